@@ -0,0 +1,40 @@
+package sink
+
+import (
+	"errors"
+
+	"github.com/markusressel/system-control/internal/audio/pipewire"
+	"github.com/markusressel/system-control/internal/configuration"
+	"github.com/spf13/cobra"
+)
+
+var preferredCmd = &cobra.Command{
+	Use:   "preferred",
+	Short: "Switch to the sink of the preferred device",
+	Long: `Switches the default audio sink and moves all existing audio streams to the sink of the device
+with the highest priority in bluetooth.preferred which is available:
+
+> system-control audio sink preferred`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := configuration.ValidateBluetooth()
+		if err != nil {
+			return err
+		}
+		preferred := configuration.CurrentConfig.Bluetooth.Preferred
+		if len(preferred) == 0 {
+			return errors.New("no preferred devices configured in bluetooth.preferred")
+		}
+
+		state := pipewire.PwDump()
+		node, err := state.FindPreferredSink(preferred)
+		if err != nil {
+			return err
+		}
+		return state.SwitchSinkTo(node)
+	},
+}
+
+func init() {
+	SinkCmd.AddCommand(preferredCmd)
+}
@@ -1,6 +1,9 @@
 package battery
 
 import (
+	"fmt"
+
+	"github.com/markusressel/system-control/internal/util"
 	"github.com/spf13/cobra"
 )
 
@@ -21,3 +24,33 @@ func init() {
 		"Battery Name",
 	)
 }
+
+// findBattery returns the battery with the given name, serial number, model or path
+func findBattery(name string) (util.BatteryInfo, error) {
+	batteries, err := util.GetBatteryList()
+	if err != nil {
+		return util.BatteryInfo{}, err
+	}
+
+	if len(batteries) <= 0 {
+		return util.BatteryInfo{}, fmt.Errorf("no batteries found")
+	}
+
+	var batteryInfo *util.BatteryInfo
+	for _, battery := range batteries {
+		if util.EqualsIgnoreCase(battery.Name, name) {
+			batteryInfo = &battery
+		} else if util.EqualsIgnoreCase(battery.SerialNumber, name) {
+			batteryInfo = &battery
+		} else if util.EqualsIgnoreCase(battery.Model, name) {
+			batteryInfo = &battery
+		} else if util.EqualsIgnoreCase(battery.Path, name) {
+			batteryInfo = &battery
+		}
+	}
+
+	if batteryInfo == nil {
+		return util.BatteryInfo{}, fmt.Errorf("no battery found matching '%s'", name)
+	}
+	return *batteryInfo, nil
+}
@@ -0,0 +1,38 @@
+package battery
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var batteryChargeBehaviourCmd = &cobra.Command{
+	Use:   "behaviour",
+	Short: "Get/Set the charge behaviour of the battery",
+	Long: `Get or set the charge behaviour of the battery, f.ex. on Framework laptops:
+  auto             charge according to the thresholds
+  inhibit-charge   do not charge while on AC
+  force-discharge  discharge the battery even while on AC`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		batteryFlag := cmd.Flag("name")
+		battery := batteryFlag.Value.String()
+
+		if len(args) > 0 {
+			return util.SetChargeBehaviour(battery, args[0])
+		}
+
+		active, available, err := util.GetChargeBehaviour(battery)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s (available: %s)\n", active, strings.Join(available, ", "))
+		return nil
+	},
+}
+
+func init() {
+	Command.AddCommand(batteryChargeBehaviourCmd)
+}
@@ -2,58 +2,100 @@ package battery
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 
+	"github.com/elliotchance/orderedmap/v2"
 	"github.com/markusressel/system-control/internal/persistence"
 	"github.com/markusressel/system-control/internal/util"
 	"github.com/spf13/cobra"
 )
 
+var startThreshold int
+var thresholdVerbose bool
+
 var batteryChargingThresholdCmd = &cobra.Command{
 	Use:   "threshold",
 	Short: "Get/Set battery charging threshold",
-	Long:  ``,
-	Args:  cobra.MaximumNArgs(1),
+	Long: `Get or set the battery levels at which charging starts and stops.
+
+The value is either the end threshold in percent or one of the charge modes:
+  full       start at 95%, stop at 100%
+  balanced   start at 75%, stop at 80%
+  longevity  start at 55%, stop at 60%
+
+Without arguments, the end threshold is printed. Use --verbose to print the backend, the start threshold
+and the charge mode as well.
+
+The start threshold is only supported by some vendors (f.ex. ThinkPad and Huawei), it can be set using --start.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		batteryFlag := cmd.Flag("name")
 		battery := batteryFlag.Value.String()
 
-		if len(args) > 0 {
-			newValueString := args[0]
-			newValue, err := strconv.Atoi(newValueString)
+		backend, err := util.DetectChargeThresholdBackend(battery)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 && !cmd.Flags().Changed("start") {
+			thresholds, err := backend.GetThresholds()
 			if err != nil {
 				return err
 			}
-			err = setBatteryThreshold(battery, newValue)
+			if thresholdVerbose {
+				printBatteryThresholds(battery, backend, thresholds)
+			} else {
+				fmt.Println(thresholds.End)
+			}
+			return nil
+		}
+
+		thresholds := util.ChargeThresholds{Start: -1}
+		if len(args) > 0 {
+			thresholds, err = parseBatteryThresholds(args[0])
 			if err != nil {
 				return err
 			}
 		} else {
-			value, err := getBatteryThreshold(battery)
+			current, err := backend.GetThresholds()
 			if err != nil {
 				return err
 			}
-			fmt.Println(value)
+			thresholds.End = current.End
 		}
 
-		return nil
+		if cmd.Flags().Changed("start") {
+			if !backend.SupportsStart() {
+				return fmt.Errorf("%s does not support a start threshold", backend.Name())
+			}
+			thresholds.Start = startThreshold
+		}
+		if !backend.SupportsStart() {
+			thresholds.Start = -1
+		}
+		return setBatteryThresholds(backend, thresholds)
 	},
 }
 
 var batteryChargingThresholdSaveCmd = &cobra.Command{
 	Use:   "save",
 	Short: "Save the current battery charging threshold on disk",
-	Long:  ``,
+	Long: `Save the current battery charging threshold in /var/lib/system-control, which is shared by all users
+and read by "battery threshold restore" (f.ex. in the unit printed by "battery threshold install").`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		batteryFlag := cmd.Flag("name")
 		battery := batteryFlag.Value.String()
 
-		current, err := getBatteryThreshold(battery)
+		backend, err := util.DetectChargeThresholdBackend(battery)
 		if err != nil {
 			return err
 		}
-
-		return persistence.SaveInt(battery+"_charge_control_end_threshold", current)
+		current, err := backend.GetThresholds()
+		if err != nil {
+			return err
+		}
+		return persistence.SaveSystemStruct(chargeThresholdsKey(battery), current)
 	},
 }
 
@@ -65,31 +107,70 @@ var batteryChargingThresholdRestoreCmd = &cobra.Command{
 		batteryFlag := cmd.Flag("name")
 		battery := batteryFlag.Value.String()
 
-		value, err := persistence.ReadInt(battery + "_charge_control_end_threshold")
+		thresholds, err := readSavedBatteryThresholds(battery)
 		if err != nil {
 			return err
 		}
-		return setBatteryThreshold(battery, int(value))
+		backend, err := util.DetectChargeThresholdBackend(battery)
+		if err != nil {
+			return err
+		}
+		if !backend.SupportsStart() {
+			thresholds.Start = -1
+		}
+		return setBatteryThresholds(backend, thresholds)
 	},
 }
 
-func setBatteryThreshold(battery string, value int) error {
-	file := "/sys/class/power_supply/" + battery + "/charge_control_end_threshold"
-	return util.WriteIntToFile(value, file)
+// parseBatteryThresholds parses either the name of a charge mode or an end threshold
+func parseBatteryThresholds(value string) (util.ChargeThresholds, error) {
+	if slices.Contains(util.ChargeModes, value) {
+		return util.GetChargeModeThresholds(value)
+	}
+	end, err := strconv.Atoi(value)
+	if err != nil {
+		return util.ChargeThresholds{}, fmt.Errorf("expected a threshold or one of %v, got '%s'", util.ChargeModes, value)
+	}
+	return util.ChargeThresholds{Start: -1, End: end}, nil
+}
+
+// readSavedBatteryThresholds reads the thresholds saved for the given battery
+func readSavedBatteryThresholds(battery string) (util.ChargeThresholds, error) {
+	thresholds := util.ChargeThresholds{Start: -1}
+	err := persistence.ReadSystemStruct(chargeThresholdsKey(battery), &thresholds)
+	return thresholds, err
 }
 
-func getBatteryThreshold(battery string) (int, error) {
-	file := "/sys/class/power_supply/" + battery + "/charge_control_end_threshold"
+// chargeThresholdsKey returns the persistence key of the thresholds of the given battery
+func chargeThresholdsKey(battery string) string {
+	return battery + "_charge_thresholds"
+}
 
-	value, err := util.ReadIntFromFile(file)
+func setBatteryThresholds(backend util.ChargeThresholdBackend, thresholds util.ChargeThresholds) error {
+	err := thresholds.Validate()
 	if err != nil {
-		return -1, err
+		return err
+	}
+	return backend.SetThresholds(thresholds)
+}
+
+func printBatteryThresholds(battery string, backend util.ChargeThresholdBackend, thresholds util.ChargeThresholds) {
+	properties := orderedmap.NewOrderedMap[string, string]()
+	properties.Set("Backend", backend.Name())
+	if thresholds.Start >= 0 {
+		properties.Set("Start", fmt.Sprintf("%d%%", thresholds.Start))
+	}
+	properties.Set("End", fmt.Sprintf("%d%%", thresholds.End))
+	if mode := thresholds.Mode(); mode != "" {
+		properties.Set("Mode", mode)
 	}
-	return int(value), err
+	util.PrintFormattedTableOrdered(battery, properties)
 }
 
 func init() {
 	Command.AddCommand(batteryChargingThresholdCmd)
+	batteryChargingThresholdCmd.Flags().IntVarP(&startThreshold, "start", "s", 0, "Battery level (in percent) below which charging starts")
+	batteryChargingThresholdCmd.Flags().BoolVarP(&thresholdVerbose, "verbose", "v", false, "Print the backend, start threshold and charge mode as well")
 
 	batteryChargingThresholdCmd.AddCommand(batteryChargingThresholdSaveCmd)
 	batteryChargingThresholdCmd.AddCommand(batteryChargingThresholdRestoreCmd)
@@ -0,0 +1,52 @@
+package battery
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var installUdev bool
+
+var batteryChargingThresholdInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Print a systemd unit which restores the saved battery charging threshold",
+	Long: `Print a systemd unit which restores the threshold saved using "battery threshold save" on boot and after resume.
+
+The unit runs "battery threshold restore", so a threshold saved later is applied without installing the unit again.
+
+Install it using:
+  system-control battery threshold install | sudo tee /etc/systemd/system/battery-threshold.service
+  sudo systemctl enable battery-threshold.service
+
+With --udev, a udev rule is printed instead, which applies the threshold when the battery is added (f.ex. on boot).
+It has to be installed to /etc/udev/rules.d/, but it is not applied after resume.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		batteryFlag := cmd.Flag("name")
+		battery := batteryFlag.Value.String()
+
+		_, err := readSavedBatteryThresholds(battery)
+		if err != nil {
+			return fmt.Errorf("no saved threshold found, save it using \"battery threshold save\" first: %w", err)
+		}
+		executable, err := os.Executable()
+		if err != nil {
+			return err
+		}
+
+		if installUdev {
+			fmt.Print(util.ChargeThresholdUdevRule(executable, battery))
+		} else {
+			fmt.Print(util.ChargeThresholdSystemdUnit(executable, battery))
+		}
+		return nil
+	},
+}
+
+func init() {
+	batteryChargingThresholdCmd.AddCommand(batteryChargingThresholdInstallCmd)
+	batteryChargingThresholdInstallCmd.Flags().BoolVar(&installUdev, "udev", false, "Print a udev rule instead of a systemd unit")
+}
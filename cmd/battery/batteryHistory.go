@@ -0,0 +1,122 @@
+package battery
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/markusressel/system-control/internal/configuration"
+	"github.com/markusressel/system-control/internal/persistence"
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var historyThreshold float64
+
+var batteryHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Record and show the health of the battery over time",
+	Long:  ``,
+}
+
+var batteryHistoryRecordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Append the current health of the battery to its history",
+	Long: `Append the current energy when fully charged, cycle count, capacity and degradation of the battery
+to its history file. Run this periodically, f.ex. daily using a systemd timer or cron.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		battery, err := findBattery(Name)
+		if err != nil {
+			return err
+		}
+		sample, err := battery.GetHistorySample(time.Now())
+		if err != nil {
+			return err
+		}
+		return util.AppendBatteryHistory(getBatteryHistoryFile(battery), sample)
+	},
+}
+
+var batteryHistoryShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the trend of the battery health",
+	Long: `Show how the health of the battery changed since the first recorded sample, and the projected date
+at which its capacity falls below the threshold configured in battery.history.threshold.
+
+Only samples of the current battery are considered, a replaced battery is detected by its serial number.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		threshold := historyThreshold
+		if !cmd.Flags().Changed("threshold") {
+			err := configuration.ValidateBattery()
+			if err != nil {
+				return err
+			}
+			threshold = configuration.CurrentConfig.Battery.History.Threshold
+		}
+		if threshold <= 0 || threshold >= 100 {
+			return errors.New("threshold must be between 0 and 100")
+		}
+
+		battery, err := findBattery(Name)
+		if err != nil {
+			return err
+		}
+		samples, err := util.ReadBatteryHistory(getBatteryHistoryFile(battery))
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no history recorded for battery %s, record it using \"battery history record\"", battery.Name)
+		} else if err != nil {
+			return err
+		}
+
+		samples = util.CurrentBatterySamples(samples)
+		trend, err := util.GetBatteryHistoryTrend(samples)
+		if err != nil {
+			return err
+		}
+		printBatteryHistoryTrend(battery, len(samples), trend, threshold)
+		return nil
+	},
+}
+
+func getBatteryHistoryFile(battery util.BatteryInfo) string {
+	return filepath.Join(persistence.BaseDir, battery.Name+"_history.csv")
+}
+
+func printBatteryHistoryTrend(battery util.BatteryInfo, sampleCount int, trend util.BatteryHistoryTrend, threshold float64) {
+	first := trend.First
+	last := trend.Last
+	fmt.Printf("%s (%d samples from %s to %s)\n", battery.Name, sampleCount, first.Time.Format(time.DateOnly), last.Time.Format(time.DateOnly))
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "  \tFirst\tLast\tChange\t")
+	_, _ = fmt.Fprintf(w, "  Energy Full\t%.1f Wh\t%.1f Wh\t%+.1f Wh\t\n", first.EnergyFull, last.EnergyFull, last.EnergyFull-first.EnergyFull)
+	if first.CycleCount >= 0 && last.CycleCount >= 0 {
+		_, _ = fmt.Fprintf(w, "  Cycle Count\t%d\t%d\t%+d\t\n", first.CycleCount, last.CycleCount, last.CycleCount-first.CycleCount)
+	}
+	_, _ = fmt.Fprintf(w, "  Capacity\t%.1f%%\t%.1f%%\t%+.1f%%\t\n", first.Capacity, last.Capacity, last.Capacity-first.Capacity)
+	_, _ = fmt.Fprintf(w, "  Degradation\t%.1f%%\t%.1f%%\t%+.1f%%\t\n", first.Degradation, last.Degradation, last.Degradation-first.Degradation)
+	_ = w.Flush()
+
+	fmt.Printf("Trend: %+.1f%% capacity per year\n", trend.CapacityPerYear)
+	date, ok := trend.ProjectCapacityDate(threshold)
+	switch {
+	case !ok:
+		fmt.Printf("Capacity is not decreasing, no projection for %.0f%% possible\n", threshold)
+	case last.Capacity < threshold:
+		fmt.Printf("Capacity is already below %.0f%%\n", threshold)
+	default:
+		fmt.Printf("Capacity falls below %.0f%% around %s\n", threshold, date.Format(time.DateOnly))
+	}
+}
+
+func init() {
+	Command.AddCommand(batteryHistoryCmd)
+	batteryHistoryCmd.AddCommand(batteryHistoryRecordCmd)
+	batteryHistoryCmd.AddCommand(batteryHistoryShowCmd)
+	batteryHistoryShowCmd.Flags().Float64VarP(&historyThreshold, "threshold", "t", 80, "Capacity (in percent) for which the date it is reached is projected, overrides battery.history.threshold")
+}
@@ -17,34 +17,11 @@ var batteryRemainingCmd = &cobra.Command{
 
 		template := "%hours%:%minutes%"
 
-		batteries, err := util.GetBatteryList()
+		batteryInfoNonNull, err := findBattery(Name)
 		if err != nil {
 			return err
 		}
 
-		if len(batteries) <= 0 {
-			return fmt.Errorf("no batteries found")
-		}
-
-		var batteryInfo *util.BatteryInfo
-		for _, battery := range batteries {
-			if util.EqualsIgnoreCase(battery.Name, Name) {
-				batteryInfo = &battery
-			} else if util.EqualsIgnoreCase(battery.SerialNumber, Name) {
-				batteryInfo = &battery
-			} else if util.EqualsIgnoreCase(battery.Model, Name) {
-				batteryInfo = &battery
-			} else if util.EqualsIgnoreCase(battery.Path, Name) {
-				batteryInfo = &battery
-			}
-		}
-
-		if batteryInfo == nil {
-			return fmt.Errorf("no battery found matching '%s'", Name)
-		}
-
-		batteryInfoNonNull := *batteryInfo
-
 		// get value
 		charging, err := batteryInfoNonNull.IsCharging()
 		if err != nil {
@@ -8,17 +8,33 @@ import (
 	"github.com/elliotchance/orderedmap/v2"
 	"github.com/lithammer/fuzzysearch/fuzzy"
 	"github.com/markusressel/system-control/internal/bluetooth"
+	"github.com/markusressel/system-control/internal/configuration"
 	"github.com/markusressel/system-control/internal/util"
 	"github.com/spf13/cobra"
 )
 
 var Name string
+var adapterName string
 
 var Command = &cobra.Command{
 	Use:              "bluetooth",
 	Short:            "Control Bluetooth Devices",
 	Long:             ``,
 	TraverseChildren: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// cobra only runs the closest persistent pre-run hook, so the one of the root command has to be run explicitly
+		if root := cmd.Root(); root != cmd && root.PersistentPreRunE != nil {
+			err := root.PersistentPreRunE(cmd, args)
+			if err != nil {
+				return err
+			}
+		}
+		err := configuration.ValidateBluetooth()
+		if err != nil {
+			return err
+		}
+		return bluetooth.SelectAdapter(adapterName)
+	},
 }
 
 func init() {
@@ -28,6 +44,12 @@ func init() {
 		"",
 		"Device Name",
 	)
+	Command.PersistentFlags().StringVarP(
+		&adapterName,
+		"adapter", "a",
+		"",
+		"Bluetooth adapter to use, by name (f.ex. hci1) or address (default: first adapter)",
+	)
 }
 
 func printBluetoothDevices(devices []bluetooth.BluetoothDevice) {
@@ -51,6 +73,9 @@ func printBluetoothDevice(device bluetooth.BluetoothDevice) {
 	if device.BatteryPercentage != nil {
 		properties.Set("Battery", fmt.Sprintf("%v%%", *device.BatteryPercentage))
 	}
+	if device.RSSI != nil {
+		properties.Set("RSSI", fmt.Sprintf("%d dBm", *device.RSSI))
+	}
 
 	util.PrintFormattedTableOrdered(device.Name, properties)
 }
@@ -84,6 +109,43 @@ func findBluetoothDeviceFuzzy(name string, devices []bluetooth.BluetoothDevice)
 	return result
 }
 
+// findBluetoothDevice returns the single device matching the given name or address
+func findBluetoothDevice(name string) (bluetooth.BluetoothDevice, error) {
+	devices, err := bluetooth.GetBluetoothDevices()
+	if err != nil {
+		return bluetooth.BluetoothDevice{}, err
+	}
+
+	matchingDevices := findBluetoothDeviceFuzzy(name, devices)
+	if len(matchingDevices) == 1 {
+		return matchingDevices[0], nil
+	} else if len(matchingDevices) > 1 {
+		deviceNames := createDeviceNameList(matchingDevices)
+		return bluetooth.BluetoothDevice{}, fmt.Errorf("multiple matching devices found: %v", deviceNames)
+	}
+	return bluetooth.BluetoothDevice{}, fmt.Errorf("device not found: %v", name)
+}
+
+// findPreferredDevices returns the best matching device of each of the given names or addresses, keeping their order.
+// Names without a match and blocked devices are skipped.
+func findPreferredDevices(preferred []string, devices []bluetooth.BluetoothDevice) []bluetooth.BluetoothDevice {
+	result := make([]bluetooth.BluetoothDevice, 0)
+	seen := map[string]bool{}
+	for _, name := range preferred {
+		matchingDevices := findBluetoothDeviceFuzzy(name, devices)
+		if len(matchingDevices) == 0 {
+			continue
+		}
+		device := matchingDevices[0]
+		if device.Blocked || seen[device.Address] {
+			continue
+		}
+		seen[device.Address] = true
+		result = append(result, device)
+	}
+	return result
+}
+
 func createDeviceNameList(devices []bluetooth.BluetoothDevice) []string {
 	return util.MapFunc(devices, func(device bluetooth.BluetoothDevice) string {
 		return fmt.Sprintf("%s (%s)", device.Name, device.Address)
@@ -0,0 +1,45 @@
+package bluetooth
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/elliotchance/orderedmap/v2"
+	"github.com/markusressel/system-control/internal/bluetooth"
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var bluetoothAdaptersCmd = &cobra.Command{
+	Use:   "adapters",
+	Short: "List all Bluetooth Adapters",
+	Long:  ``,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		adapters, err := bluetooth.GetBluetoothAdapters()
+		if err != nil {
+			return err
+		}
+
+		for i, adapter := range adapters {
+			properties := orderedmap.NewOrderedMap[string, string]()
+			properties.Set("Address", adapter.Address)
+			properties.Set("Alias", adapter.Alias)
+			properties.Set("Powered", strconv.FormatBool(adapter.Powered))
+			properties.Set("Discoverable", strconv.FormatBool(adapter.Discoverable))
+			properties.Set("Pairable", strconv.FormatBool(adapter.Pairable))
+			properties.Set("Discovering", strconv.FormatBool(adapter.Discovering))
+
+			util.PrintFormattedTableOrdered(adapter.Name, properties)
+
+			if i < len(adapters)-1 {
+				fmt.Println()
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	Command.AddCommand(bluetoothAdaptersCmd)
+}
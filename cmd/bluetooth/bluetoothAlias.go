@@ -0,0 +1,29 @@
+package bluetooth
+
+import (
+	"github.com/markusressel/system-control/internal/bluetooth"
+	"github.com/spf13/cobra"
+)
+
+var bluetoothAliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Rename a Bluetooth Device",
+	Long: `Set the alias of a Bluetooth Device, which is shown instead of its name.
+If no name is given, the alias is reset to the name of the device.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		device, err := findBluetoothDevice(args[0])
+		if err != nil {
+			return err
+		}
+		alias := ""
+		if len(args) > 1 {
+			alias = args[1]
+		}
+		return bluetooth.SetBluetoothDeviceAlias(device, alias)
+	},
+}
+
+func init() {
+	Command.AddCommand(bluetoothAliasCmd)
+}
@@ -0,0 +1,86 @@
+package bluetooth
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/markusressel/system-control/internal/audio/pipewire"
+	"github.com/spf13/cobra"
+)
+
+var audioCodec string
+var audioSetDefault bool
+
+var bluetoothAudioCmd = &cobra.Command{
+	Use:   "audio",
+	Short: "Show or switch the audio profile of a Bluetooth Device",
+	Long: `Show or switch the audio profile and codec of a Bluetooth Device.
+
+Without a mode, the available profiles and codecs of the device are listed. The mode is one of:
+  a2dp  high quality playback without microphone
+  hfp   headset with microphone and low audio quality (HSP/HFP)
+  auto  the profile with the highest priority, usually A2DP with the best codec`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bluetoothDevice, err := findBluetoothDevice(args[0])
+		if err != nil {
+			return err
+		}
+
+		state := pipewire.PwDump()
+		device, err := state.FindBluetoothDevice(bluetoothDevice.Address)
+		if err != nil {
+			return fmt.Errorf("%w, is it connected?", err)
+		}
+
+		if len(args) < 2 {
+			printBluetoothAudioProfiles(device)
+			return nil
+		}
+
+		profile, err := device.FindBluetoothProfile(args[1], audioCodec)
+		if err != nil {
+			return err
+		}
+		err = device.SetProfileByName(profile.Name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Switched %s to %s\n", bluetoothDevice.Name, profile.Description)
+
+		if audioSetDefault {
+			return pipewire.SetBluetoothDefaults(bluetoothDevice.Address, 5*time.Second)
+		}
+		return nil
+	},
+}
+
+func printBluetoothAudioProfiles(device pipewire.InterfaceDevice) {
+	var activeProfile string
+	if len(device.Info.Params.Profile) > 0 {
+		activeProfile = device.Info.Params.Profile[0].Name
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "  Mode\tCodec\tProfile\t")
+	for _, profile := range device.GetBluetoothProfiles() {
+		name := profile.Name
+		if profile.Name == activeProfile {
+			name += " *"
+		}
+		codec := profile.Codec
+		if codec == "" {
+			codec = "-"
+		}
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\t\n", profile.Mode, codec, name)
+	}
+	_ = w.Flush()
+}
+
+func init() {
+	Command.AddCommand(bluetoothAudioCmd)
+	bluetoothAudioCmd.Flags().StringVarP(&audioCodec, "codec", "c", "", "Codec to use, f.ex. SBC, AAC, LDAC or aptX (default: best available codec)")
+	bluetoothAudioCmd.Flags().BoolVarP(&audioSetDefault, "default", "d", false, "Make the device the default sink and source")
+}
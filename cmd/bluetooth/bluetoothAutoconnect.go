@@ -0,0 +1,55 @@
+package bluetooth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/markusressel/system-control/internal/bluetooth"
+	"github.com/markusressel/system-control/internal/configuration"
+	"github.com/spf13/cobra"
+)
+
+var bluetoothAutoconnectCmd = &cobra.Command{
+	Use:   "autoconnect",
+	Short: "Automatically reconnect favorite Bluetooth Devices",
+	Long: `Keep the favorite Bluetooth Devices configured in bluetooth.autoconnect.devices connected.
+
+Favorites are reconnected as soon as the adapter is powered on or they come into range.
+Reconnecting devices which are out of range is retried in bluetooth.autoconnect.interval.
+Only paired devices which are not blocked are connected. If a connected device is one of the audio devices
+in bluetooth.preferred, the sink of the preferred device of the highest priority which is available is made the default one.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config := configuration.CurrentConfig.Bluetooth.Autoconnect
+		if len(config.Devices) == 0 {
+			return errors.New("no favorite devices configured in bluetooth.autoconnect.devices")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		logMessage := func(message string) {
+			fmt.Printf("[%s] %s\n", time.Now().Format("2006-01-02 15:04:05"), message)
+		}
+
+		fmt.Printf("Keeping %d favorite devices connected...\n", len(config.Devices))
+		return bluetooth.AutoConnect(ctx, config.Devices, config.Interval, logMessage, func(device bluetooth.BluetoothDevice) {
+			// waiting for the sink of the device must not delay reconnecting other devices
+			go func() {
+				err := selectPreferredSink(device)
+				if err != nil {
+					logMessage(fmt.Sprintf("Failed to select the preferred sink: %v", err))
+				}
+			}()
+		})
+	},
+}
+
+func init() {
+	Command.AddCommand(bluetoothAutoconnectCmd)
+}
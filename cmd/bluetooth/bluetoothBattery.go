@@ -0,0 +1,150 @@
+package bluetooth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/markusressel/system-control/internal/bluetooth"
+	"github.com/markusressel/system-control/internal/configuration"
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var batteryThreshold int
+var batteryInterval time.Duration
+
+var bluetoothBatteryCmd = &cobra.Command{
+	Use:   "battery",
+	Short: "Show the battery level of connected Bluetooth Devices",
+	Long: `Show the battery level of connected Bluetooth Devices.
+
+Batteries reported by BlueZ are merged with device batteries known to the kernel (f.ex. Logitech HID++)
+and upower, so each device is only listed once. If a device is given, only batteries whose name
+contains it or whose address matches it are shown.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		batteries, err := bluetooth.GetPeripheralBatteries()
+		if err != nil {
+			if batteries == nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "WARNING: %v\n", err)
+		}
+		if len(args) > 0 {
+			batteries = filterBatteries(batteries, args[0])
+			if len(batteries) == 0 {
+				return fmt.Errorf("no battery found for device %s", args[0])
+			}
+		}
+		printBatteries(batteries)
+		return nil
+	},
+}
+
+var bluetoothBatteryWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Send a notification when the battery of a Bluetooth Device is low",
+	Long: `Periodically check the battery levels of connected Bluetooth Devices and send a desktop notification
+when one of them drops below the threshold configured in bluetooth.battery.threshold.
+
+A device is notified only once, until its battery has been charged above the threshold again.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config := configuration.CurrentConfig.Bluetooth.Battery
+		if cmd.Flags().Changed("threshold") {
+			config.Threshold = batteryThreshold
+		}
+		if cmd.Flags().Changed("interval") {
+			config.Interval = batteryInterval
+		}
+		if config.Threshold < 0 || config.Threshold > 100 {
+			return errors.New("threshold must be between 0 and 100")
+		}
+		if config.Interval <= 0 {
+			return errors.New("interval must be positive")
+		}
+
+		monitor := bluetooth.BatteryMonitor{Threshold: int64(config.Threshold)}
+		notificationIds := map[string]uint32{}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		ticker := time.NewTicker(config.Interval)
+		defer ticker.Stop()
+
+		fmt.Printf("Watching battery levels (threshold: %d%%)...\n", config.Threshold)
+		for {
+			timestamp := time.Now().Format("2006-01-02 15:04:05")
+			batteries, err := bluetooth.GetPeripheralBatteries()
+			if err != nil {
+				fmt.Printf("[%s] ERROR: %v\n", timestamp, err)
+			}
+			// keep the state of the monitor until a source is available again
+			var dropped []bluetooth.PeripheralBattery
+			if batteries != nil {
+				dropped = monitor.Update(batteries)
+			}
+			for _, battery := range dropped {
+				fmt.Printf("[%s] battery of %s dropped to %d%%\n", timestamp, battery.Name, battery.Percentage)
+				id, err := util.SendNotification(
+					notificationIds[battery.Name],
+					"battery-caution",
+					"Low battery",
+					fmt.Sprintf("%s: %d%%", battery.Name, battery.Percentage),
+					util.NotificationUrgencyCritical,
+				)
+				if err != nil {
+					fmt.Printf("[%s] ERROR: failed to send notification: %v\n", timestamp, err)
+					continue
+				}
+				notificationIds[battery.Name] = id
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+func filterBatteries(batteries []bluetooth.PeripheralBattery, device string) []bluetooth.PeripheralBattery {
+	result := make([]bluetooth.PeripheralBattery, 0)
+	for _, battery := range batteries {
+		if util.ContainsIgnoreCase(battery.Name, device) || util.EqualsIgnoreCase(battery.Address, device) {
+			result = append(result, battery)
+		}
+	}
+	return result
+}
+
+func printBatteries(batteries []bluetooth.PeripheralBattery) {
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 2, ' ', 0)
+	for _, battery := range batteries {
+		percentage := "?"
+		if battery.Percentage >= 0 {
+			percentage = fmt.Sprintf("%d%%", battery.Percentage)
+		}
+		status := battery.Status
+		if status == "" {
+			status = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", battery.Name, percentage, status, battery.Source)
+	}
+	_ = w.Flush()
+}
+
+func init() {
+	Command.AddCommand(bluetoothBatteryCmd)
+	bluetoothBatteryCmd.AddCommand(bluetoothBatteryWatchCmd)
+	bluetoothBatteryWatchCmd.Flags().IntVarP(&batteryThreshold, "threshold", "t", 20, "Battery level (in percent) below which a notification is sent, overrides bluetooth.battery.threshold")
+	bluetoothBatteryWatchCmd.Flags().DurationVar(&batteryInterval, "interval", 5*time.Minute, "Interval in which the battery levels are checked, overrides bluetooth.battery.interval")
+}
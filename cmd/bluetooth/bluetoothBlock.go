@@ -0,0 +1,39 @@
+package bluetooth
+
+import (
+	"github.com/markusressel/system-control/internal/bluetooth"
+	"github.com/spf13/cobra"
+)
+
+var bluetoothBlockCmd = &cobra.Command{
+	Use:   "block",
+	Short: "Block a Bluetooth Device",
+	Long:  `Block a Bluetooth Device, which disconnects it and rejects all further connections.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		device, err := findBluetoothDevice(args[0])
+		if err != nil {
+			return err
+		}
+		return bluetooth.SetBluetoothDeviceBlocked(device, true)
+	},
+}
+
+var bluetoothUnblockCmd = &cobra.Command{
+	Use:   "unblock",
+	Short: "Unblock a Bluetooth Device",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		device, err := findBluetoothDevice(args[0])
+		if err != nil {
+			return err
+		}
+		return bluetooth.SetBluetoothDeviceBlocked(device, false)
+	},
+}
+
+func init() {
+	Command.AddCommand(bluetoothBlockCmd)
+	Command.AddCommand(bluetoothUnblockCmd)
+}
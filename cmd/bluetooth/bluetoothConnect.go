@@ -1,42 +1,123 @@
 package bluetooth
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/markusressel/system-control/internal/audio/pipewire"
 	"github.com/markusressel/system-control/internal/bluetooth"
+	"github.com/markusressel/system-control/internal/configuration"
 	"github.com/spf13/cobra"
 )
 
+var connectBest bool
+var connectTimeout time.Duration
+
 var bluetoothConnectCmd = &cobra.Command{
 	Use:   "connect",
 	Short: "Connect to a Bluetooth Device",
-	Long:  ``,
-	Args:  cobra.ExactArgs(1),
+	Long: `Connect to a Bluetooth Device.
+
+If the device is one of the audio devices in bluetooth.preferred, the sink of the preferred device of the
+highest priority which is available is made the default one.
+
+With --best, the device of the highest priority in bluetooth.preferred which is in range is connected instead.
+All other connected devices of the same class (f.ex. other headsets) are disconnected, and the sink
+and source of an audio device are made the default ones.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		deviceName := args[0]
+		if connectBest {
+			if len(args) > 0 {
+				return errors.New("a device can not be given together with --best")
+			}
+			return connectBestDevice()
+		}
+		if len(args) == 0 {
+			return errors.New("no device given")
+		}
 
-		devices, err := bluetooth.GetBluetoothDevices()
+		device, err := findBluetoothDevice(args[0])
 		if err != nil {
 			return err
 		}
+		err = bluetooth.ConnectToBluetoothDevice(device)
+		if err != nil {
+			return err
+		}
+		return selectPreferredSink(device)
+	},
+}
 
-		matchingDevices := findBluetoothDeviceFuzzy(deviceName, devices)
+// selectPreferredSink makes the sink of the preferred device of the highest priority which is available
+// the default one, if the given (newly connected) device is one of the preferred audio devices
+func selectPreferredSink(device bluetooth.BluetoothDevice) error {
+	preferred := configuration.CurrentConfig.Bluetooth.Preferred
+	if len(findPreferredDevices(preferred, []bluetooth.BluetoothDevice{device})) == 0 {
+		return nil
+	}
+	if class, ok := device.MajorClass(); !ok || class != bluetooth.MajorClassAudioVideo {
+		return nil
+	}
+	return pipewire.SwitchToPreferredSink(device.Address, preferred, 5*time.Second)
+}
 
-		if len(matchingDevices) == 1 {
-			err := bluetooth.ConnectToBluetoothDevice(matchingDevices[0])
-			if err != nil {
-				return err
-			}
-			return nil
-		} else if len(matchingDevices) > 1 {
-			deviceNames := createDeviceNameList(matchingDevices)
-			return fmt.Errorf("multiple matching devices found: %v", deviceNames)
+func connectBestDevice() error {
+	preferred := configuration.CurrentConfig.Bluetooth.Preferred
+	if len(preferred) == 0 {
+		return errors.New("no preferred devices configured in bluetooth.preferred")
+	}
+	if connectTimeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+
+	devices, err := bluetooth.GetBluetoothDevices()
+	if err != nil {
+		return err
+	}
+	candidates := findPreferredDevices(preferred, devices)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+
+	best, err := bluetooth.FindBestDevice(ctx, candidates)
+	if err != nil {
+		return err
+	}
+
+	if !best.Connected {
+		fmt.Printf("Connecting %s (%s)...\n", best.Name, best.Address)
+		err = bluetooth.ConnectToBluetoothDevice(best)
+		if err != nil {
+			return err
 		}
+	}
 
-		return fmt.Errorf("device not found: %v", deviceName)
-	},
+	for _, device := range devices {
+		if !device.Connected || device.Address == best.Address || !device.SameClass(best) {
+			continue
+		}
+		fmt.Printf("Disconnecting %s (%s)\n", device.Name, device.Address)
+		err = bluetooth.DisconnectBluetoothDevice(device)
+		if err != nil {
+			return err
+		}
+	}
+
+	if class, ok := best.MajorClass(); ok && class == bluetooth.MajorClassAudioVideo {
+		return pipewire.SetBluetoothDefaults(best.Address, 5*time.Second)
+	}
+	return nil
 }
 
 func init() {
 	Command.AddCommand(bluetoothConnectCmd)
+	bluetoothConnectCmd.Flags().BoolVarP(&connectBest, "best", "b", false, "Connect the preferred device of the highest priority which is in range")
+	bluetoothConnectCmd.Flags().DurationVarP(&connectTimeout, "timeout", "t", 10*time.Second, "Maximum duration of the discovery of preferred devices with --best")
 }
@@ -1,6 +1,7 @@
 package bluetooth
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -10,12 +11,19 @@ import (
 
 var autoConnect bool
 var removeExisting bool
+var agentCapability string
+var autoAccept bool
+var pairTimeout time.Duration
 
 var bluetoothPairCmd = &cobra.Command{
 	Use:   "pair",
 	Short: "Pair a Bluetooth Device",
-	Long:  ``,
-	Args:  cobra.ExactArgs(1),
+	Long: `Pair a Bluetooth Device.
+
+A pairing agent is registered for the duration of the command, which prints passkeys and
+asks for confirmation or PIN codes on the terminal, as required by f.ex. keyboards.
+Use --auto-accept to accept all requests without asking, for headless pairing.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		deviceName := args[0]
 
@@ -34,42 +42,62 @@ var bluetoothPairCmd = &cobra.Command{
 			}
 		}
 
+		stopAgent, err := bluetooth.StartPairingAgent(agentCapability, autoAccept)
+		if err != nil {
+			return fmt.Errorf("failed to start pairing agent: %v", err)
+		}
 		defer func() {
-			err := bluetooth.SetBluetoothScan(false)
+			err := stopAgent()
 			if err != nil {
 				fmt.Println(err.Error())
 			}
 		}()
 
-		err := bluetooth.SetBluetoothScan(true)
+		matches := func(device bluetooth.BluetoothDevice) bool {
+			return device.Name == deviceName || device.Address == deviceName
+		}
+
+		// devices known to BlueZ can be paired right away, all others have to be discovered first
+		var target *bluetooth.BluetoothDevice
+		devices, err := bluetooth.GetBluetoothDevices()
 		if err != nil {
-			return fmt.Errorf("failed to start Bluetooth scan: %v", err)
+			return err
+		}
+		for _, device := range devices {
+			if matches(device) {
+				target = &device
+				break
+			}
 		}
 
-		startTime := time.Now()
-		// search for device in loop with timeout
-		for time.Now().Sub(startTime) < 30*time.Second {
-			devices, err := bluetooth.GetBluetoothDevices()
+		if target == nil {
+			fmt.Printf("Searching for %s...\n", deviceName)
+			ctx, cancel := context.WithTimeout(context.Background(), pairTimeout)
+			defer cancel()
+			err = bluetooth.DiscoverBluetoothDevices(ctx, func(device bluetooth.BluetoothDevice) bool {
+				if matches(device) {
+					target = &device
+					return true
+				}
+				return false
+			})
 			if err != nil {
-				return err
+				return fmt.Errorf("failed to start Bluetooth scan: %v", err)
 			}
-			for _, device := range devices {
-				if device.Name == deviceName || device.Address == deviceName {
-					err := bluetooth.PairBluetoothDevice(device)
+		}
 
-					if err != nil && autoConnect {
-						fmt.Printf("Failed to pair device %s: %v\n", deviceName, err)
-					} else if autoConnect {
-						err = bluetooth.ConnectToBluetoothDevice(device)
-						if err != nil {
-							fmt.Printf("Failed to connect to device %s after pairing: %v\n", deviceName, err)
-						}
-					}
-					return err
+		if target != nil {
+			err := bluetooth.PairBluetoothDevice(*target)
+
+			if err != nil && autoConnect {
+				fmt.Printf("Failed to pair device %s: %v\n", deviceName, err)
+			} else if autoConnect {
+				err = bluetooth.ConnectToBluetoothDevice(*target)
+				if err != nil {
+					fmt.Printf("Failed to connect to device %s after pairing: %v\n", deviceName, err)
 				}
 			}
-
-			time.Sleep(1 * time.Second)
+			return err
 		}
 
 		return fmt.Errorf("device not found: %v", deviceName)
@@ -80,4 +108,7 @@ func init() {
 	Command.AddCommand(bluetoothPairCmd)
 	bluetoothPairCmd.Flags().BoolVarP(&autoConnect, "connect", "c", false, "Automatically connect after pairing")
 	bluetoothPairCmd.Flags().BoolVarP(&removeExisting, "remove-existing", "r", false, "Remove existing device (if it exists) before pairing")
+	bluetoothPairCmd.Flags().StringVar(&agentCapability, "capability", bluetooth.AgentCapabilityKeyboardDisplay, "Capability of the pairing agent (NoInputNoOutput, DisplayYesNo or KeyboardDisplay)")
+	bluetoothPairCmd.Flags().BoolVarP(&autoAccept, "auto-accept", "y", false, "Accept all pairing requests without asking")
+	bluetoothPairCmd.Flags().DurationVarP(&pairTimeout, "timeout", "t", 30*time.Second, "Time to wait for the device to be discovered")
 }
@@ -1,43 +1,71 @@
 package bluetooth
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/markusressel/system-control/internal/bluetooth"
-	"github.com/markusressel/system-control/internal/util"
 	"github.com/spf13/cobra"
 )
 
+var scanDuration time.Duration
+
 var bluetoothScanCmd = &cobra.Command{
 	Use:   "scan",
 	Short: "Scan for available Bluetooth Devices",
-	Long:  ``,
-	Args:  cobra.NoArgs,
-	RunE: func(cmd *cobra.Command, args []string) (err error) {
-		defer func() {
-			if scanErr := bluetooth.SetBluetoothScan(false); scanErr != nil {
-				println("Failed to turn off Bluetooth scanning")
-			}
-		}()
-		err = bluetooth.SetBluetoothScan(true)
-		if err != nil {
-			return err
+	Long: `Scan for available Bluetooth Devices.
+
+Devices are printed with their signal strength (RSSI) as soon as they are discovered.
+Already paired or connected devices are not shown.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if scanDuration <= 0 {
+			return errors.New("duration must be positive")
 		}
 
-		println("Scanning for Bluetooth Devices...")
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		ctx, cancel := context.WithTimeout(ctx, scanDuration)
+		defer cancel()
+
+		fmt.Printf("Scanning for Bluetooth Devices for %s...\n", scanDuration)
 
-		devices, err := bluetooth.GetBluetoothDevices()
+		found := 0
+		err := bluetooth.DiscoverBluetoothDevices(ctx, func(device bluetooth.BluetoothDevice) bool {
+			if device.Connected || device.Paired {
+				return false
+			}
+			printDiscoveredDevice(device)
+			found++
+			return false
+		})
 		if err != nil {
 			return err
 		}
-		availableDevices := util.FilterFunc(devices, func(device bluetooth.BluetoothDevice) bool {
-			return !device.Connected && !device.Paired
-		})
-
-		printBluetoothDevices(availableDevices)
 
+		fmt.Printf("Found %d devices\n", found)
 		return nil
 	},
 }
 
+func printDiscoveredDevice(device bluetooth.BluetoothDevice) {
+	rssi := "?"
+	if device.RSSI != nil {
+		rssi = fmt.Sprintf("%d dBm", *device.RSSI)
+	}
+	name := device.Name
+	if name == "" {
+		name = "Unknown"
+	}
+	fmt.Printf("%-17s  %8s  %s\n", device.Address, rssi, name)
+}
+
 func init() {
 	Command.AddCommand(bluetoothScanCmd)
+	bluetoothScanCmd.Flags().DurationVarP(&scanDuration, "duration", "d", 10*time.Second, "Duration of the scan")
 }
@@ -0,0 +1,39 @@
+package bluetooth
+
+import (
+	"github.com/markusressel/system-control/internal/bluetooth"
+	"github.com/spf13/cobra"
+)
+
+var bluetoothTrustCmd = &cobra.Command{
+	Use:   "trust",
+	Short: "Trust a Bluetooth Device",
+	Long:  `Trust a Bluetooth Device, which allows it to connect without confirmation.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		device, err := findBluetoothDevice(args[0])
+		if err != nil {
+			return err
+		}
+		return bluetooth.SetBluetoothDeviceTrusted(device, true)
+	},
+}
+
+var bluetoothUntrustCmd = &cobra.Command{
+	Use:   "untrust",
+	Short: "Revoke the trust of a Bluetooth Device",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		device, err := findBluetoothDevice(args[0])
+		if err != nil {
+			return err
+		}
+		return bluetooth.SetBluetoothDeviceTrusted(device, false)
+	},
+}
+
+func init() {
+	Command.AddCommand(bluetoothTrustCmd)
+	Command.AddCommand(bluetoothUntrustCmd)
+}
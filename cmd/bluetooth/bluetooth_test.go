@@ -0,0 +1,82 @@
+package bluetooth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/markusressel/system-control/internal/bluetooth"
+	"github.com/markusressel/system-control/internal/dbustest"
+	"github.com/stretchr/testify/assert"
+)
+
+var testDevices = []bluetooth.BluetoothDevice{
+	{Name: "LG-TONE-FP9", Address: "B8:F8:BE:13:A4:72"},
+	{Name: "MX Keys", Address: "F1:2B:3C:4D:5E:6F"},
+	{Name: "MX Master 3", Address: "C0:E3:79:2A:11:05"},
+	{Name: "WH-1000XM4", Address: "00:1D:43:6D:03:1A", Blocked: true},
+}
+
+func deviceNames(devices []bluetooth.BluetoothDevice) []string {
+	names := make([]string, len(devices))
+	for i, device := range devices {
+		names[i] = device.Name
+	}
+	return names
+}
+
+func TestFindBluetoothDeviceFuzzy(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected []string
+	}{
+		{"b8:f8:be:13:a4:72", []string{"LG-TONE-FP9"}},
+		{"tone", []string{"LG-TONE-FP9"}},
+		{"lgfp9", []string{"LG-TONE-FP9"}},
+		{"MX", []string{"MX Keys", "MX Master 3"}},
+		{"mx master", []string{"MX Master 3"}},
+		{"Galaxy Buds", []string{}},
+	}
+	for _, test := range tests {
+		// WHEN
+		result := findBluetoothDeviceFuzzy(test.name, testDevices)
+
+		// THEN
+		assert.Equal(t, test.expected, deviceNames(result), test.name)
+	}
+}
+
+func TestFindPreferredDevices(t *testing.T) {
+	// WHEN
+	result := findPreferredDevices([]string{"WH-1000XM4", "mx master", "Galaxy Buds", "b8:f8:be:13:a4:72", "MX Master 3"}, testDevices)
+
+	// THEN
+	assert.Equal(t, []string{"MX Master 3", "LG-TONE-FP9"}, deviceNames(result))
+}
+
+func TestFindBluetoothDevice(t *testing.T) {
+	// GIVEN
+	address := dbustest.StartBus(t)
+	bluez := dbustest.StartBlueZ(t, address)
+	adapterPath := dbus.ObjectPath("/org/bluez/hci0")
+	bluez.AddAdapter(t, adapterPath, map[string]any{"Address": "AA:BB:CC:DD:EE:00", "Powered": true})
+	for _, device := range testDevices {
+		bluez.AddDevice(t, adapterPath+"/dev_"+dbus.ObjectPath(strings.ReplaceAll(device.Address, ":", "_")), map[string]any{
+			"Address": device.Address,
+			"Name":    device.Name,
+			"Adapter": adapterPath,
+		}, nil)
+	}
+	t.Cleanup(bluetooth.UseBus(dbustest.Connect(t, address), dbustest.Dialer(address)))
+
+	// WHEN / THEN
+	device, err := findBluetoothDevice("tone")
+	assert.NoError(t, err)
+	assert.Equal(t, "B8:F8:BE:13:A4:72", device.Address)
+
+	_, err = findBluetoothDevice("MX")
+	assert.ErrorContains(t, err, "multiple matching devices found")
+
+	_, err = findBluetoothDevice("Galaxy Buds")
+	assert.EqualError(t, err, "device not found: Galaxy Buds")
+}
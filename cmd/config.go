@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/markusressel/system-control/internal/configuration"
 	"github.com/spf13/cobra"
 )
@@ -15,6 +17,24 @@ var configCmd = &cobra.Command{
 	},
 }
 
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the configuration of the system-control tool",
+	Long: `Validate all sections of the configuration of the system-control tool.
+
+Commands only validate the sections they use, this checks the whole configuration at once.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := configuration.Validate()
+		if err != nil {
+			return err
+		}
+		fmt.Println("Configuration is valid")
+		return nil
+	},
+}
+
 func init() {
 	RootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd)
 }
@@ -0,0 +1,213 @@
+package backlight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/markusressel/system-control/internal/configuration"
+	"github.com/markusressel/system-control/internal/persistence"
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/spf13/cobra"
+)
+
+const (
+	KeyBacklightAutoBias = "backlight.auto.bias"
+)
+
+var (
+	autoSensor   string
+	autoInterval time.Duration
+
+	TEMP_PATH = os.TempDir() + "/system-control"
+)
+
+// autoBias is a manual offset (in percentage points) applied on top of the brightness curve
+type autoBias struct {
+	Offset  float64
+	Updated time.Time
+}
+
+var backlightAutoCmd = &cobra.Command{
+	Use:   "auto",
+	Short: "Continuously adjust the display backlight brightness based on an ambient light sensor",
+	Long: `Continuously adjust the display backlight brightness based on an ambient light sensor.
+
+The brightness curve, hysteresis and smoothing can be configured in the "backlight.auto" section
+of the configuration file. While this command is running, "brightness inc" and "brightness dec"
+bias the curve instead of being overridden by it.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := configuration.ValidateBacklight()
+		if err != nil {
+			return err
+		}
+
+		autoLock, err := lockBacklightAuto()
+		if err != nil {
+			return err
+		}
+		defer autoLock.Unlock()
+
+		config := configuration.CurrentConfig.Backlight.Auto
+		sensorName := config.Sensor
+		if cmd.Flags().Changed("sensor") {
+			sensorName = autoSensor
+		}
+		interval := config.Interval
+		if cmd.Flags().Changed("interval") {
+			interval = autoInterval
+		}
+		if interval <= 0 {
+			return errors.New("interval must be positive")
+		}
+
+		sensor, err := util.FindAmbientLightSensor(sensorName)
+		if err != nil {
+			return err
+		}
+		mainBacklight, err := util.GetMainBacklight()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Using ambient light sensor %s (%s) for backlight %s\n", sensor.Name, sensor.Label, mainBacklight.Name)
+
+		// start without a bias from a previous session
+		err = saveAutoBias(autoBias{})
+		if err != nil {
+			return err
+		}
+
+		controller := util.AmbientBacklightController{
+			Curve:      toBrightnessCurve(config.Curve),
+			Hysteresis: config.Hysteresis,
+			Smoothing:  config.Smoothing,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			lux, err := sensor.GetIlluminance()
+			if err != nil {
+				return err
+			}
+
+			bias := getAutoBias(config.BiasTimeout)
+			target, changed := controller.Update(lux, bias)
+			if changed {
+				percentage := int(math.Round(target))
+				err = mainBacklight.SetBrightness(percentage)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Illuminance: %.1f lx, Bias: %+.0f%%, Brightness: %d%%\n", controller.SmoothedIlluminance(), bias, percentage)
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+func toBrightnessCurve(points []configuration.BacklightCurvePoint) util.BrightnessCurve {
+	return util.MapFunc(points, func(p configuration.BacklightCurvePoint) util.BrightnessCurvePoint {
+		return util.BrightnessCurvePoint{
+			Lux:        p.Lux,
+			Brightness: p.Brightness,
+		}
+	})
+}
+
+// getAutoBias returns the currently active manual bias, or 0 if it is expired or unset
+func getAutoBias(timeout time.Duration) float64 {
+	var bias autoBias
+	err := persistence.ReadStruct(KeyBacklightAutoBias, &bias)
+	if err != nil {
+		return 0
+	}
+	if timeout > 0 && time.Since(bias.Updated) > timeout {
+		return 0
+	}
+	return bias.Offset
+}
+
+func saveAutoBias(bias autoBias) error {
+	return persistence.SaveStruct(KeyBacklightAutoBias, bias)
+}
+
+// biasAutoBacklight adds the given change (in percentage points) to the bias of a running
+// "backlight auto" process. It does nothing if no such process is running.
+func biasAutoBacklight(change int) error {
+	if !isBacklightAutoRunning() {
+		return nil
+	}
+	timeout := configuration.CurrentConfig.Backlight.Auto.BiasTimeout
+	offset := getAutoBias(timeout) + float64(change)
+	return saveAutoBias(autoBias{
+		Offset:  util.Clamp(offset, -100.0, 100.0),
+		Updated: time.Now(),
+	})
+}
+
+func backlightAutoLockPath() string {
+	return TEMP_PATH + "/cmd-display-backlight-auto.lock"
+}
+
+func lockBacklightAuto() (*flock.Flock, error) {
+	err := os.MkdirAll(TEMP_PATH, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("could not create temp directory: %w", err)
+	}
+	fileLock := flock.New(backlightAutoLockPath())
+	locked, err := fileLock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("could not acquire lock: %w", err)
+	}
+	if !locked {
+		return nil, errors.New("automatic backlight control is already running")
+	}
+	return fileLock, nil
+}
+
+func isBacklightAutoRunning() bool {
+	fileLock := flock.New(backlightAutoLockPath())
+	locked, err := fileLock.TryLock()
+	if err != nil {
+		return false
+	}
+	if locked {
+		_ = fileLock.Unlock()
+		return false
+	}
+	return true
+}
+
+func init() {
+	backlightAutoCmd.Flags().StringVarP(
+		&autoSensor,
+		"sensor", "s",
+		"",
+		"Name or label of the ambient light sensor to use",
+	)
+	backlightAutoCmd.Flags().DurationVarP(
+		&autoInterval,
+		"interval", "i",
+		time.Second,
+		"Interval between sensor readings",
+	)
+
+	Command.AddCommand(backlightAutoCmd)
+}
@@ -38,7 +38,12 @@ var incBrightnessCmd = &cobra.Command{
 
 		rawChange := int(float32(change) * (float32(maxBrightness) / 100.0))
 
-		return mainBacklight.AdjustBrightness(rawChange)
+		err = mainBacklight.AdjustBrightness(rawChange)
+		if err != nil {
+			return err
+		}
+
+		return biasAutoBacklight(change)
 	},
 }
 
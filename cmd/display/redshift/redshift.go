@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"slices"
-	"strconv"
 
 	"github.com/gofrs/flock"
+	"github.com/markusressel/system-control/internal/configuration"
+	"github.com/markusressel/system-control/internal/gammaramp"
 	"github.com/markusressel/system-control/internal/persistence"
 	"github.com/markusressel/system-control/internal/util"
 	"github.com/spf13/cobra"
@@ -18,6 +18,7 @@ const (
 	KeyRedshiftColorTemp  = "redshift.colorTemperature"
 	KeyRedshiftBrightness = "redshift.brightness"
 	KeyRedshiftGamma      = "redshift.gamma"
+	KeyRedshiftReset      = "redshift.reset"
 )
 
 var (
@@ -38,6 +39,16 @@ var Command = &cobra.Command{
 	Use:   "redshift",
 	Short: "Apply the given redshift",
 	Long:  ``,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// cobra only runs the closest persistent pre-run hook, so the one of the root command has to be run explicitly
+		if root := cmd.Root(); root != cmd && root.PersistentPreRunE != nil {
+			err := root.PersistentPreRunE(cmd, args)
+			if err != nil {
+				return err
+			}
+		}
+		return configuration.ValidateRedshift()
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		redshiftLock = lockRedshift()
 		defer redshiftLock.Unlock()
@@ -103,15 +114,32 @@ func parseDisplayParam(display string) (result []util.DisplayInfo, err error) {
 			result = []util.DisplayInfo{*foundDisplayName}
 		}
 	} else {
-		return util.GetDisplays()
+		return getDisplays()
 	}
 
 	return result, nil
 }
 
+// getDisplays returns all displays whose gamma ramps can be adjusted
+func getDisplays() ([]util.DisplayInfo, error) {
+	backend, err := gammaramp.NewBackend()
+	if err != nil {
+		return nil, err
+	}
+	defer backend.Close()
+
+	outputs, err := backend.Outputs()
+	if err != nil {
+		return nil, err
+	}
+	return util.MapFunc(outputs, func(name string) util.DisplayInfo {
+		return util.DisplayInfo{Name: name}
+	}), nil
+}
+
 // findDisplay finds a display by name
 func findDisplay(d string) (*util.DisplayInfo, error) {
-	knownDisplays, err := util.GetDisplays()
+	knownDisplays, err := getDisplays()
 	if err != nil {
 		return nil, err
 	}
@@ -166,6 +194,36 @@ func getLastSetGamma(display util.DisplayInfo) float64 {
 	return lastSetGamma
 }
 
+// getLastSetColorSetting returns the last set values of the given display
+func getLastSetColorSetting(display util.DisplayInfo) gammaramp.ColorSetting {
+	colorTemperature := getLastSetColorTemperature(display)
+	if colorTemperature == -1 {
+		colorTemperature = gammaramp.NeutralColorTemperature
+	}
+	return gammaramp.ColorSetting{
+		Temperature: colorTemperature,
+		Brightness:  getLastSetBrightness(display),
+		Gamma:       getLastSetGamma(display),
+	}
+}
+
+// isReset returns true if the default gamma ramps should be applied to the given display
+// instead of the last set values
+func isReset(display util.DisplayInfo) bool {
+	key := KeyRedshiftReset + "." + display.Name
+	value, err := persistence.ReadInt(key)
+	return err == nil && value == 1
+}
+
+func saveResetState(display util.DisplayInfo, reset bool) error {
+	key := KeyRedshiftReset + "." + display.Name
+	value := 0
+	if reset {
+		value = 1
+	}
+	return persistence.SaveInt(key, value)
+}
+
 func saveLastSetColorTemperature(display util.DisplayInfo, colorTemperature int64) error {
 	key := KeyRedshiftColorTemp + "." + display.Name
 	return persistence.SaveInt(key, int(colorTemperature))
@@ -192,15 +250,17 @@ func ApplyRedshift(display util.DisplayInfo, colorTemperature int64, brightness
 		gamma = getLastSetGamma(display)
 	}
 
-	displays, err := util.GetDisplays()
+	backend, err := gammaramp.NewBackend()
 	if err != nil {
 		return err
 	}
-	displayIndex := slices.IndexFunc(displays, func(d util.DisplayInfo) bool { return d.Name == display.Name })
+	defer backend.Close()
 
-	err = SetRedshiftCBG(displayIndex, colorTemperature, brightness, gamma)
-	if err != nil {
-		return err
+	if backend.KeepsStateOnExit() {
+		err = SetRedshiftCBG(backend, display, colorTemperature, brightness, gamma)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = saveLastSetColorTemperature(display, colorTemperature)
@@ -215,69 +275,64 @@ func ApplyRedshift(display util.DisplayInfo, colorTemperature int64, brightness
 	if err != nil {
 		return err
 	}
+	err = saveResetState(display, false)
+	if err != nil {
+		return err
+	}
 
+	if !backend.KeepsStateOnExit() {
+		return ensureRedshiftHoldRunning()
+	}
 	return nil
 }
 
 // SetRedshiftCBG sets the redshift color temperature, brightness and gamma
-// colorTemperature: the color temperature in Kelvin, between 1000 and 25000 (-1 to ignore, 6500 is default
-// brightness: the brightness value between 0.1 and 1.0 (-1 to ignore, 1.0 is default)
-// gamma: the gamma value between 0.1 and 1.0 (-1 to ignore, 1.0 is default)
-// immediate: apply the changes immediately, without transition
-func SetRedshiftCBG(displayIndex int, colorTemperature int64, brightness float64, gamma float64) error {
-	args := []string{
-		"-x", // reset previous "mode"
-		"-P", // reset previous gamma ramps
-		"-o", // one shot mode
-	}
-
-	if displayIndex > -1 {
-		// -m randr:crtc=1
-		args = append(args, "-m", fmt.Sprintf("randr:crtc=%d", displayIndex))
-	}
-
-	if colorTemperature != -1 {
-		// set color temperature
-		args = append(args, "-O", fmt.Sprintf("%d", colorTemperature))
+// colorTemperature: the color temperature in Kelvin, between 1000 and 25000 (6500 is neutral)
+// brightness: the brightness value between 0.1 and 1.0 (1.0 is default)
+// gamma: the gamma value between 0.1 and 2.0 (1.0 is default)
+func SetRedshiftCBG(backend gammaramp.Backend, display util.DisplayInfo, colorTemperature int64, brightness float64, gamma float64) error {
+	if colorTemperature == -1 {
+		colorTemperature = gammaramp.NeutralColorTemperature
 	}
-
-	if brightness != -1 {
-		// set brightness
-		args = append(args, "-b", strconv.FormatFloat(brightness, 'f', -1, 64))
+	if brightness == -1 {
+		brightness = 1.0
 	}
-
-	if gamma != -1 {
-		// set gamma
-		args = append(args, "-g", strconv.FormatFloat(gamma, 'f', -1, 64))
+	if gamma == -1 {
+		gamma = 1.0
 	}
 
-	if len(args) == 0 {
-		return errors.New("no changes to apply")
-	}
-	_, err := util.ExecCommand("redshift", args...)
-	return err
+	return backend.SetColor(display.Name, gammaramp.ColorSetting{
+		Temperature: colorTemperature,
+		Brightness:  brightness,
+		Gamma:       gamma,
+	})
 }
 
+// ResetRedshift restores the default gamma ramps of the given display.
+// The last set values are kept, so they can be re-applied later.
 func ResetRedshift(display util.DisplayInfo) (err error) {
-	args := []string{
-		"-x", // reset previous "mode"
-		"-P", // reset previous gamma ramps
-		"-r", // apply immediately
+	backend, err := gammaramp.NewBackend()
+	if err != nil {
+		return err
 	}
+	defer backend.Close()
 
-	displays, err := util.GetDisplays()
+	if backend.KeepsStateOnExit() {
+		err = gammaramp.ResetColor(backend, display.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = saveResetState(display, true)
 	if err != nil {
 		return err
 	}
-	displayIndex := slices.IndexFunc(displays, func(d util.DisplayInfo) bool { return d.Name == display.Name })
 
-	if displayIndex > -1 {
-		// -m randr:crtc=1
-		args = append(args, "-m", fmt.Sprintf("randr:crtc=%d", displayIndex))
+	if !backend.KeepsStateOnExit() {
+		return ensureRedshiftHoldRunning()
 	}
-
-	_, err = util.ExecCommand("redshift", args...)
-	return err
+	return nil
 }
 
 var (
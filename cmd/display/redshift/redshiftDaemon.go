@@ -0,0 +1,79 @@
+package redshift
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	daemonInterval time.Duration
+)
+
+var redshiftDaemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Continuously update the applied redshift based on the current time of day.",
+	Long: `Continuously update the applied redshift based on the current time of day.
+
+Values are interpolated in small steps from the last set values towards the target, so that the
+target is reached after the configured "redshift.transitionDuration". A new transition is started
+on startup, after the system was suspended and after values have been changed manually.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if daemonInterval <= 0 {
+			return errors.New("interval must be positive")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		ticker := time.NewTicker(daemonInterval)
+		defer ticker.Stop()
+
+		var lastTick time.Time
+		for {
+			// strip the monotonic clock reading, since it does not advance while the system is suspended
+			now := time.Now().Round(0)
+			restartTransition := lastTick.IsZero() || now.Sub(lastTick) > 3*daemonInterval
+			lastTick = now
+
+			err := daemonStep(now, restartTransition)
+			if err != nil {
+				return err
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+func daemonStep(now time.Time, restartTransition bool) error {
+	redshiftLock = lockRedshift()
+	defer redshiftLock.Unlock()
+
+	displays, err := parseDisplayParam(display)
+	if err != nil {
+		return err
+	}
+
+	return updateRedshift(displays, now, restartTransition)
+}
+
+func init() {
+	redshiftDaemonCmd.Flags().DurationVarP(
+		&daemonInterval,
+		"interval", "i",
+		5*time.Second,
+		"Interval between two update steps",
+	)
+
+	Command.AddCommand(redshiftDaemonCmd)
+}
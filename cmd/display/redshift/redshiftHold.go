@@ -0,0 +1,131 @@
+package redshift
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/markusressel/system-control/internal/gammaramp"
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// redshiftHoldInterval is the interval in which the hold process checks for changed values
+	redshiftHoldInterval = 250 * time.Millisecond
+)
+
+// redshiftHoldCmd keeps the last set values applied for display servers that reset
+// the gamma ramps as soon as the client that set them disconnects (f.ex. wlroots based
+// Wayland compositors). It is started automatically when needed.
+var redshiftHoldCmd = &cobra.Command{
+	Use:    "hold",
+	Short:  "Keep the last set redshift values applied until terminated",
+	Long:   ``,
+	Hidden: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		holdLock := flock.New(redshiftHoldLockPath())
+		locked, err := holdLock.TryLock()
+		if err != nil {
+			return err
+		}
+		if !locked {
+			// another hold process is already running
+			return nil
+		}
+		defer holdLock.Unlock()
+
+		backend, err := gammaramp.NewBackend()
+		if err != nil {
+			return err
+		}
+		defer backend.Close()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		ticker := time.NewTicker(redshiftHoldInterval)
+		defer ticker.Stop()
+
+		applied := map[string]gammaramp.ColorSetting{}
+		for {
+			outputs, err := backend.Outputs()
+			if err != nil {
+				return err
+			}
+			for _, output := range outputs {
+				display := util.DisplayInfo{Name: output}
+				setting := getLastSetColorSetting(display)
+				if isReset(display) {
+					setting = gammaramp.NeutralColorSetting()
+				}
+				if current, ok := applied[output]; ok && current == setting {
+					continue
+				}
+				err = backend.SetColor(output, setting)
+				if err != nil {
+					return err
+				}
+				applied[output] = setting
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+func redshiftHoldLockPath() string {
+	return TEMP_PATH + "/cmd-display-redshift-hold.lock"
+}
+
+// ensureRedshiftHoldRunning starts a detached hold process, if none is running yet
+func ensureRedshiftHoldRunning() error {
+	err := os.MkdirAll(TEMP_PATH, 0755)
+	if err != nil {
+		return err
+	}
+	holdLock := flock.New(redshiftHoldLockPath())
+	locked, err := holdLock.TryLock()
+	if err != nil {
+		return err
+	}
+	if !locked {
+		// the running hold process picks up the new values by itself
+		return nil
+	}
+	err = holdLock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	executablePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to resolve executable path: %w", err)
+	}
+
+	childProcess := exec.Command(executablePath, "display", "redshift", "hold")
+	childProcess.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	childProcess.Stdin = nil
+	childProcess.Stdout = io.Discard
+	childProcess.Stderr = io.Discard
+
+	if err := childProcess.Start(); err != nil {
+		return fmt.Errorf("failed to start redshift hold process: %w", err)
+	}
+
+	return childProcess.Process.Release()
+}
+
+func init() {
+	Command.AddCommand(redshiftHoldCmd)
+}
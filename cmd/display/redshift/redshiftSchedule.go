@@ -0,0 +1,78 @@
+package redshift
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elliotchance/orderedmap/v2"
+	"github.com/markusressel/system-control/internal/configuration"
+	"github.com/markusressel/system-control/internal/daylight"
+	"github.com/markusressel/system-control/internal/gammaramp"
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scheduleDuration time.Duration
+)
+
+var redshiftScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Print the upcoming day/night transition times",
+	Long:  ``,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if scheduleDuration <= 0 {
+			return errors.New("duration must be positive")
+		}
+
+		config := configuration.CurrentConfig.Redshift
+		schedule, err := daylight.NewSchedule(config)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		target, err := CalculateTargetColorSetting(config, "", gammaramp.NeutralColorSetting(), now)
+		if err != nil {
+			return err
+		}
+
+		properties := orderedmap.NewOrderedMap[string, string]()
+		properties.Set("Mode", config.Schedule.Mode)
+		if solarSchedule, ok := schedule.(daylight.SolarSchedule); ok {
+			location := solarSchedule.Location
+			properties.Set("Location", fmt.Sprintf("%.4f, %.4f", location.Latitude, location.Longitude))
+			properties.Set("Location Source", location.Source)
+		}
+		properties.Set("Period", daylight.CurrentPeriod(schedule, now))
+		properties.Set("Color Temperature", fmt.Sprintf("%dK", target.Temperature))
+		properties.Set("Brightness", fmt.Sprintf("%.2f", target.Brightness))
+		properties.Set("Gamma", fmt.Sprintf("%.2f", target.Gamma))
+		util.PrintFormattedTableOrdered("Schedule", properties)
+
+		fmt.Println()
+
+		events := orderedmap.NewOrderedMap[string, string]()
+		for _, event := range schedule.Events(now, now.Add(scheduleDuration)) {
+			eventTime := event.Time.Local()
+			until := strings.TrimSuffix(eventTime.Sub(now).Round(time.Minute).String(), "0s")
+			events.Set(eventTime.Format("Mon 2006-01-02 15:04"), fmt.Sprintf("%s (in %s)", event.Period, until))
+		}
+		util.PrintFormattedTableOrdered("Upcoming", events)
+
+		return nil
+	},
+}
+
+func init() {
+	redshiftScheduleCmd.Flags().DurationVar(
+		&scheduleDuration,
+		"duration",
+		24*time.Hour,
+		"Time span to print upcoming transitions for",
+	)
+
+	Command.AddCommand(redshiftScheduleCmd)
+}
@@ -0,0 +1,68 @@
+package redshift
+
+import (
+	"time"
+
+	"github.com/markusressel/system-control/internal/configuration"
+	"github.com/markusressel/system-control/internal/gammaramp"
+	"github.com/markusressel/system-control/internal/persistence"
+	"github.com/markusressel/system-control/internal/util"
+)
+
+const (
+	KeyRedshiftTransition = "redshift.transition"
+)
+
+// transitionState is the persisted state of an ongoing transition of a single display
+type transitionState struct {
+	From        gammaramp.ColorSetting
+	Start       time.Time
+	LastApplied gammaramp.ColorSetting
+}
+
+func getTransitionState(display util.DisplayInfo) (transitionState, error) {
+	var state transitionState
+	err := persistence.ReadStruct(KeyRedshiftTransition+"."+display.Name, &state)
+	return state, err
+}
+
+func saveTransitionState(display util.DisplayInfo, state transitionState) error {
+	return persistence.SaveStruct(KeyRedshiftTransition+"."+display.Name, state)
+}
+
+// stepTransition moves the redshift values of the given display one step closer to the given target
+// and returns the applied values.
+//
+// The values are interpolated linearly from the last set values towards the target over
+// the configured transition duration. A new transition is started if restart is true, if there is
+// no previous transition or if the values were changed by something else since the last step
+// (f.ex. a manual adjustment).
+func stepTransition(display util.DisplayInfo, target gammaramp.ColorSetting, now time.Time, restart bool) (gammaramp.ColorSetting, error) {
+	duration := configuration.CurrentConfig.Redshift.TransitionDuration
+	current := getLastSetColorSetting(display)
+
+	state, err := getTransitionState(display)
+	if restart || err != nil || state.LastApplied != current {
+		state = transitionState{
+			From:  current,
+			Start: now,
+		}
+	}
+
+	transition := gammaramp.Transition{
+		From:     state.From,
+		Start:    state.Start,
+		Duration: duration,
+	}
+	value := transition.ValueAt(target, now)
+
+	if value != current {
+		err = ApplyRedshift(display, value.Temperature, value.Brightness, value.Gamma)
+		if err != nil {
+			return current, err
+		}
+	}
+
+	state.LastApplied = value
+	return value, saveTransitionState(display, state)
+}
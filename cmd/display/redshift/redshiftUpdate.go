@@ -1,92 +1,177 @@
 package redshift
 
 import (
-	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/markusressel/system-control/internal/configuration"
+	"github.com/markusressel/system-control/internal/daylight"
+	"github.com/markusressel/system-control/internal/gammaramp"
 	"github.com/markusressel/system-control/internal/util"
-	"github.com/nathan-osman/go-sunrise"
 	"github.com/spf13/cobra"
 )
 
 var redshiftUpdateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Update the currently applied redshift based on the current time of day.",
-	Long:  ``,
+	Long: `Update the currently applied redshift based on the current time of day.
+
+Changes are applied gradually: every invocation moves the values one step closer to the target,
+so that the target is reached after the configured "redshift.transitionDuration". Run this command
+periodically (f.ex. every minute) or use "redshift daemon" instead.`,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		redshiftLock = lockRedshift()
 		defer redshiftLock.Unlock()
 
-		config := configuration.CurrentConfig
-
-		redshiftConfig, err := util.ReadRedshiftConfig()
+		displays, err := parseDisplayParam(display)
 		if err != nil {
 			return err
 		}
 
-		colorTemperature := CalculateTargetColorTemperature(
-			config.Redshift,
-			redshiftConfig,
-		)
+		return updateRedshift(displays, time.Now(), false)
+	},
+}
 
-		if colorTemperature != -1 && (colorTemperature < 1000 || colorTemperature > 25000) {
-			return errors.New("color temperature must be between 1000 and 25000")
+// updateRedshift moves the redshift values of the given displays one step closer to the
+// target for the given point in time
+func updateRedshift(displays []util.DisplayInfo, now time.Time, restartTransition bool) error {
+	for _, display := range displays {
+		target, err := calculateTargetColorSetting(display, now)
+		if err != nil {
+			return err
 		}
 
-		displays, err := parseDisplayParam(display)
+		lastSet := getLastSetColorSetting(display)
+		value, err := stepTransition(display, target, now, restartTransition)
 		if err != nil {
 			return err
 		}
 
-		for _, display := range displays {
-			err = ApplyRedshift(display, colorTemperature, -1, -1)
-			if err != nil {
-				return err
-			}
-
-			// print current values, if any of them were changed
-			lastSetColorTemperature := getLastSetColorTemperature(display)
-			lastSetBrightness := getLastSetBrightness(display)
-			lastSetGamma := getLastSetGamma(display)
-
-			if colorTemperature != -1 && colorTemperature != lastSetColorTemperature {
-				fmt.Printf("Color Temperature: %d -> %d\n", lastSetColorTemperature, colorTemperature)
-				fmt.Printf("Brightness: %.2f\n", lastSetBrightness)
-				fmt.Printf("Gamma: %.2f\n", lastSetGamma)
-			}
+		// print current values, if any of them were changed
+		if value != lastSet {
+			fmt.Printf("Display: %s\n", display.Name)
+			fmt.Printf("  Color Temperature: %d -> %d (target: %d)\n", lastSet.Temperature, value.Temperature, target.Temperature)
+			fmt.Printf("  Brightness: %.2f -> %.2f (target: %.2f)\n", lastSet.Brightness, value.Brightness, target.Brightness)
+			fmt.Printf("  Gamma: %.2f -> %.2f (target: %.2f)\n", lastSet.Gamma, value.Gamma, target.Gamma)
 		}
+	}
 
-		return nil
-	},
+	return nil
+}
+
+// calculateTargetColorSetting calculates the values the given display should have at the given point in time
+func calculateTargetColorSetting(display util.DisplayInfo, now time.Time) (gammaramp.ColorSetting, error) {
+	current := getLastSetColorSetting(display)
+	return CalculateTargetColorSetting(configuration.CurrentConfig.Redshift, display.Name, current, now)
 }
 
 const (
-	// TransitionElevationThreshold is the sun's elevation threshold at which the color temperature should be fully transitioned.
-	TransitionElevationThreshold = 20
+	defaultDayColorTemperature   = 6500
+	defaultNightColorTemperature = 4500
 )
 
-func CalculateTargetColorTemperature(
-	_redshiftConfig configuration.RedshiftConfig,
-	redshiftConfig util.RedshiftConfig,
-) int64 {
-	elevation := sunrise.Elevation(
-		redshiftConfig.Manual.Lat,
-		redshiftConfig.Manual.Lon,
-		time.Now(),
-	)
-
-	targetColor := redshiftConfig.Redshift.DayColorTemperature
-	if elevation < 0 {
-		targetColor = redshiftConfig.Redshift.NightColorTemperature
-	} else if elevation > TransitionElevationThreshold {
-		targetColor = redshiftConfig.Redshift.DayColorTemperature
-	} else {
-		targetColor = redshiftConfig.Redshift.NightColorTemperature + int64((float64(redshiftConfig.Redshift.DayColorTemperature)-float64(redshiftConfig.Redshift.NightColorTemperature))*(elevation/TransitionElevationThreshold))
+// CalculateTargetColorSetting calculates the values for the given display at the given point in time,
+// interpolating between the night, twilight and day profiles based on the configured schedule.
+// Brightness and gamma are only changed if they are configured in at least one profile,
+// otherwise the current value is kept.
+func CalculateTargetColorSetting(redshiftConfig configuration.RedshiftConfig, displayName string, current gammaramp.ColorSetting, now time.Time) (gammaramp.ColorSetting, error) {
+	schedule, err := daylight.NewSchedule(redshiftConfig)
+	if err != nil {
+		return current, err
+	}
+	factor := schedule.DaylightFactor(now)
+	profiles := getDisplayProfiles(redshiftConfig, displayName)
+
+	day, night := getDayNightColorTemperature(profiles)
+	twilight := profiles.Twilight.ColorTemperature
+	if twilight <= 0 {
+		twilight = (day + night) / 2
+	}
+	colorTemperature := daylight.Interpolate(factor, float64(night), float64(twilight), float64(day))
+
+	target := current
+	target.Temperature = clampColorTemperatureToConfig(int64(math.Round(colorTemperature)))
+	target.Brightness = clampBrightnessToConfig(interpolateProfileValue(
+		factor, profiles.Night.Brightness, profiles.Twilight.Brightness, profiles.Day.Brightness, current.Brightness,
+	))
+	target.Gamma = clampGammaToConfig(interpolateProfileValue(
+		factor, profiles.Night.Gamma, profiles.Twilight.Gamma, profiles.Day.Gamma, current.Gamma,
+	))
+	return target, nil
+}
+
+// getDisplayProfiles returns the profiles for the given display, which are the global profiles
+// with all values overridden that are set in the display specific profiles.
+func getDisplayProfiles(redshiftConfig configuration.RedshiftConfig, displayName string) configuration.RedshiftProfilesConfig {
+	profiles := redshiftConfig.Profiles
+	overrides, ok := redshiftConfig.Displays[displayName]
+	if !ok {
+		return profiles
+	}
+	profiles.Day = mergeProfile(profiles.Day, overrides.Day)
+	profiles.Twilight = mergeProfile(profiles.Twilight, overrides.Twilight)
+	profiles.Night = mergeProfile(profiles.Night, overrides.Night)
+	return profiles
+}
+
+func mergeProfile(base configuration.RedshiftProfileConfig, override configuration.RedshiftProfileConfig) configuration.RedshiftProfileConfig {
+	if override.ColorTemperature > 0 {
+		base.ColorTemperature = override.ColorTemperature
+	}
+	if override.Brightness > 0 {
+		base.Brightness = override.Brightness
+	}
+	if override.Gamma > 0 {
+		base.Gamma = override.Gamma
+	}
+	return base
+}
+
+// interpolateProfileValue interpolates a brightness or gamma value between the given profile values.
+// Unset (zero) day and night values default to 1.0, an unset twilight value defaults to the
+// midpoint between day and night. If no value is set at all, the current value is returned.
+func interpolateProfileValue(factor float64, night float64, twilight float64, day float64, current float64) float64 {
+	if night <= 0 && twilight <= 0 && day <= 0 {
+		return current
+	}
+	if night <= 0 {
+		night = 1.0
+	}
+	if day <= 0 {
+		day = 1.0
+	}
+	if twilight <= 0 {
+		twilight = (day + night) / 2
+	}
+	return daylight.Interpolate(factor, night, twilight, day)
+}
+
+// getDayNightColorTemperature returns the configured day and night color temperatures,
+// falling back to ~/.config/redshift.conf and the default values.
+func getDayNightColorTemperature(profiles configuration.RedshiftProfilesConfig) (day int64, night int64) {
+	day = profiles.Day.ColorTemperature
+	night = profiles.Night.ColorTemperature
+	if day > 0 && night > 0 {
+		return day, night
 	}
 
-	return targetColor
+	legacyConfig, err := util.ReadRedshiftConfig()
+	if err == nil {
+		if day <= 0 {
+			day = legacyConfig.Redshift.DayColorTemperature
+		}
+		if night <= 0 {
+			night = legacyConfig.Redshift.NightColorTemperature
+		}
+	}
+	if day <= 0 {
+		day = defaultDayColorTemperature
+	}
+	if night <= 0 {
+		night = defaultNightColorTemperature
+	}
+	return day, night
 }
 
 func init() {
@@ -0,0 +1,55 @@
+package connection
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/elliotchance/orderedmap/v2"
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var Command = &cobra.Command{
+	Use:   "connection",
+	Short: "Manage saved network connection profiles",
+	Long:  ``,
+}
+
+// printConnection prints the properties of the given connection profile
+func printConnection(connection wifi.Connection) {
+	properties := orderedmap.NewOrderedMap[string, string]()
+	properties.Set("Name", connection.Name)
+	properties.Set("UUID", connection.UUID)
+	properties.Set("Type", connection.Type)
+	properties.Set("Active", connection.Active)
+	properties.Set("Device", connection.Device)
+	properties.Set("State", connection.State)
+	properties.Set("Autoconnect", connection.Autoconnect)
+	properties.Set("Autoconnect-Priority", connection.AutoconnectPri)
+	properties.Set("Last Used", connection.TimestampReal)
+	properties.Set("Readonly", connection.Readonly)
+	properties.Set("Filename", connection.Filename)
+
+	util.PrintFormattedTableOrdered(connection.Name, properties)
+}
+
+// parseOnOff parses a boolean given as on/off, yes/no or true/false
+func parseOnOff(value string) (bool, error) {
+	switch value {
+	case "on", "yes":
+		return true, nil
+	case "off", "no":
+		return false, nil
+	default:
+		result, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("invalid value: %s, expected on or off", value)
+		}
+		return result, nil
+	}
+}
+
+func init() {
+	Command.AddCommand(listCmd)
+}
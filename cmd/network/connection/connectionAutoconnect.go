@@ -0,0 +1,36 @@
+package connection
+
+import (
+	"fmt"
+
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var autoconnectCmd = &cobra.Command{
+	Use:   "autoconnect <name|uuid> [on|off]",
+	Short: "Print or set whether a connection profile is connected automatically",
+	Long:  ``,
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connection, err := wifi.FindConnection(args[0])
+		if err != nil {
+			return err
+		}
+
+		if len(args) < 2 {
+			fmt.Println(connection.Autoconnect)
+			return nil
+		}
+
+		autoconnect, err := parseOnOff(args[1])
+		if err != nil {
+			return err
+		}
+		return wifi.SetConnectionAutoconnect(connection, autoconnect)
+	},
+}
+
+func init() {
+	Command.AddCommand(autoconnectCmd)
+}
@@ -0,0 +1,24 @@
+package connection
+
+import (
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete <name|uuid>",
+	Short: "Delete a connection profile",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connection, err := wifi.FindConnection(args[0])
+		if err != nil {
+			return err
+		}
+		return wifi.DeleteConnection(connection)
+	},
+}
+
+func init() {
+	Command.AddCommand(deleteCmd)
+}
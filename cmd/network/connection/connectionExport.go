@@ -0,0 +1,64 @@
+package connection
+
+import (
+	"os"
+
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	exportOutput    string
+	exportNoSecrets bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [name|uuid...]",
+	Short: "Export WiFi connection profiles to a portable YAML file",
+	Long: `Export WiFi connection profiles to a portable YAML file, which can be imported on other machines
+using "network connection import". If no connection is given, all WiFi connection profiles are exported.
+
+Reading passwords of system wide connection profiles usually requires root privileges.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connections, err := wifi.GetConnections()
+		if err != nil {
+			return err
+		}
+		connections = wifi.FilterWifiConnections(connections)
+		if len(args) > 0 {
+			selected := make([]wifi.Connection, 0, len(args))
+			for _, arg := range args {
+				connection, err := wifi.FindConnection(arg)
+				if err != nil {
+					return err
+				}
+				selected = append(selected, connection)
+			}
+			connections = selected
+		}
+
+		profiles, err := wifi.ExportConnections(connections, !exportNoSecrets)
+		if err != nil {
+			return err
+		}
+
+		output, err := yaml.Marshal(profiles)
+		if err != nil {
+			return err
+		}
+		if exportOutput == "" || exportOutput == "-" {
+			_, err = os.Stdout.Write(output)
+			return err
+		}
+		// the file may contain passwords
+		return os.WriteFile(exportOutput, output, 0600)
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "File to write to (default: stdout)")
+	exportCmd.Flags().BoolVar(&exportNoSecrets, "no-secrets", false, "Do not include passwords")
+
+	Command.AddCommand(exportCmd)
+}
@@ -0,0 +1,66 @@
+package connection
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var importReplace bool
+
+var importCmd = &cobra.Command{
+	Use:   "import <file|->",
+	Short: "Import WiFi connection profiles from a YAML file",
+	Long: `Import WiFi connection profiles from a YAML file created by "network connection export".
+Use "-" to read from stdin. Existing profiles with the same name are skipped, unless --replace is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var input []byte
+		var err error
+		if args[0] == "-" {
+			input, err = io.ReadAll(os.Stdin)
+		} else {
+			input, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			return err
+		}
+
+		var profiles wifi.ConnectionProfiles
+		err = yaml.Unmarshal(input, &profiles)
+		if err != nil {
+			return err
+		}
+
+		failed := 0
+		for _, profile := range profiles.Connections {
+			err := wifi.ImportConnectionProfile(profile, importReplace)
+			if errors.Is(err, wifi.ErrConnectionExists) {
+				fmt.Printf("%s: skipped, already exists\n", profile.Name)
+				continue
+			}
+			if err != nil {
+				failed++
+				fmt.Printf("%s: %v\n", profile.Name, err)
+				continue
+			}
+			fmt.Printf("%s: imported\n", profile.Name)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to import %d of %d connections", failed, len(profiles.Connections))
+		}
+		return nil
+	},
+}
+
+func init() {
+	importCmd.Flags().BoolVarP(&importReplace, "replace", "r", false, "Replace existing profiles with the same name")
+
+	Command.AddCommand(importCmd)
+}
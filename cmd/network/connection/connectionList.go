@@ -0,0 +1,67 @@
+package connection
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagFilterActive bool
+	filterType       string
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved connection profiles",
+	Long:  ``,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connections, err := wifi.GetConnections()
+		if err != nil {
+			return err
+		}
+
+		// filter entries
+		connections = util.FilterFunc(connections, func(connection wifi.Connection) bool {
+			if flagFilterActive && connection.Active != "yes" {
+				return false
+			}
+			if filterType != "" && !util.ContainsIgnoreCase(connection.Type, filterType) {
+				return false
+			}
+			return true
+		})
+
+		// sort entries
+		slices.SortStableFunc(connections, func(a, b wifi.Connection) int {
+			return cmp.Or(
+				// active connections first
+				-1*cmp.Compare(a.Active, b.Active),
+				// then sort by type
+				cmp.Compare(a.Type, b.Type),
+				// then sort by name
+				util.CompareIgnoreCase(a.Name, b.Name),
+			)
+		})
+
+		for i, connection := range connections {
+			printConnection(connection)
+
+			if i < len(connections)-1 {
+				fmt.Println()
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	listCmd.Flags().BoolVarP(&flagFilterActive, "active", "a", false, "Only list active connections")
+	listCmd.Flags().StringVarP(&filterType, "type", "t", "", "Filter by type (f.ex. wifi, ethernet, vpn)")
+}
@@ -0,0 +1,40 @@
+package connection
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var priorityCmd = &cobra.Command{
+	Use:   "priority <name|uuid> [priority]",
+	Short: "Print or set the autoconnect priority of a connection profile",
+	Long: `Print or set the autoconnect priority of a connection profile.
+
+If multiple profiles are available, the one with the highest priority is connected automatically.
+The priority must be between -999 and 999, the default is 0.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connection, err := wifi.FindConnection(args[0])
+		if err != nil {
+			return err
+		}
+
+		if len(args) < 2 {
+			fmt.Println(connection.AutoconnectPri)
+			return nil
+		}
+
+		priority, err := strconv.ParseInt(args[1], 10, 32)
+		if err != nil || priority < -999 || priority > 999 {
+			return fmt.Errorf("invalid priority: %s, must be between -999 and 999", args[1])
+		}
+		return wifi.SetConnectionPriority(connection, int32(priority))
+	},
+}
+
+func init() {
+	Command.AddCommand(priorityCmd)
+}
@@ -0,0 +1,31 @@
+package connection
+
+import (
+	"fmt"
+
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <name|uuid> <new-name>",
+	Short: "Rename a connection profile",
+	Long:  ``,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		newName := args[1]
+		if len(newName) <= 0 {
+			return fmt.Errorf("name must not be empty")
+		}
+
+		connection, err := wifi.FindConnection(args[0])
+		if err != nil {
+			return err
+		}
+		return wifi.RenameConnection(connection, newName)
+	},
+}
+
+func init() {
+	Command.AddCommand(renameCmd)
+}
@@ -0,0 +1,80 @@
+package connection
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/elliotchance/orderedmap/v2"
+	"github.com/godbus/dbus/v5"
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var showSecrets bool
+
+var showCmd = &cobra.Command{
+	Use:   "show <name|uuid>",
+	Short: "Show all settings of a connection profile",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connection, err := wifi.FindConnection(args[0])
+		if err != nil {
+			return err
+		}
+		settings, err := wifi.GetConnectionSettings(connection, showSecrets)
+		if err != nil {
+			return err
+		}
+
+		printConnection(connection)
+
+		settingNames := make([]string, 0, len(settings))
+		for settingName := range settings {
+			settingNames = append(settingNames, settingName)
+		}
+		slices.Sort(settingNames)
+
+		for _, settingName := range settingNames {
+			keys := make([]string, 0, len(settings[settingName]))
+			for key := range settings[settingName] {
+				keys = append(keys, key)
+			}
+			slices.Sort(keys)
+
+			properties := orderedmap.NewOrderedMap[string, string]()
+			for _, key := range keys {
+				properties.Set(key, formatSettingValue(settings[settingName][key]))
+			}
+
+			fmt.Println()
+			util.PrintFormattedTableOrdered(settingName, properties)
+		}
+
+		return nil
+	},
+}
+
+// formatSettingValue formats a connection setting value in a human-readable way
+func formatSettingValue(variant dbus.Variant) string {
+	switch value := variant.Value().(type) {
+	case []byte:
+		if utf8.Valid(value) && !slices.Contains(value, 0) {
+			return string(value)
+		}
+		return fmt.Sprintf("%x", value)
+	case []string:
+		return strings.Join(value, ", ")
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+func init() {
+	showCmd.Flags().BoolVarP(&showSecrets, "show-secrets", "s", false, "Include secrets like passwords")
+
+	Command.AddCommand(showCmd)
+}
@@ -1,7 +1,9 @@
 package network
 
 import (
+	"github.com/markusressel/system-control/cmd/network/connection"
 	"github.com/markusressel/system-control/cmd/network/device"
+	"github.com/markusressel/system-control/cmd/network/vpn"
 	"github.com/markusressel/system-control/cmd/network/wifi"
 	"github.com/spf13/cobra"
 )
@@ -13,6 +15,8 @@ var Command = &cobra.Command{
 }
 
 func init() {
+	Command.AddCommand(connection.Command)
 	Command.AddCommand(device.Command)
+	Command.AddCommand(vpn.Command)
 	Command.AddCommand(wifi.Command)
 }
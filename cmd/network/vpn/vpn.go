@@ -0,0 +1,41 @@
+package vpn
+
+import (
+	"time"
+
+	"github.com/elliotchance/orderedmap/v2"
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exclusive bool
+	timeout   time.Duration
+)
+
+var Command = &cobra.Command{
+	Use:   "vpn",
+	Short: "Control NetworkManager VPN and WireGuard connections",
+	Long:  ``,
+}
+
+// printVPN prints the properties of the given VPN connection profile
+func printVPN(connection wifi.Connection) {
+	properties := orderedmap.NewOrderedMap[string, string]()
+	properties.Set("UUID", connection.UUID)
+	properties.Set("Type", connection.Type)
+	properties.Set("Active", connection.Active)
+	properties.Set("Device", connection.Device)
+	properties.Set("State", connection.State)
+	properties.Set("Autoconnect", connection.Autoconnect)
+	properties.Set("Last Used", connection.TimestampReal)
+
+	util.PrintFormattedTableOrdered(connection.Name, properties)
+}
+
+// addActivationFlags adds the flags used by commands which activate a VPN
+func addActivationFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&exclusive, "exclusive", "x", false, "Deactivate all other VPN connections")
+	cmd.Flags().DurationVarP(&timeout, "timeout", "t", 60*time.Second, "Maximum time to wait for the connection to be established")
+}
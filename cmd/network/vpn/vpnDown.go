@@ -0,0 +1,38 @@
+package vpn
+
+import (
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var downCmd = &cobra.Command{
+	Use:   "down [name|uuid]",
+	Short: "Deactivate a VPN connection",
+	Long:  `Deactivate the given VPN connection, or all active VPN connections if none is given.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			connection, err := wifi.FindVPNConnection(args[0])
+			if err != nil {
+				return err
+			}
+			return wifi.DeactivateVPN(connection)
+		}
+
+		connections, err := wifi.GetVPNConnections()
+		if err != nil {
+			return err
+		}
+		for _, connection := range connections {
+			err = wifi.DeactivateVPN(connection)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	Command.AddCommand(downCmd)
+}
@@ -0,0 +1,45 @@
+package vpn
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List VPN and WireGuard connection profiles",
+	Long:  ``,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connections, err := wifi.GetVPNConnections()
+		if err != nil {
+			return err
+		}
+
+		slices.SortStableFunc(connections, func(a, b wifi.Connection) int {
+			return cmp.Or(
+				// active connections first
+				-1*cmp.Compare(a.Active, b.Active),
+				util.CompareIgnoreCase(a.Name, b.Name),
+			)
+		})
+
+		for i, connection := range connections {
+			printVPN(connection)
+
+			if i < len(connections)-1 {
+				fmt.Println()
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	Command.AddCommand(listCmd)
+}
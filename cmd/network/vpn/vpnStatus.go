@@ -0,0 +1,65 @@
+package vpn
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var quiet bool
+
+var statusCmd = &cobra.Command{
+	Use:   "status [name|uuid]",
+	Short: "Show the active VPN connections",
+	Long: `Show the active VPN connections, or the state of the given VPN connection.
+
+Exits with code 1 if no VPN (or the given VPN) is active, which makes it usable in scripts and status bars.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var connections []wifi.Connection
+		if len(args) > 0 {
+			connection, err := wifi.FindVPNConnection(args[0])
+			if err != nil {
+				return err
+			}
+			connections = []wifi.Connection{connection}
+		} else {
+			vpnConnections, err := wifi.GetVPNConnections()
+			if err != nil {
+				return err
+			}
+			connections = util.FilterFunc(vpnConnections, func(connection wifi.Connection) bool {
+				return connection.Active == "yes"
+			})
+		}
+
+		active := util.FilterFunc(connections, func(connection wifi.Connection) bool {
+			return connection.Active == "yes"
+		})
+		if !quiet {
+			if len(connections) == 0 {
+				fmt.Println("No VPN active")
+			}
+			for i, connection := range connections {
+				printVPN(connection)
+
+				if i < len(connections)-1 {
+					fmt.Println()
+				}
+			}
+		}
+		if len(active) == 0 {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+func init() {
+	Command.AddCommand(statusCmd)
+
+	statusCmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Only set the exit code")
+}
@@ -0,0 +1,29 @@
+package vpn
+
+import (
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var toggleCmd = &cobra.Command{
+	Use:   "toggle <name|uuid>",
+	Short: "Toggle a VPN connection",
+	Long:  `Deactivate the given VPN connection if it is active, activate it otherwise.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connection, err := wifi.FindVPNConnection(args[0])
+		if err != nil {
+			return err
+		}
+		if connection.Active == "yes" {
+			return wifi.DeactivateVPN(connection)
+		}
+		return wifi.ActivateVPN(connection, exclusive, timeout)
+	},
+}
+
+func init() {
+	Command.AddCommand(toggleCmd)
+
+	addActivationFlags(toggleCmd)
+}
@@ -0,0 +1,26 @@
+package vpn
+
+import (
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var upCmd = &cobra.Command{
+	Use:   "up <name|uuid>",
+	Short: "Activate a VPN connection",
+	Long:  ``,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connection, err := wifi.FindVPNConnection(args[0])
+		if err != nil {
+			return err
+		}
+		return wifi.ActivateVPN(connection, exclusive, timeout)
+	},
+}
+
+func init() {
+	Command.AddCommand(upCmd)
+
+	addActivationFlags(upCmd)
+}
@@ -1,20 +1,102 @@
 package wifi
 
 import (
+	"fmt"
+	"os"
+
+	"github.com/markusressel/system-control/internal/configuration"
+	"github.com/markusressel/system-control/internal/persistence"
+	"github.com/markusressel/system-control/internal/wifi"
 	"github.com/spf13/cobra"
 )
 
+const (
+	KeyHotspotPassword = "hotspot.password"
+)
+
+var wifiInterface string
+var hotspotSSID string
+
 var Command = &cobra.Command{
 	Use:   "hotspot",
 	Short: "Control WiFi hotspots",
 	Long:  ``,
 }
 
+// loadHotspotConfig returns the hotspot section of the configuration, with flags applied and
+// the interface and SSID detected if they are not configured
+func loadHotspotConfig() (configuration.HotspotConfig, error) {
+	config := configuration.CurrentConfig.Hotspot
+	err := configuration.ValidateHotspot()
+	if err != nil {
+		return config, err
+	}
+	if wifiInterface != "" {
+		config.Interface = wifiInterface
+	}
+	if hotspotSSID != "" {
+		config.SSID = hotspotSSID
+	}
+
+	if config.Interface == "" {
+		detected, err := wifi.FindWifiInterface()
+		if err != nil {
+			return config, err
+		}
+		config.Interface = detected
+	}
+	if config.SSID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return config, fmt.Errorf("failed to determine hotspot SSID: %w", err)
+		}
+		config.SSID = hostname
+	}
+	return config, nil
+}
+
+// getHotspotPassword returns the configured password of the hotspot, or an empty string for open hotspots.
+// If none is configured, a random password is generated on first use and persisted for future use.
+func getHotspotPassword(config configuration.HotspotConfig) (string, error) {
+	if config.Security == wifi.HotspotSecurityOpen {
+		return "", nil
+	}
+	if config.Password != "" {
+		return config.Password, nil
+	}
+
+	password, err := persistence.ReadString(KeyHotspotPassword)
+	if err == nil && password != "" {
+		return password, nil
+	}
+
+	password, err = wifi.GenerateHotspotPassword()
+	if err != nil {
+		return "", err
+	}
+	err = persistence.SaveString(KeyHotspotPassword, password)
+	if err != nil {
+		return "", err
+	}
+	fmt.Printf("Generated hotspot password: %s\n", password)
+	return password, nil
+}
+
+func createHotspotConfigName(hotspotSSID string) string {
+	return fmt.Sprintf("%s Hotspot", hotspotSSID)
+}
+
 func init() {
 	Command.PersistentFlags().StringVarP(
 		&wifiInterface,
 		"interface", "i",
 		"",
-		"WiFi interface to use for the hotspot",
+		"WiFi interface to use for the hotspot (default: first WiFi device)",
+	)
+	Command.PersistentFlags().StringVarP(
+		&hotspotSSID,
+		"ssid", "s",
+		"",
+		"SSID of the hotspot (default: hostname)",
 	)
 }
@@ -2,6 +2,7 @@ package wifi
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/elliotchance/orderedmap/v2"
 	"github.com/markusressel/system-control/internal/util"
@@ -9,12 +10,19 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var clientsStats bool
+
 var clientsCmd = &cobra.Command{
 	Use:   "clients",
 	Short: "List currently connected Hotspot Client Devices",
 	Long:  ``,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		isHotspotUp, err := wifi.IsHotspotUp(hotspotSSID)
+		config, err := loadHotspotConfig()
+		if err != nil {
+			return err
+		}
+
+		isHotspotUp, err := wifi.IsHotspotUp(config.SSID)
 		if err != nil {
 			return err
 		}
@@ -23,13 +31,17 @@ var clientsCmd = &cobra.Command{
 			return nil
 		}
 
-		hotspotDevices, err := wifi.GetConnectedHotspotDevices(wifiInterface, hotspotSSID)
+		hotspotDevices, err := wifi.GetConnectedHotspotDevices(config.Interface, config.SSID)
 		if err != nil {
 			return err
 		}
 
+		blockedClients, err := loadBlockedClients()
+		if err != nil {
+			return err
+		}
 		for _, device := range hotspotDevices {
-			printHotspotDevice(device)
+			printHotspotDevice(device, isBlocked(blockedClients, device.MAC), clientsStats)
 		}
 
 		return err
@@ -39,18 +51,23 @@ var clientsCmd = &cobra.Command{
 func init() {
 	Command.AddCommand(clientsCmd)
 
-	clientsCmd.PersistentFlags().StringVarP(
-		&hotspotSSID,
-		"ssid", "s",
-		"",
-		"SSID of the hotspot",
-	)
+	clientsCmd.Flags().BoolVarP(&clientsStats, "stats", "S", false, "Show traffic statistics, signal and connection duration")
 }
 
-func printHotspotDevice(device wifi.HotspotLease) {
+func printHotspotDevice(device wifi.HotspotLease, blocked bool, stats bool) {
 	properties := orderedmap.NewOrderedMap[string, string]()
 	properties.Set("IP", device.IP)
 	properties.Set("MAC", device.MAC)
+	properties.Set("Blocked", strconv.FormatBool(blocked))
+	if stats {
+		station := device.Station
+		properties.Set("Signal", fmt.Sprintf("%d dBm (avg %d dBm)", station.Signal, station.SignalAvg))
+		properties.Set("Connected", station.ConnectedTime.String())
+		properties.Set("Downloaded", util.FormatBytes(float64(station.TxBytes)))
+		properties.Set("Uploaded", util.FormatBytes(float64(station.RxBytes)))
+		properties.Set("Bitrate", fmt.Sprintf("%.1f MBit/s down, %.1f MBit/s up", station.TxBitrate, station.RxBitrate))
+		properties.Set("Retries", fmt.Sprintf("%d (%d failed)", station.TxRetries, station.TxFailed))
+	}
 
 	util.PrintFormattedTableOrdered(device.Name, properties)
 }
@@ -0,0 +1,169 @@
+package wifi
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/markusressel/system-control/internal/configuration"
+	"github.com/markusressel/system-control/internal/persistence"
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+const (
+	KeyHotspotBlockedClients = "hotspot.blocked"
+)
+
+// BlockedClient is an entry of the persisted block list
+type BlockedClient struct {
+	MAC  string
+	Name string
+}
+
+var blockCmd = &cobra.Command{
+	Use:   "block <name|mac|ip>",
+	Short: "Block all network traffic of a hotspot client",
+	Long: `Block all network traffic of a hotspot client.
+
+Clients are blocked by their MAC address, so they stay blocked when they reconnect with a different IP address.
+The block list is persisted in /var/lib/system-control and applied again when the hotspot is turned on.
+NOTE: requires root privileges`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadHotspotConfig()
+		if err != nil {
+			return err
+		}
+		client, err := findClient(config, args[0], nil)
+		if err != nil {
+			return err
+		}
+
+		err = wifi.BlockHost(config.Firewall, client.MAC)
+		if err != nil {
+			return err
+		}
+
+		blockedClients, err := loadBlockedClients()
+		if err != nil {
+			return err
+		}
+		if !isBlocked(blockedClients, client.MAC) {
+			blockedClients = append(blockedClients, BlockedClient{MAC: client.MAC, Name: client.Name})
+		}
+		err = persistence.SaveSystemStruct(KeyHotspotBlockedClients, blockedClients)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Blocked %s\n", formatClient(client))
+		return nil
+	},
+}
+
+var unblockCmd = &cobra.Command{
+	Use:   "unblock <name|mac|ip>",
+	Short: "Unblock a previously blocked hotspot client",
+	Long: `Unblock a previously blocked hotspot client.
+NOTE: requires root privileges`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadHotspotConfig()
+		if err != nil {
+			return err
+		}
+		blockedClients, err := loadBlockedClients()
+		if err != nil {
+			return err
+		}
+		client, err := findClient(config, args[0], blockedClients)
+		if err != nil {
+			return err
+		}
+
+		err = wifi.UnblockHost(config.Firewall, client.MAC)
+		if err != nil {
+			return err
+		}
+
+		blockedClients = slices.DeleteFunc(blockedClients, func(blocked BlockedClient) bool {
+			return strings.EqualFold(blocked.MAC, client.MAC)
+		})
+		err = persistence.SaveSystemStruct(KeyHotspotBlockedClients, blockedClients)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Unblocked %s\n", formatClient(client))
+		return nil
+	},
+}
+
+// findClient searches the given query in the connected hotspot clients and the given block list.
+// If no client matches, the query is accepted as a MAC address.
+func findClient(config configuration.HotspotConfig, query string, blockedClients []BlockedClient) (wifi.HotspotLease, error) {
+	clients, err := wifi.GetConnectedHotspotDevices(config.Interface, config.SSID)
+	if err != nil {
+		return wifi.HotspotLease{}, err
+	}
+	for _, blocked := range blockedClients {
+		clients = append(clients, wifi.HotspotLease{MAC: blocked.MAC, Name: blocked.Name})
+	}
+
+	client, found := wifi.FindHotspotClient(clients, query)
+	if found {
+		return client, nil
+	}
+	if _, err := net.ParseMAC(query); err == nil {
+		return wifi.HotspotLease{MAC: query}, nil
+	}
+	return wifi.HotspotLease{}, fmt.Errorf("client not found: %s", query)
+}
+
+func formatClient(client wifi.HotspotLease) string {
+	if client.Name == "" {
+		return client.MAC
+	}
+	return fmt.Sprintf("'%s' (%s)", client.Name, client.MAC)
+}
+
+// loadBlockedClients returns the persisted block list, which is empty if nothing has been blocked yet
+func loadBlockedClients() ([]BlockedClient, error) {
+	var blockedClients []BlockedClient
+	err := persistence.ReadSystemStruct(KeyHotspotBlockedClients, &blockedClients)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read the block list: %w", err)
+	}
+	return blockedClients, nil
+}
+
+// applyBlockedClients blocks all clients of the persisted block list
+func applyBlockedClients(config configuration.HotspotConfig) error {
+	blockedClients, err := loadBlockedClients()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, blocked := range blockedClients {
+		err := wifi.BlockHost(config.Firewall, blocked.MAC)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to block %s: %w", blocked.MAC, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func isBlocked(blockedClients []BlockedClient, mac string) bool {
+	return slices.ContainsFunc(blockedClients, func(blocked BlockedClient) bool {
+		return strings.EqualFold(blocked.MAC, mac)
+	})
+}
+
+func init() {
+	clientsCmd.AddCommand(blockCmd)
+	clientsCmd.AddCommand(unblockCmd)
+}
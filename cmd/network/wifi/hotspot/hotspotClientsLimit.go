@@ -0,0 +1,67 @@
+package wifi
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var limitDown int
+var limitUp int
+
+var limitCmd = &cobra.Command{
+	Use:   "limit <name|mac|ip>",
+	Short: "Limit the bandwidth of a hotspot client",
+	Long: `Limit the download and upload bandwidth of a hotspot client.
+
+Limits are given in kbit/s, a limit of 0 removes the respective limit.
+NOTE: requires root privileges`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadHotspotConfig()
+		if err != nil {
+			return err
+		}
+
+		clients, err := wifi.GetConnectedHotspotDevices(config.Interface, config.SSID)
+		if err != nil {
+			return err
+		}
+		client, found := wifi.FindHotspotClient(clients, args[0])
+		if !found {
+			if net.ParseIP(args[0]).To4() == nil {
+				return fmt.Errorf("client not found: %s", args[0])
+			}
+			client = wifi.HotspotLease{IP: args[0]}
+		}
+
+		err = wifi.LimitHost(config.Firewall, client.IP, limitDown, limitUp)
+		if err != nil {
+			return err
+		}
+
+		name := client.Name
+		if name == "" {
+			name = client.IP
+		}
+		fmt.Printf("Limits of '%s': download %s, upload %s\n", name, formatLimit(limitDown), formatLimit(limitUp))
+		return nil
+	},
+}
+
+func formatLimit(kbit int) string {
+	if kbit <= 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d kbit/s", kbit)
+}
+
+func init() {
+	clientsCmd.AddCommand(limitCmd)
+
+	limitCmd.Flags().IntVarP(&limitDown, "down", "d", 0, "Download limit in kbit/s, 0 removes the limit")
+	limitCmd.Flags().IntVarP(&limitUp, "up", "u", 0, "Upload limit in kbit/s, 0 removes the limit")
+	limitCmd.MarkFlagsOneRequired("down", "up")
+}
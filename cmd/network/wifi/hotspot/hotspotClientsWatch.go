@@ -0,0 +1,107 @@
+package wifi
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var watchInterval time.Duration
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Continuously show the throughput of all hotspot clients",
+	Long: `Continuously show the current throughput, the total traffic, the signal and the connection duration
+of all hotspot clients. Clients are sorted by their total download, so the device using the most data
+is shown first.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if watchInterval <= 0 {
+			return errors.New("interval must be positive")
+		}
+		config, err := loadHotspotConfig()
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+
+		previous := map[string]wifi.StationInfo{}
+		previousTime := time.Time{}
+		for {
+			now := time.Now()
+			clients, err := wifi.GetConnectedHotspotDevices(config.Interface, config.SSID)
+			if err != nil {
+				return err
+			}
+
+			printClientThroughput(clients, previous, now.Sub(previousTime), now)
+
+			previous = map[string]wifi.StationInfo{}
+			for _, client := range clients {
+				previous[client.MAC] = client.Station
+			}
+			previousTime = now
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+func printClientThroughput(clients []wifi.HotspotLease, previous map[string]wifi.StationInfo, elapsed time.Duration, now time.Time) {
+	slices.SortFunc(clients, func(a, b wifi.HotspotLease) int {
+		return cmp.Or(
+			-1*cmp.Compare(a.Station.TxBytes, b.Station.TxBytes),
+			util.CompareIgnoreCase(a.Name, b.Name),
+		)
+	})
+
+	// clear the screen
+	fmt.Print("\033[H\033[2J")
+	fmt.Printf("%s  %d client(s)\n\n", now.Format("2006-01-02 15:04:05"), len(clients))
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "Name\tIP\tSignal\tConnected\tDown/s\tUp/s\tDownloaded\tUploaded\t")
+	for _, client := range clients {
+		down, up := 0.0, 0.0
+		if last, ok := previous[client.MAC]; ok {
+			down, up = wifi.Throughput(last, client.Station, elapsed)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d dBm\t%s\t%s\t%s\t%s\t%s\t\n",
+			client.Name,
+			client.IP,
+			client.Station.Signal,
+			client.Station.ConnectedTime,
+			util.FormatBytes(down),
+			util.FormatBytes(up),
+			util.FormatBytes(float64(client.Station.TxBytes)),
+			util.FormatBytes(float64(client.Station.RxBytes)),
+		)
+	}
+	_ = w.Flush()
+}
+
+func init() {
+	clientsCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 2*time.Second, "Time between two samples")
+}
@@ -14,33 +14,34 @@ var offCmd = &cobra.Command{
 	Short: "Turn off the WiFi Hotspot",
 	Long:  ``,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if wifiInterface == "" {
-			wifiInterface = "wlo1"
-		}
-		if hotspotSSID == "" {
-			hotspotSSID = "M16"
+		config, err := loadHotspotConfig()
+		if err != nil {
+			return err
 		}
 
-		hotspotName := createHotspotConfigName(hotspotSSID)
+		hotspotName := createHotspotConfigName(config.SSID)
 
 		if !force {
 			// check if there are still devices connected
-			hotspotDevices, err := wifi.GetConnectedHotspotDevices(wifiInterface, hotspotSSID)
+			hotspotDevices, err := wifi.GetConnectedHotspotDevices(config.Interface, config.SSID)
 			if err != nil {
 				return err
 			}
 
 			if len(hotspotDevices) > 0 {
+				blockedClients, err := loadBlockedClients()
+				if err != nil {
+					return err
+				}
 				for _, device := range hotspotDevices {
-					printHotspotDevice(device)
+					printHotspotDevice(device, isBlocked(blockedClients, device.MAC), false)
 				}
 				println("There are still devices connected to the hotspot. Please disconnect them first or use --force parameter.")
 				os.Exit(1)
 			}
 		}
 
-		err := wifi.TurnOffHotspot(hotspotName)
-		return err
+		return wifi.TurnOffHotspot(hotspotName)
 	},
 }
 
@@ -53,11 +54,4 @@ func init() {
 		false,
 		"Force turn off the Hotspot",
 	)
-
-	offCmd.PersistentFlags().StringVarP(
-		&hotspotSSID,
-		"ssid", "s",
-		"",
-		"SSID of the hotspot",
-	)
 }
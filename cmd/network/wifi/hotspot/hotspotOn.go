@@ -1,59 +1,130 @@
 package wifi
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 
+	"github.com/markusressel/system-control/internal/configuration"
 	"github.com/markusressel/system-control/internal/wifi"
 	"github.com/spf13/cobra"
 )
 
-var wifiInterface string
-var hotspotSSID string
 var password string
+var band string
+var channel string
+var security string
 
 var onCmd = &cobra.Command{
 	Use:   "on",
 	Short: "Turn on the WiFi Hotspot",
-	Long:  ``,
+	Long: `Turn on the WiFi Hotspot.
+
+Settings which are not given as flags are read from the "hotspot" section of the configuration file.
+If no password is configured, a random password is generated on first use (unless the security is "open").`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if wifiInterface == "" {
-			wifiInterface = "wlo1"
+		config, err := loadHotspotConfig()
+		if err != nil {
+			return err
+		}
+		if password != "" {
+			config.Password = password
+		}
+		if cmd.Flags().Changed("band") {
+			config.Band = band
+		}
+		if cmd.Flags().Changed("channel") {
+			config.Channel = channel
+		}
+		if security != "" {
+			config.Security = security
 		}
-		if hotspotSSID == "" {
-			hotspotSSID = "M16"
+		// the flags override the validated configuration, so the result has to be validated again
+		err = configuration.ValidateHotspotConfig(config, cmd.CommandPath())
+		if err != nil {
+			return err
 		}
-		if password == "" {
-			password = "M12345678"
+
+		hotspotPassword, err := getHotspotPassword(config)
+		if err != nil {
+			return err
+		}
+
+		channelNumber, err := parseChannel(config.Band, config.Channel)
+		if err != nil {
+			return err
+		}
+
+		err = wifi.TurnOnHotspot(wifi.HotspotOptions{
+			Name:      createHotspotConfigName(config.SSID),
+			Interface: config.Interface,
+			SSID:      config.SSID,
+			Password:  hotspotPassword,
+			Band:      config.Band,
+			Channel:   channelNumber,
+			Security:  config.Security,
+		})
+		if err != nil {
+			return err
 		}
-		hotspotName := createHotspotConfigName(hotspotSSID)
 
-		err := wifi.TurnOnHotspot(
-			hotspotName,
-			wifiInterface,
-			hotspotSSID,
-			password,
-		)
-		return err
+		// firewall rules are lost on reboot, so the block list has to be applied again
+		err = applyBlockedClients(config)
+		if err != nil {
+			fmt.Printf("WARNING: failed to apply the block list: %v\n", err)
+		}
+		return nil
 	},
 }
 
-func createHotspotConfigName(hotspotSSID string) string {
-	return fmt.Sprintf("%s Hotspot", hotspotSSID)
+// parseChannel parses the channel parameter, "auto" selects the least congested channel of the given band
+func parseChannel(band string, channel string) (int, error) {
+	if channel == "" {
+		return 0, nil
+	}
+	if band == wifi.BandAny {
+		return 0, errors.New("a band is required to select a channel")
+	}
+	if channel == "auto" {
+		recommended, err := wifi.RecommendChannel(band)
+		if err != nil {
+			return 0, err
+		}
+		fmt.Printf("Using channel %d\n", recommended)
+		return recommended, nil
+	}
+	channelNumber, err := strconv.Atoi(channel)
+	if err != nil || channelNumber <= 0 {
+		return 0, fmt.Errorf("invalid channel: %s", channel)
+	}
+	return channelNumber, nil
 }
 
 func init() {
 	Command.AddCommand(onCmd)
 
-	onCmd.PersistentFlags().StringVarP(
-		&hotspotSSID,
-		"hotspotSSID", "s",
-		"",
-		"SSID of the hotspot",
-	)
-	onCmd.PersistentFlags().StringVarP(
+	onCmd.Flags().StringVarP(
 		&password,
 		"password", "p",
 		"",
 		"Password of the hotspot",
 	)
+	onCmd.Flags().StringVarP(
+		&band,
+		"band", "b",
+		wifi.BandAny,
+		"Band of the hotspot (2.4 or 5)",
+	)
+	onCmd.Flags().StringVarP(
+		&channel,
+		"channel", "c",
+		"",
+		"Channel of the hotspot, \"auto\" to use the least congested channel (requires --band)",
+	)
+	onCmd.Flags().StringVar(
+		&security,
+		"security",
+		"",
+		"Security of the hotspot (wpa2, wpa3 or open)",
+	)
 }
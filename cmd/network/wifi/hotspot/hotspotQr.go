@@ -0,0 +1,61 @@
+package wifi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var qrOutput string
+var qrSize int
+
+var qrCmd = &cobra.Command{
+	Use:   "qr",
+	Short: "Show a QR code to join the hotspot",
+	Long: `Show a QR code which allows phones to join the hotspot without typing the password.
+
+If the hotspot connection profile exists, its settings are used. Otherwise the QR code is created
+from the hotspot configuration.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadHotspotConfig()
+		if err != nil {
+			return err
+		}
+
+		var payload string
+		connection, err := wifi.FindConnection(createHotspotConfigName(config.SSID))
+		if err == nil {
+			payload, err = wifi.ConnectionQRCodePayload(connection)
+		} else if errors.Is(err, wifi.ErrConnectionNotFound) {
+			var hotspotPassword string
+			hotspotPassword, err = getHotspotPassword(config)
+			if err == nil {
+				payload, err = wifi.HotspotQRCodePayload(config.SSID, config.Security, hotspotPassword)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if qrOutput == "" {
+			return util.PrintQRCode(payload)
+		}
+		err = util.WriteQRCodePNG(payload, qrOutput, qrSize)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("QR code written to %s\n", qrOutput)
+		return nil
+	},
+}
+
+func init() {
+	Command.AddCommand(qrCmd)
+
+	qrCmd.Flags().StringVarP(&qrOutput, "output", "o", "", "Write the QR code to the given PNG file instead of printing it")
+	qrCmd.Flags().IntVar(&qrSize, "size", 512, "Width and height of the PNG file in pixels")
+}
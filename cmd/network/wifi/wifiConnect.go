@@ -1,20 +1,108 @@
 package wifi
 
 import (
+	"bufio"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/markusressel/system-control/internal/util"
 	"github.com/markusressel/system-control/internal/wifi"
 	"github.com/spf13/cobra"
 )
 
+var (
+	connectPassword          string
+	connectPasswordStdin     bool
+	connectPasswordFile      string
+	connectHidden            bool
+	connectBSSID             string
+	connectBand              string
+	connectInterface         string
+	connectEAP               string
+	connectPhase2            string
+	connectIdentity          string
+	connectAnonymousIdentity string
+	connectCACert            string
+	connectTimeout           time.Duration
+)
+
 var connectCmd = &cobra.Command{
-	Use:   "connect",
-	Short: "Connect to a known WiFi network",
-	Long:  ``,
+	Use:   "connect <ssid|profile>",
+	Short: "Connect to a WiFi network",
+	Long: `Connect to a WiFi network without any interactive prompts.
+
+If a WiFi connection profile with the given name or UUID exists and no credentials are given, the profile is activated.
+Otherwise a connection profile is created (or updated) using the given credentials.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		networkName := args[0]
-		return wifi.Connect(networkName)
+		password, err := readConnectPassword()
+		if err != nil {
+			return err
+		}
+
+		options := wifi.ConnectOptions{
+			SSID:      args[0],
+			Password:  password,
+			Hidden:    connectHidden,
+			BSSID:     connectBSSID,
+			Band:      connectBand,
+			Interface: connectInterface,
+			Timeout:   connectTimeout,
+		}
+		if connectEAP != "" {
+			options.Enterprise = &wifi.EnterpriseOptions{
+				EAP:               strings.ToLower(connectEAP),
+				Phase2:            connectPhase2,
+				Identity:          connectIdentity,
+				AnonymousIdentity: connectAnonymousIdentity,
+				CACert:            connectCACert,
+			}
+		}
+
+		return wifi.ConnectWithOptions(options)
 	},
 }
 
+// readConnectPassword returns the password given via flag, stdin or file
+func readConnectPassword() (string, error) {
+	switch {
+	case connectPasswordStdin:
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && len(line) <= 0 {
+			return "", errors.New("failed to read password from stdin")
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	case connectPasswordFile != "":
+		content, err := util.ReadTextFromFile(connectPasswordFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(content, "\r\n"), nil
+	default:
+		return connectPassword, nil
+	}
+}
+
 func init() {
 	Command.AddCommand(connectCmd)
+
+	connectCmd.Flags().StringVarP(&connectPassword, "password", "p", "", "Password of the network")
+	connectCmd.Flags().BoolVar(&connectPasswordStdin, "password-stdin", false, "Read the password from stdin")
+	connectCmd.Flags().StringVar(&connectPasswordFile, "password-file", "", "Read the password from the given file")
+	connectCmd.MarkFlagsMutuallyExclusive("password", "password-stdin", "password-file")
+
+	connectCmd.Flags().BoolVar(&connectHidden, "hidden", false, "Connect to a network that does not broadcast its SSID")
+	connectCmd.Flags().StringVarP(&connectBSSID, "bssid", "b", "", "Only connect to the access point with the given BSSID")
+	connectCmd.Flags().StringVarP(&connectBand, "band", "B", wifi.BandAny, "Only connect using the given band (2.4, 5 or 6)")
+	connectCmd.Flags().StringVarP(&connectInterface, "interface", "i", "", "WiFi device to use (default: first WiFi device)")
+	connectCmd.Flags().DurationVarP(&connectTimeout, "timeout", "t", 60*time.Second, "Maximum time to wait for the connection to be established")
+
+	connectCmd.Flags().StringVar(&connectEAP, "eap", "", "EAP method for WPA2/WPA3-Enterprise networks (peap or ttls)")
+	connectCmd.Flags().StringVar(&connectPhase2, "phase2", "mschapv2", "Inner authentication method for WPA2/WPA3-Enterprise networks")
+	connectCmd.Flags().StringVar(&connectIdentity, "identity", "", "Identity for WPA2/WPA3-Enterprise networks")
+	connectCmd.Flags().StringVar(&connectAnonymousIdentity, "anonymous-identity", "", "Anonymous (outer) identity for WPA2/WPA3-Enterprise networks")
+	connectCmd.Flags().StringVar(&connectCACert, "ca-cert", "", "CA certificate used to verify the authentication server of WPA2/WPA3-Enterprise networks")
+	connectCmd.MarkFlagsRequiredTogether("eap", "identity")
 }
@@ -0,0 +1,99 @@
+package wifi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var (
+	monitorInterval  time.Duration
+	monitorThreshold int
+	monitorInterface string
+	monitorLogFile   string
+)
+
+var monitorCmd = &cobra.Command{
+	Use:   "monitor",
+	Short: "Continuously print the signal of the connected WiFi network and log roaming events",
+	Long: `Continuously print the signal strength, BSSID and frequency of the connected WiFi network.
+
+Roaming between access points of the same network, network changes and disconnects are logged as events.
+A warning is printed when the signal drops below the given threshold. Events can additionally be
+appended to a log file.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if monitorInterval <= 0 {
+			return errors.New("interval must be positive")
+		}
+
+		var eventLog io.Writer = io.Discard
+		if monitorLogFile != "" {
+			file, err := os.OpenFile(monitorLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			eventLog = file
+		}
+
+		monitor := wifi.SignalMonitor{Threshold: monitorThreshold}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		ticker := time.NewTicker(monitorInterval)
+		defer ticker.Stop()
+
+		for {
+			now := time.Now()
+			network, err := wifi.GetActiveNetwork(monitorInterface)
+			if err != nil {
+				return err
+			}
+
+			timestamp := now.Format("2006-01-02 15:04:05")
+			for _, event := range monitor.Update(network, now) {
+				line := fmt.Sprintf("[%s] %s", timestamp, event)
+				if event.Type == wifi.MonitorEventSignalLow {
+					line = fmt.Sprintf("[%s] WARNING: %s (threshold: %d%%)", timestamp, event, monitorThreshold)
+				}
+				fmt.Println(line)
+				_, _ = fmt.Fprintln(eventLog, line)
+			}
+
+			if network != nil {
+				fmt.Printf(
+					"[%s] %s  %s  %s (channel %d)  signal %3d%% %s  rate %s\n",
+					timestamp, network.SSID, network.BSSID, network.Frequency, network.Channel,
+					network.Signal, network.Bars, network.Rate,
+				)
+			} else {
+				fmt.Printf("[%s] not connected\n", timestamp)
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+func init() {
+	Command.AddCommand(monitorCmd)
+
+	monitorCmd.Flags().DurationVar(&monitorInterval, "interval", 2*time.Second, "Interval in which the signal is sampled")
+	monitorCmd.Flags().IntVarP(&monitorThreshold, "threshold", "t", 30, "Signal strength (in percent) below which a warning is printed")
+	monitorCmd.Flags().StringVarP(&monitorInterface, "interface", "i", "", "WiFi device to monitor (default: first connected WiFi device)")
+	monitorCmd.Flags().StringVarP(&monitorLogFile, "log", "l", "", "Append events to the given file")
+}
@@ -0,0 +1,50 @@
+package wifi
+
+import (
+	"fmt"
+
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var (
+	qrOutput string
+	qrSize   int
+)
+
+var qrCmd = &cobra.Command{
+	Use:   "qr <connection>",
+	Short: "Show a QR code to share a saved WiFi connection",
+	Long: `Show a QR code which allows phones to join the network of a saved WiFi connection profile.
+
+Reading the password of system owned connection profiles may require root privileges.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		connection, err := wifi.FindConnection(args[0])
+		if err != nil {
+			return err
+		}
+		payload, err := wifi.ConnectionQRCodePayload(connection)
+		if err != nil {
+			return err
+		}
+
+		if qrOutput == "" {
+			return util.PrintQRCode(payload)
+		}
+		err = util.WriteQRCodePNG(payload, qrOutput, qrSize)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("QR code written to %s\n", qrOutput)
+		return nil
+	},
+}
+
+func init() {
+	Command.AddCommand(qrCmd)
+
+	qrCmd.Flags().StringVarP(&qrOutput, "output", "o", "", "Write the QR code to the given PNG file instead of printing it")
+	qrCmd.Flags().IntVar(&qrSize, "size", 512, "Width and height of the PNG file in pixels")
+}
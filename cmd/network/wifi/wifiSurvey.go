@@ -0,0 +1,101 @@
+package wifi
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/markusressel/system-control/internal/wifi"
+	"github.com/spf13/cobra"
+)
+
+var (
+	surveyBand   string
+	surveyAll    bool
+	surveyRescan bool
+)
+
+var surveyCmd = &cobra.Command{
+	Use:   "survey",
+	Short: "Show the congestion of all WiFi channels",
+	Long: `Show the number of networks, the strongest signal and the overlapping bandwidth of each channel
+in the 2.4, 5 and 6 GHz bands and recommend the least congested channel.
+
+The score of a channel is the sum of the signal strength of all networks overlapping with it, weighted by
+the share of the channel they overlap. Networks on adjacent channels are additionally weighted by half. Lower is better.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if surveyRescan {
+			err := wifi.Rescan(15 * time.Second)
+			if err != nil {
+				return err
+			}
+		}
+
+		networks, err := wifi.GetNetworks()
+		if err != nil {
+			return err
+		}
+
+		reports := wifi.Survey(networks)
+		printed := 0
+		for _, report := range reports {
+			if surveyBand != wifi.BandAny && report.Band != surveyBand {
+				continue
+			}
+			if printed > 0 {
+				fmt.Println()
+			}
+			printBandReport(report)
+			printed++
+		}
+		if printed == 0 {
+			return fmt.Errorf("unsupported band: %s", surveyBand)
+		}
+		return nil
+	},
+}
+
+func printBandReport(report wifi.BandReport) {
+	fmt.Printf("%s GHz\n", report.Band)
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "  Channel\tFrequency\tNetworks\tOverlap\tStrongest\tScore\t")
+	for _, channel := range report.Channels {
+		if !surveyAll && !channel.Candidate && channel.OverlappingBandwidth <= 0 {
+			continue
+		}
+		name := fmt.Sprintf("%d", channel.Channel)
+		if channel.DFS {
+			name += " (DFS)"
+		}
+		if channel.Candidate && channel.Channel == report.Recommended.Channel {
+			name += " *"
+		}
+		_, _ = fmt.Fprintf(w, "  %s\t%d MHz\t%d\t%d MHz\t%d\t%.2f\t\n",
+			name,
+			channel.Frequency,
+			channel.Networks,
+			channel.OverlappingBandwidth,
+			channel.StrongestSignal,
+			channel.Score,
+		)
+	}
+	_ = w.Flush()
+
+	if report.Recommended.Candidate {
+		fmt.Printf("Recommended channel: %d\n", report.Recommended.Channel)
+		if report.Band != wifi.Band6G {
+			fmt.Printf("  system-control network wifi hotspot on --band %s --channel %d\n", report.Band, report.Recommended.Channel)
+		}
+	}
+}
+
+func init() {
+	Command.AddCommand(surveyCmd)
+
+	surveyCmd.Flags().StringVarP(&surveyBand, "band", "B", wifi.BandAny, "Only show the given band (2.4, 5 or 6)")
+	surveyCmd.Flags().BoolVarP(&surveyAll, "all", "a", false, "Show all channels, including unused non-recommendable ones")
+	surveyCmd.Flags().BoolVarP(&surveyRescan, "rescan", "r", false, "Request a new scan before creating the report")
+}
@@ -38,9 +38,9 @@ var RootCmd = &cobra.Command{
 	SilenceErrors: true,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// 1. Locate and read the file into Viper's internal cache
-		configPath := configuration.DetectAndReadConfigFile()
+		configuration.DetectAndReadConfigFile()
 		configuration.LoadConfig()
-		return configuration.Validate(configPath)
+		return nil
 	},
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
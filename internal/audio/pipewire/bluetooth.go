@@ -0,0 +1,180 @@
+package pipewire
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/markusressel/system-control/internal/util"
+)
+
+const (
+	MediaClassAudioSource = "Audio/Source"
+
+	// BluetoothModeA2DP is the high quality playback profile without microphone
+	BluetoothModeA2DP = "a2dp"
+	// BluetoothModeHFP is the headset profile (HSP/HFP) with microphone and low audio quality
+	BluetoothModeHFP = "hfp"
+	// BluetoothModeAuto selects the profile with the highest priority, which is usually A2DP with the best codec
+	BluetoothModeAuto = "auto"
+
+	bluetoothAddressProp = "api.bluez5.address"
+)
+
+var bluetoothCodecPattern = regexp.MustCompile(`codec ([^)]+)\)`)
+
+// BluetoothProfile is a device profile of a Bluetooth audio device
+type BluetoothProfile struct {
+	DeviceProfile
+	// Mode is either BluetoothModeA2DP or BluetoothModeHFP
+	Mode string
+	// Codec is the audio codec used by the profile, f.ex. "SBC", "AAC", "LDAC" or "aptX", empty if unknown
+	Codec string
+}
+
+// FindBluetoothDevice returns the device of the Bluetooth device with the given address
+func (state *GraphState) FindBluetoothDevice(address string) (InterfaceDevice, error) {
+	for _, device := range state.Devices {
+		deviceAddress, _ := device.Info.Props[bluetoothAddressProp].(string)
+		if strings.EqualFold(deviceAddress, address) {
+			return device, nil
+		}
+	}
+	return InterfaceDevice{}, fmt.Errorf("no audio device found for bluetooth device %s", address)
+}
+
+// FindBluetoothNodes returns the nodes of the Bluetooth device with the given address and media class
+func (state *GraphState) FindBluetoothNodes(address string, mediaClass string) []InterfaceNode {
+	result := make([]InterfaceNode, 0)
+	for _, node := range state.Nodes {
+		nodeAddress, _ := node.Info.Props[bluetoothAddressProp].(string)
+		nodeMediaClass, _ := node.GetMediaClass()
+		if strings.EqualFold(nodeAddress, address) && nodeMediaClass == mediaClass {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// GetBluetoothProfiles returns all available A2DP and HFP profiles of the device
+func (d InterfaceDevice) GetBluetoothProfiles() []BluetoothProfile {
+	result := make([]BluetoothProfile, 0)
+	for _, profile := range d.Info.Params.EnumProfile {
+		if profile.Available == "no" {
+			continue
+		}
+		var mode string
+		switch {
+		case strings.HasPrefix(profile.Name, "a2dp-"):
+			mode = BluetoothModeA2DP
+		case strings.HasPrefix(profile.Name, "headset-head-unit"):
+			mode = BluetoothModeHFP
+		default:
+			continue
+		}
+		bluetoothProfile := BluetoothProfile{DeviceProfile: profile, Mode: mode}
+		if match := bluetoothCodecPattern.FindStringSubmatch(profile.Description); match != nil {
+			bluetoothProfile.Codec = match[1]
+		}
+		result = append(result, bluetoothProfile)
+	}
+	return result
+}
+
+// FindBluetoothProfile returns the profile with the highest priority of the given mode.
+// If a codec is given, only profiles using this codec are considered.
+func (d InterfaceDevice) FindBluetoothProfile(mode string, codec string) (BluetoothProfile, error) {
+	switch mode {
+	case BluetoothModeA2DP, BluetoothModeHFP, BluetoothModeAuto:
+	default:
+		return BluetoothProfile{}, fmt.Errorf("unsupported bluetooth audio mode: %s", mode)
+	}
+
+	var result *BluetoothProfile
+	for _, profile := range d.GetBluetoothProfiles() {
+		if mode != BluetoothModeAuto && profile.Mode != mode {
+			continue
+		}
+		if codec != "" && !util.EqualsIgnoreCase(profile.Codec, codec) {
+			continue
+		}
+		if result == nil || profile.Priority > result.Priority {
+			result = &profile
+		}
+	}
+	if result == nil && codec != "" {
+		return BluetoothProfile{}, fmt.Errorf("codec %s is not available for mode %s", codec, mode)
+	} else if result == nil {
+		return BluetoothProfile{}, fmt.Errorf("no profile available for mode %s", mode)
+	}
+	return *result, nil
+}
+
+// SetBluetoothDefaults makes the sink and, if available, the source of the Bluetooth device with the given
+// address the default ones. After a profile switch it may take a moment until the nodes are available,
+// so the PipeWire state is polled until the given timeout is reached.
+func SetBluetoothDefaults(address string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		state := PwDump()
+		sinks := state.FindBluetoothNodes(address, MediaClassAudioSink)
+		if len(sinks) > 0 {
+			err := state.SwitchSinkTo(sinks[0])
+			if err != nil {
+				return err
+			}
+			sources := state.FindBluetoothNodes(address, MediaClassAudioSource)
+			if len(sources) > 0 {
+				sourceName, err := sources[0].GetName()
+				if err != nil {
+					return err
+				}
+				return setDefaultSource(sourceName)
+			}
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.New("no audio sink found for bluetooth device " + address)
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+// SwitchToPreferredSink makes the sink of the first of the given devices, ordered by priority, which is available
+// the default one, after waiting until the sink of the (newly connected) Bluetooth device with the given address
+// is available or the given timeout is reached.
+func SwitchToPreferredSink(address string, preferred []string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		state := PwDump()
+		if len(state.FindBluetoothNodes(address, MediaClassAudioSink)) > 0 || time.Now().After(deadline) {
+			node, err := state.FindPreferredSink(preferred)
+			if err != nil {
+				return err
+			}
+			return state.SwitchSinkTo(node)
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+// FindPreferredSink returns the sink of the first of the given devices, ordered by priority, which is available.
+// A device is matched by its bluetooth address or a part of the name or description of its sink.
+func (state *GraphState) FindPreferredSink(preferred []string) (InterfaceNode, error) {
+	sinks := state.GetSinkNodes()
+	for _, device := range preferred {
+		for _, sink := range sinks {
+			address, _ := sink.Info.Props[bluetoothAddressProp].(string)
+			name, _ := sink.GetName()
+			description, _ := sink.GetDescription()
+			if util.EqualsIgnoreCase(address, device) ||
+				util.ContainsIgnoreCase(name, device) ||
+				util.ContainsIgnoreCase(description, device) {
+				return sink, nil
+			}
+		}
+	}
+	return InterfaceNode{}, errors.New("no sink of a preferred device found")
+}
@@ -0,0 +1,117 @@
+package pipewire
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readTestState(t *testing.T) GraphState {
+	input, err := os.ReadFile("../../../test/pipewire/pw.dump")
+	assert.NoError(t, err)
+	state, err := parsePwDumpToState(string(input))
+	assert.NoError(t, err)
+	return state
+}
+
+func TestFindBluetoothDevice(t *testing.T) {
+	// GIVEN
+	state := readTestState(t)
+
+	// WHEN
+	device, err := state.FindBluetoothDevice("b8:f8:be:52:03:90")
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, 73, device.Id)
+
+	_, err = state.FindBluetoothDevice("00:11:22:33:44:55")
+	assert.EqualError(t, err, "no audio device found for bluetooth device 00:11:22:33:44:55")
+}
+
+func TestFindBluetoothNodes(t *testing.T) {
+	// GIVEN
+	state := readTestState(t)
+
+	// WHEN
+	sinks := state.FindBluetoothNodes("B8:F8:BE:52:03:90", MediaClassAudioSink)
+
+	// THEN
+	assert.Len(t, sinks, 1)
+	name, err := sinks[0].GetName()
+	assert.NoError(t, err)
+	assert.Equal(t, "bluez_output.B8_F8_BE_52_03_90.1", name)
+}
+
+func TestGetBluetoothProfiles(t *testing.T) {
+	// GIVEN
+	state := readTestState(t)
+	device, err := state.FindBluetoothDevice("B8:F8:BE:52:03:90")
+	assert.NoError(t, err)
+
+	// WHEN
+	profiles := device.GetBluetoothProfiles()
+
+	// THEN
+	var names, modes, codecs []string
+	for _, profile := range profiles {
+		names = append(names, profile.Name)
+		modes = append(modes, profile.Mode)
+		codecs = append(codecs, profile.Codec)
+	}
+	assert.Equal(t, []string{"headset-head-unit", "a2dp-sink-sbc", "a2dp-sink-sbc_xq", "a2dp-sink", "headset-head-unit-cvsd", "headset-head-unit-msbc"}, names)
+	assert.Equal(t, []string{BluetoothModeHFP, BluetoothModeA2DP, BluetoothModeA2DP, BluetoothModeA2DP, BluetoothModeHFP, BluetoothModeHFP}, modes)
+	assert.Equal(t, []string{"", "SBC", "SBC-XQ", "AAC", "CVSD", "mSBC"}, codecs)
+}
+
+func TestFindBluetoothProfile(t *testing.T) {
+	// GIVEN
+	state := readTestState(t)
+	device, err := state.FindBluetoothDevice("B8:F8:BE:52:03:90")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		mode     string
+		codec    string
+		expected string
+	}{
+		{BluetoothModeA2DP, "", "a2dp-sink"},
+		{BluetoothModeA2DP, "sbc", "a2dp-sink-sbc"},
+		{BluetoothModeHFP, "", "headset-head-unit-msbc"},
+		{BluetoothModeHFP, "CVSD", "headset-head-unit-cvsd"},
+		{BluetoothModeAuto, "", "a2dp-sink"},
+	}
+	for _, test := range tests {
+		// WHEN
+		profile, err := device.FindBluetoothProfile(test.mode, test.codec)
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, profile.Name)
+	}
+
+	_, err = device.FindBluetoothProfile(BluetoothModeA2DP, "LDAC")
+	assert.EqualError(t, err, "codec LDAC is not available for mode a2dp")
+	_, err = device.FindBluetoothProfile("hsp", "")
+	assert.EqualError(t, err, "unsupported bluetooth audio mode: hsp")
+}
+
+func TestFindPreferredSink(t *testing.T) {
+	// GIVEN
+	state := readTestState(t)
+
+	// WHEN
+	sink, err := state.FindPreferredSink([]string{"WH-1000XM4", "b8:f8:be:52:03:90", "HDMI"})
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, 65, sink.Id)
+
+	sink, err = state.FindPreferredSink([]string{"Analog Stereo", "LG-TONE-FP9"})
+	assert.NoError(t, err)
+	assert.Equal(t, 59, sink.Id)
+
+	_, err = state.FindPreferredSink([]string{"WH-1000XM4"})
+	assert.EqualError(t, err, "no sink of a preferred device found")
+}
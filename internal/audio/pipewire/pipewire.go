@@ -61,6 +61,12 @@ func setDefaultSink(sinkName string) (err error) {
 	return err
 }
 
+// Switches the default source to the source with the given "node.name"
+func setDefaultSource(sourceName string) (err error) {
+	_, err = util.ExecCommand("pw-metadata", "0", "default.configured.audio.source", `{ "name": "`+sourceName+`" }`)
+	return err
+}
+
 func WpCtlSetVolume(id int, volume float64) error {
 	formattedVolume := fmt.Sprintf("%.3f", volume)
 	return runWpCtl("set-volume", strconv.Itoa(id), formattedVolume)
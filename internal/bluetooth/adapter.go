@@ -1,6 +1,11 @@
 package bluetooth
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/godbus/dbus/v5"
+	bt "tinygo.org/x/bluetooth"
+)
 
 var (
 	// global bluez adapter instance
@@ -14,6 +19,45 @@ func init() {
 	bluez = NewBlueZAdapter()
 }
 
+// GetBluetoothAdapters returns all bluetooth adapters of the system
+func GetBluetoothAdapters() ([]Adapter, error) {
+	return bluez.Adapters()
+}
+
+// UseBus makes all following operations talk to BlueZ using the given bus connection instead of the system bus,
+// f.ex. to use a fake BlueZ service on a private bus. connectPrivate has to open a new connection to the same bus.
+// The returned function restores the previous adapter.
+func UseBus(conn *dbus.Conn, connectPrivate func() (*dbus.Conn, error)) (restore func()) {
+	previous := bluez
+	bluez = NewBlueZAdapterWithConn(conn, connectPrivate)
+	return func() {
+		bluez = previous
+	}
+}
+
+// SelectAdapter selects the adapter used by all following operations, by its name (f.ex. "hci1"), address or alias.
+// An empty value selects the default adapter.
+func SelectAdapter(nameOrAddress string) error {
+	if nameOrAddress == "" {
+		return nil
+	}
+	adapters, err := bluez.Adapters()
+	if err != nil {
+		return err
+	}
+	adapter, err := findAdapter(adapters, nameOrAddress)
+	if err != nil {
+		return err
+	}
+	var scanAdapter *bt.Adapter
+	if bluez.adapter != nil {
+		scanAdapter = bt.NewAdapter(adapter.Name)
+	}
+	bluez = newBlueZAdapter(bluez.conn, bluez.connectPrivate, scanAdapter)
+	bluez.adapterPath = adapter.Path
+	return nil
+}
+
 func TurnOnBluetoothAdapter() error {
 	return bluez.PowerOn()
 }
@@ -53,3 +97,18 @@ func DisconnectBluetoothDevice(device BluetoothDevice) error {
 func RemoveBluetoothDevice(device BluetoothDevice) error {
 	return bluez.Remove(device.Address)
 }
+
+// SetBluetoothDeviceTrusted sets whether the device is trusted, trusted devices may connect without confirmation
+func SetBluetoothDeviceTrusted(device BluetoothDevice, trusted bool) error {
+	return bluez.SetTrusted(device.Address, trusted)
+}
+
+// SetBluetoothDeviceBlocked sets whether the device is blocked, connections of blocked devices are rejected
+func SetBluetoothDeviceBlocked(device BluetoothDevice, blocked bool) error {
+	return bluez.SetBlocked(device.Address, blocked)
+}
+
+// SetBluetoothDeviceAlias sets the alias of the device, an empty alias resets it to the name of the device
+func SetBluetoothDeviceAlias(device BluetoothDevice, alias string) error {
+	return bluez.SetAlias(device.Address, alias)
+}
@@ -0,0 +1,224 @@
+package bluetooth
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	pathpkg "path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	// AgentCapabilityNoInputNoOutput pairs without any user interaction ("just works")
+	AgentCapabilityNoInputNoOutput = "NoInputNoOutput"
+	// AgentCapabilityDisplayYesNo displays passkeys and asks for confirmation
+	AgentCapabilityDisplayYesNo = "DisplayYesNo"
+	// AgentCapabilityKeyboardDisplay displays passkeys and allows entering PIN codes and passkeys
+	AgentCapabilityKeyboardDisplay = "KeyboardDisplay"
+
+	agentInterface        = "org.bluez.Agent1"
+	agentManagerInterface = "org.bluez.AgentManager1"
+	agentPath             = dbus.ObjectPath("/org/bluez/agent/system_control")
+
+	// defaultPinCode is used for legacy devices when pairing with auto-accept
+	defaultPinCode = "0000"
+)
+
+var (
+	errAgentRejected = dbus.NewError("org.bluez.Error.Rejected", []any{"Rejected"})
+	errAgentCanceled = dbus.NewError("org.bluez.Error.Canceled", []any{"Canceled"})
+)
+
+// Agent implements the org.bluez.Agent1 interface, which is used by BlueZ to display passkeys and
+// request confirmations or PIN codes while pairing. Prompts are written to out and answers read from in.
+type Agent struct {
+	conn *dbus.Conn
+	// AutoAccept accepts all confirmations and authorizations without asking, for headless pairing
+	AutoAccept bool
+	out        io.Writer
+	in         *bufio.Reader
+	// mu serializes prompts, since BlueZ may send multiple requests concurrently
+	mu sync.Mutex
+}
+
+// NewAgent creates an agent which uses the given bus connection to resolve device names
+func NewAgent(conn *dbus.Conn, autoAccept bool, in io.Reader, out io.Writer) *Agent {
+	return &Agent{
+		conn:       conn,
+		AutoAccept: autoAccept,
+		out:        out,
+		in:         bufio.NewReader(in),
+	}
+}
+
+// StartPairingAgent registers an agent with the given capability as the default agent of BlueZ.
+// The returned function unregisters the agent again.
+func StartPairingAgent(capability string, autoAccept bool) (func() error, error) {
+	conn, err := bluez.bus()
+	if err != nil {
+		return nil, err
+	}
+	agent := NewAgent(conn, autoAccept, os.Stdin, os.Stdout)
+	return agent.Register(capability)
+}
+
+// Register exports the agent on its bus connection and registers it as the default agent with the given capability.
+// The returned function unregisters the agent again.
+func (a *Agent) Register(capability string) (func() error, error) {
+	switch capability {
+	case AgentCapabilityNoInputNoOutput, AgentCapabilityDisplayYesNo, AgentCapabilityKeyboardDisplay:
+	default:
+		return nil, fmt.Errorf("unsupported agent capability: %s", capability)
+	}
+
+	err := a.conn.Export(a, agentPath, agentInterface)
+	if err != nil {
+		return nil, err
+	}
+	manager := a.conn.Object("org.bluez", "/org/bluez")
+	err = manager.Call(agentManagerInterface+".RegisterAgent", 0, agentPath, capability).Err
+	if err != nil {
+		_ = a.conn.Export(nil, agentPath, agentInterface)
+		return nil, fmt.Errorf("failed to register agent: %w", err)
+	}
+	err = manager.Call(agentManagerInterface+".RequestDefaultAgent", 0, agentPath).Err
+	if err != nil {
+		_ = manager.Call(agentManagerInterface+".UnregisterAgent", 0, agentPath).Err
+		_ = a.conn.Export(nil, agentPath, agentInterface)
+		return nil, fmt.Errorf("failed to register default agent: %w", err)
+	}
+
+	return func() error {
+		err := manager.Call(agentManagerInterface+".UnregisterAgent", 0, agentPath).Err
+		_ = a.conn.Export(nil, agentPath, agentInterface)
+		return err
+	}, nil
+}
+
+// Release is called when BlueZ unregisters the agent
+func (a *Agent) Release() *dbus.Error {
+	return nil
+}
+
+// RequestPinCode asks for the PIN code of a legacy device
+func (a *Agent) RequestPinCode(device dbus.ObjectPath) (string, *dbus.Error) {
+	if a.AutoAccept {
+		a.printf("Using PIN code %s for %s\n", defaultPinCode, a.deviceName(device))
+		return defaultPinCode, nil
+	}
+	pinCode, err := a.prompt(fmt.Sprintf("Enter PIN code for %s: ", a.deviceName(device)))
+	if err != nil || pinCode == "" {
+		return "", errAgentRejected
+	}
+	return pinCode, nil
+}
+
+// DisplayPinCode displays the PIN code which has to be entered on the device
+func (a *Agent) DisplayPinCode(device dbus.ObjectPath, pinCode string) *dbus.Error {
+	a.printf("PIN code for %s: %s\n", a.deviceName(device), pinCode)
+	return nil
+}
+
+// RequestPasskey asks for the passkey displayed by the device
+func (a *Agent) RequestPasskey(device dbus.ObjectPath) (uint32, *dbus.Error) {
+	if a.AutoAccept {
+		// the passkey is only known to the device, so it can not be accepted automatically
+		a.printf("Rejecting passkey request of %s, passkeys can not be entered automatically\n", a.deviceName(device))
+		return 0, errAgentRejected
+	}
+	answer, err := a.prompt(fmt.Sprintf("Enter passkey displayed by %s: ", a.deviceName(device)))
+	if err != nil {
+		return 0, errAgentRejected
+	}
+	passkey, err := strconv.ParseUint(answer, 10, 32)
+	if err != nil || passkey > 999999 {
+		return 0, errAgentRejected
+	}
+	return uint32(passkey), nil
+}
+
+// DisplayPasskey displays the passkey which has to be typed on the device, entered is the number of typed digits
+func (a *Agent) DisplayPasskey(device dbus.ObjectPath, passkey uint32, entered uint16) *dbus.Error {
+	if entered == 0 {
+		a.printf("Type passkey %06d on %s and press enter\n", passkey, a.deviceName(device))
+	} else {
+		a.printf("Passkey %06d (%d digits entered)\n", passkey, entered)
+	}
+	return nil
+}
+
+// RequestConfirmation asks to confirm that the passkey matches the one displayed by the device
+func (a *Agent) RequestConfirmation(device dbus.ObjectPath, passkey uint32) *dbus.Error {
+	return a.confirm(fmt.Sprintf("Confirm passkey %06d for %s", passkey, a.deviceName(device)))
+}
+
+// RequestAuthorization asks to authorize an incoming pairing request, which would otherwise use "just works"
+func (a *Agent) RequestAuthorization(device dbus.ObjectPath) *dbus.Error {
+	return a.confirm(fmt.Sprintf("Authorize pairing with %s", a.deviceName(device)))
+}
+
+// AuthorizeService asks to authorize a connection to the service with the given UUID
+func (a *Agent) AuthorizeService(device dbus.ObjectPath, uuid string) *dbus.Error {
+	return a.confirm(fmt.Sprintf("Authorize service %s of %s", uuid, a.deviceName(device)))
+}
+
+// Cancel is called when a request has been canceled by BlueZ, f.ex. after a timeout
+func (a *Agent) Cancel() *dbus.Error {
+	a.printf("Request canceled\n")
+	return nil
+}
+
+// confirm prints the question and waits for a yes/no answer, returning an error if it is rejected
+func (a *Agent) confirm(question string) *dbus.Error {
+	if a.AutoAccept {
+		a.printf("%s: yes (auto-accept)\n", question)
+		return nil
+	}
+	answer, err := a.prompt(question + " (yes/no): ")
+	if err != nil {
+		return errAgentCanceled
+	}
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return nil
+	default:
+		return errAgentRejected
+	}
+}
+
+func (a *Agent) prompt(question string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_, _ = fmt.Fprint(a.out, question)
+	answer, err := a.in.ReadString('\n')
+	if err != nil && answer == "" {
+		return "", err
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+func (a *Agent) printf(format string, args ...any) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_, _ = fmt.Fprintf(a.out, format, args...)
+}
+
+// deviceName returns the alias of the device with the given path, or its address if the alias is not available
+func (a *Agent) deviceName(device dbus.ObjectPath) string {
+	address := strings.ReplaceAll(strings.TrimPrefix(pathpkg.Base(string(device)), "dev_"), "_", ":")
+	alias, err := a.conn.Object("org.bluez", device).GetProperty("org.bluez.Device1.Alias")
+	if err != nil {
+		return address
+	}
+	if name, ok := alias.Value().(string); ok && name != "" && name != strings.ReplaceAll(address, ":", "-") {
+		return fmt.Sprintf("%s (%s)", name, address)
+	}
+	return address
+}
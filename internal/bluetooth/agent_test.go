@@ -0,0 +1,142 @@
+package bluetooth
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/markusressel/system-control/internal/dbustest"
+	"github.com/stretchr/testify/assert"
+)
+
+// syncBuffer is a bytes.Buffer that can be written by the D-Bus handlers while the test reads it
+type syncBuffer struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buffer.String()
+}
+
+// startTestAgent starts a fake BlueZ service with a single device and registers an agent with it,
+// returning the fake service, a connection to call the agent, the agent and the agent output
+func startTestAgent(t *testing.T, alias string, autoAccept bool, input string) (*dbustest.FakeBlueZ, *dbus.Conn, *Agent, *syncBuffer) {
+	address := dbustest.StartBus(t)
+	bluez := dbustest.StartBlueZ(t, address)
+	bluez.AddDevice(t, testHeadsetPath, map[string]any{
+		"Address": "00:1D:43:6D:03:1A",
+		"Alias":   alias,
+		"Adapter": testAdapterPath,
+	}, nil)
+
+	out := &syncBuffer{}
+	agent := NewAgent(dbustest.Connect(t, address), autoAccept, strings.NewReader(input), out)
+	stop, err := agent.Register(AgentCapabilityKeyboardDisplay)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = stop()
+	})
+	return bluez, dbustest.Connect(t, address), agent, out
+}
+
+func callAgent(caller *dbus.Conn, agent *Agent, method string, args ...any) *dbus.Call {
+	return caller.Object(agent.conn.Names()[0], agentPath).Call(agentInterface+"."+method, 0, args...)
+}
+
+func TestAgent_Register(t *testing.T) {
+	// GIVEN
+	bluez, _, _, _ := startTestAgent(t, "Keyboard", false, "")
+
+	// THEN
+	path, capability := bluez.DefaultAgent()
+	assert.Equal(t, agentPath, path)
+	assert.Equal(t, AgentCapabilityKeyboardDisplay, capability)
+}
+
+func TestAgent_RegisterInvalidCapability(t *testing.T) {
+	// GIVEN
+	agent := NewAgent(nil, false, strings.NewReader(""), &bytes.Buffer{})
+
+	// WHEN
+	_, err := agent.Register("Telepathy")
+
+	// THEN
+	assert.EqualError(t, err, "unsupported agent capability: Telepathy")
+}
+
+func TestAgent_RequestConfirmation(t *testing.T) {
+	// GIVEN
+	_, caller, agent, out := startTestAgent(t, "Keyboard", false, "yes\nno\n")
+
+	// WHEN
+	accepted := callAgent(caller, agent, "RequestConfirmation", testHeadsetPath, uint32(1234)).Err
+	rejected := callAgent(caller, agent, "RequestConfirmation", testHeadsetPath, uint32(1234)).Err
+
+	// THEN
+	assert.NoError(t, accepted)
+	assert.ErrorContains(t, rejected, "Rejected")
+	assert.Contains(t, out.String(), "Confirm passkey 001234 for Keyboard (00:1D:43:6D:03:1A) (yes/no): ")
+}
+
+func TestAgent_RequestConfirmationAutoAccept(t *testing.T) {
+	// GIVEN
+	_, caller, agent, out := startTestAgent(t, "Keyboard", true, "")
+
+	// WHEN
+	err := callAgent(caller, agent, "RequestConfirmation", testHeadsetPath, uint32(1234)).Err
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "yes (auto-accept)")
+}
+
+func TestAgent_RequestPasskey(t *testing.T) {
+	// GIVEN
+	_, caller, agent, _ := startTestAgent(t, "Keyboard", false, "012345\n1000000\n")
+
+	// WHEN
+	var passkey uint32
+	err := callAgent(caller, agent, "RequestPasskey", testHeadsetPath).Store(&passkey)
+	tooLong := callAgent(caller, agent, "RequestPasskey", testHeadsetPath).Err
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(12345), passkey)
+	assert.ErrorContains(t, tooLong, "Rejected")
+}
+
+func TestAgent_RequestPinCodeAutoAccept(t *testing.T) {
+	// GIVEN
+	_, caller, agent, _ := startTestAgent(t, "Keyboard", true, "")
+
+	// WHEN
+	var pinCode string
+	err := callAgent(caller, agent, "RequestPinCode", testHeadsetPath).Store(&pinCode)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, defaultPinCode, pinCode)
+}
+
+func TestAgent_DisplayPasskeyWithoutAlias(t *testing.T) {
+	// GIVEN
+	_, caller, agent, out := startTestAgent(t, "00-1D-43-6D-03-1A", false, "")
+
+	// WHEN
+	err := callAgent(caller, agent, "DisplayPasskey", testHeadsetPath, uint32(42), uint16(0)).Err
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, "Type passkey 000042 on 00:1D:43:6D:03:1A and press enter\n", out.String())
+}
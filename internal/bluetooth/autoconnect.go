@@ -0,0 +1,198 @@
+package bluetooth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const adapter1Interface = "org.bluez.Adapter1"
+
+// AutoConnect keeps the given favorite devices (names or addresses) connected until the context is done.
+// Favorites are connected as soon as the adapter is powered on or they are seen in range, and
+// reconnection is retried in the given interval. Messages about connection attempts are passed to the log function,
+// and each connected device is passed to onConnect, if it is not nil. Devices are connected in the background,
+// but log and onConnect are never called concurrently.
+func AutoConnect(ctx context.Context, favorites []string, interval time.Duration, log func(message string), onConnect func(device BluetoothDevice)) error {
+	return bluez.AutoConnect(ctx, favorites, interval, log, onConnect)
+}
+
+// AutoConnect keeps the given favorite devices connected using the adapter, see the package level AutoConnect
+func (t *BluezAdapter) AutoConnect(ctx context.Context, favorites []string, interval time.Duration, log func(message string), onConnect func(device BluetoothDevice)) error {
+	conn, err := t.privateBus()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	adapterPath, err := getDefaultAdapterPath(conn, t.adapterPath)
+	if err != nil {
+		return err
+	}
+	return autoConnect(ctx, conn, adapterPath, favorites, interval, log, onConnect)
+}
+
+func autoConnect(ctx context.Context, conn *dbus.Conn, adapterPath dbus.ObjectPath, favorites []string, interval time.Duration, log func(message string), onConnect func(device BluetoothDevice)) error {
+	for _, iface := range []string{adapter1Interface, device1Interface} {
+		err := conn.AddMatchSignal(
+			dbus.WithMatchSender("org.bluez"),
+			dbus.WithMatchInterface(propertiesInterface),
+			dbus.WithMatchMember("PropertiesChanged"),
+			dbus.WithMatchArg(0, iface),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to PropertiesChanged: %w", err)
+		}
+	}
+	err := conn.AddMatchSignal(
+		dbus.WithMatchSender("org.bluez"),
+		dbus.WithMatchInterface(objectManagerInterface),
+		dbus.WithMatchMember("InterfacesAdded"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to InterfacesAdded: %w", err)
+	}
+	signals := make(chan *dbus.Signal, 64)
+	conn.Signal(signals)
+	defer conn.RemoveSignal(signals)
+
+	// connecting holds the paths of devices with a pending Connect call, so a device is not connected twice
+	var mu sync.Mutex
+	connecting := map[dbus.ObjectPath]bool{}
+	var reportMu sync.Mutex
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	report := func(message string, device *BluetoothDevice) {
+		reportMu.Lock()
+		defer reportMu.Unlock()
+		log(message)
+		if device != nil && onConnect != nil {
+			onConnect(*device)
+		}
+	}
+	// connect connects the device in the background, since Connect blocks until the device responds or times out
+	connect := func(path dbus.ObjectPath, props map[string]dbus.Variant, logFailure bool) {
+		device := deviceFromProperties(props)
+		if !isFavorite(favorites, device) || !device.Paired || device.Connected || device.Blocked || deviceAdapterPath(props) != adapterPath {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if connecting[path] {
+			return
+		}
+		connecting[path] = true
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				mu.Lock()
+				delete(connecting, path)
+				mu.Unlock()
+			}()
+			err := conn.Object("org.bluez", path).CallWithContext(ctx, device1Interface+".Connect", 0).Err
+			if err != nil {
+				if logFailure && ctx.Err() == nil {
+					report(fmt.Sprintf("Failed to connect %s (%s): %v", device.Name, device.Address, err), nil)
+				}
+				return
+			}
+			report(fmt.Sprintf("Connected %s (%s)", device.Name, device.Address), &device)
+		}()
+	}
+	// connectAll tries to connect all favorites, failures are only logged if the devices are expected to be reachable
+	connectAll := func(logFailures bool) error {
+		var managedObjects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+		err := conn.Object("org.bluez", "/").Call(objectManagerInterface+".GetManagedObjects", 0).Store(&managedObjects)
+		if err != nil {
+			return fmt.Errorf("GetManagedObjects: %w", err)
+		}
+		if props, ok := managedObjects[adapterPath][adapter1Interface]; ok && !adapterFromProperties(adapterPath, props).Powered {
+			return nil
+		}
+		for path, ifaces := range managedObjects {
+			if props, ok := ifaces[device1Interface]; ok {
+				connect(path, props, logFailures)
+			}
+		}
+		return nil
+	}
+
+	err = connectAll(false)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			err := connectAll(false)
+			if err != nil {
+				return err
+			}
+		case signal, ok := <-signals:
+			if !ok {
+				return nil
+			}
+			switch signal.Name {
+			case objectManagerInterface + ".InterfacesAdded":
+				var path dbus.ObjectPath
+				var ifaces map[string]map[string]dbus.Variant
+				if dbus.Store(signal.Body, &path, &ifaces) != nil {
+					continue
+				}
+				if props, ok := ifaces[device1Interface]; ok {
+					connect(path, props, true)
+				}
+			case propertiesInterface + ".PropertiesChanged":
+				var iface string
+				var changed map[string]dbus.Variant
+				var invalidated []string
+				if dbus.Store(signal.Body, &iface, &changed, &invalidated) != nil {
+					continue
+				}
+				switch {
+				case iface == adapter1Interface && signal.Path == adapterPath:
+					if powered, ok := changed["Powered"]; ok && powered.Value() == true {
+						report("Adapter powered on", nil)
+						err := connectAll(true)
+						if err != nil {
+							return err
+						}
+					}
+				case iface == device1Interface:
+					// an RSSI update means that the device is in range
+					if _, ok := changed["RSSI"]; !ok {
+						continue
+					}
+					var props map[string]dbus.Variant
+					err := conn.Object("org.bluez", signal.Path).Call(propertiesInterface+".GetAll", 0, device1Interface).Store(&props)
+					if err != nil {
+						continue
+					}
+					connect(signal.Path, props, true)
+				}
+			}
+		}
+	}
+}
+
+// isFavorite returns true if the given device matches one of the given names or addresses
+func isFavorite(favorites []string, device BluetoothDevice) bool {
+	for _, favorite := range favorites {
+		if strings.EqualFold(device.Address, favorite) || device.Name == favorite || device.Alias == favorite {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package bluetooth
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/markusressel/system-control/internal/dbustest"
+	"github.com/stretchr/testify/assert"
+)
+
+// expectConnected waits until the device with the given path has been connected the given number of times
+func expectConnected(t *testing.T, bluez *dbustest.FakeBlueZ, path dbus.ObjectPath, times int) {
+	t.Helper()
+	assert.Eventually(t, func() bool {
+		return bluez.Property(path, device1Interface, "Connected") == true &&
+			slices.Equal(bluez.Calls(), slices.Repeat([]string{string(path) + ".Connect"}, times))
+	}, 5*time.Second, 10*time.Millisecond, "%s was not connected", path)
+}
+
+func TestBluezAdapter_AutoConnect(t *testing.T) {
+	// GIVEN
+	address := dbustest.StartBus(t)
+	bluez := dbustest.StartBlueZ(t, address)
+	bluez.AddAdapter(t, testAdapterPath, map[string]any{"Address": "AA:BB:CC:DD:EE:00", "Powered": true})
+	bluez.AddDevice(t, testHeadsetPath, map[string]any{
+		"Address": "00:1D:43:6D:03:1A",
+		"Name":    "LG-TONE-FP9",
+		"Paired":  true,
+		"RSSI":    int16(-80),
+		"Adapter": testAdapterPath,
+	}, nil)
+	bluez.AddDevice(t, testKeyboardPath, map[string]any{
+		"Address": "F1:2B:3C:4D:5E:6F",
+		"Name":    "MX Keys",
+		"Paired":  true,
+		"Adapter": testAdapterPath,
+	}, nil)
+	adapter := NewBlueZAdapterWithConn(dbustest.Connect(t, address), dbustest.Dialer(address))
+	adapter.adapterPath = testAdapterPath
+
+	var mu sync.Mutex
+	var messages []string
+	var connectedDevices []string
+	// expectMessages waits until the given number of messages has been logged
+	expectMessages := func(count int) {
+		t.Helper()
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(messages) == count
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- adapter.AutoConnect(ctx, []string{"lg-tone-fp9", "00:1d:43:6d:03:1a"}, time.Hour, func(message string) {
+			mu.Lock()
+			defer mu.Unlock()
+			messages = append(messages, message)
+		}, func(device BluetoothDevice) {
+			mu.Lock()
+			defer mu.Unlock()
+			connectedDevices = append(connectedDevices, device.Address)
+		})
+	}()
+
+	// THEN favorites are connected right away
+	expectConnected(t, bluez, testHeadsetPath, 1)
+	expectMessages(1)
+
+	// WHEN the favorite is disconnected and comes into range again
+	bluez.Set(t, testHeadsetPath, device1Interface, "Connected", false)
+	bluez.Set(t, testHeadsetPath, device1Interface, "RSSI", int16(-60))
+
+	// THEN
+	expectConnected(t, bluez, testHeadsetPath, 2)
+	expectMessages(2)
+
+	// WHEN the favorite is disconnected and the adapter is powered on
+	bluez.Set(t, testHeadsetPath, device1Interface, "Connected", false)
+	bluez.Set(t, testAdapterPath, adapter1Interface, "Powered", true)
+
+	// THEN
+	expectConnected(t, bluez, testHeadsetPath, 3)
+
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Equal(t, []string{
+		"Connected LG-TONE-FP9 (00:1D:43:6D:03:1A)",
+		"Connected LG-TONE-FP9 (00:1D:43:6D:03:1A)",
+		"Adapter powered on",
+		"Connected LG-TONE-FP9 (00:1D:43:6D:03:1A)",
+	}, messages)
+	assert.Equal(t, []string{"00:1D:43:6D:03:1A", "00:1D:43:6D:03:1A", "00:1D:43:6D:03:1A"}, connectedDevices)
+}
+
+func TestIsFavorite(t *testing.T) {
+	device := BluetoothDevice{Name: "LG-TONE-FP9", Alias: "Headphones", Address: "00:1D:43:6D:03:1A"}
+
+	assert.True(t, isFavorite([]string{"00:1d:43:6d:03:1a"}, device))
+	assert.True(t, isFavorite([]string{"Keyboard", "LG-TONE-FP9"}, device))
+	assert.True(t, isFavorite([]string{"Headphones"}, device))
+	assert.False(t, isFavorite([]string{"lg-tone-fp9"}, device))
+	assert.False(t, isFavorite(nil, device))
+}
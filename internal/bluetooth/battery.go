@@ -0,0 +1,253 @@
+package bluetooth
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/markusressel/system-control/internal/upower"
+	"github.com/markusressel/system-control/internal/util"
+)
+
+const (
+	// BatterySourceBlueZ are batteries reported by BlueZ via the Battery1 interface
+	BatterySourceBlueZ = "bluez"
+	// BatterySourceSysfs are device batteries of /sys/class/power_supply, including Logitech HID++ devices
+	BatterySourceSysfs = "sysfs"
+	// BatterySourceUpower are batteries only known to upower
+	BatterySourceUpower = "upower"
+)
+
+var addressPattern = regexp.MustCompile(`(?i)([0-9a-f]{2}[:_-]){5}[0-9a-f]{2}`)
+
+// PeripheralBattery is the battery of a peripheral device, like a headset, mouse or keyboard
+type PeripheralBattery struct {
+	Name string
+	// Address is the bluetooth address of the device, empty if unknown
+	Address string
+	// Source is one of BatterySourceBlueZ, BatterySourceSysfs or BatterySourceUpower
+	Source string
+	// Percentage is the charge of the battery in percent, -1 if unknown
+	Percentage int64
+	// Status is f.ex. "Charging" or "Discharging", empty if unknown
+	Status string
+
+	// key identifies the battery across sources
+	key string
+}
+
+// GetPeripheralBatteries returns the batteries of all connected bluetooth devices, merged with
+// the device batteries found in sysfs (including HID++) and upower. Each battery is only listed once.
+// Sources which are not available, f.ex. BlueZ if bluetoothd is not running, are skipped and their errors
+// are returned along with the batteries of the other sources. If no source is available, no batteries are returned.
+func GetPeripheralBatteries() ([]PeripheralBattery, error) {
+	return collectBatteries(
+		batterySource{BatterySourceBlueZ, func() ([]PeripheralBattery, error) { return getBlueZBatteries(bluez) }},
+		batterySource{BatterySourceSysfs, getSysfsBatteries},
+		batterySource{BatterySourceUpower, getUpowerBatteries},
+	)
+}
+
+// batterySource is a named source of peripheral batteries
+type batterySource struct {
+	name string
+	get  func() ([]PeripheralBattery, error)
+}
+
+// collectBatteries merges the batteries of the given sources, ordered by precedence.
+// Errors of individual sources are joined, the batteries are nil if all sources failed.
+func collectBatteries(sources ...batterySource) ([]PeripheralBattery, error) {
+	var lists [][]PeripheralBattery
+	var errs []error
+	for _, source := range sources {
+		batteries, err := source.get()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.name, err))
+			continue
+		}
+		lists = append(lists, batteries)
+	}
+	if len(lists) == 0 && len(sources) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return mergeBatteries(lists...), errors.Join(errs...)
+}
+
+// getBlueZBatteries returns the batteries of all devices connected via the given adapter
+func getBlueZBatteries(adapter *BluezAdapter) ([]PeripheralBattery, error) {
+	devices, err := adapter.ListConnected()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]PeripheralBattery, 0)
+	for _, device := range devices {
+		if device.BatteryPercentage != nil {
+			result = append(result, PeripheralBattery{
+				Name:       device.Name,
+				Address:    device.Address,
+				Source:     BatterySourceBlueZ,
+				Percentage: *device.BatteryPercentage,
+			})
+		}
+	}
+	return result, nil
+}
+
+// getSysfsBatteries returns the device batteries found in sysfs
+func getSysfsBatteries() ([]PeripheralBattery, error) {
+	batteryList, err := util.GetBatteryList()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]PeripheralBattery, 0)
+	for _, battery := range batteryList {
+		// system batteries have the scope "System" or none at all
+		if battery.Scope != "Device" {
+			continue
+		}
+		result = append(result, batteryFromSysfs(&battery))
+	}
+	return result, nil
+}
+
+// getUpowerBatteries returns the device batteries known to upower
+func getUpowerBatteries() ([]PeripheralBattery, error) {
+	upowerDevices, err := upower.GetUpowerDevices()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]PeripheralBattery, 0)
+	for _, device := range upowerDevices {
+		if device.PowerSupply || device.NativePath == "" {
+			continue
+		}
+		result = append(result, batteryFromUpower(device))
+	}
+	return result, nil
+}
+
+func batteryFromSysfs(battery *util.BatteryInfo) PeripheralBattery {
+	result := PeripheralBattery{
+		Name:       strings.TrimSpace(battery.Manufacturer + " " + battery.Model),
+		Address:    extractAddress(battery.Name, battery.SerialNumber),
+		Source:     BatterySourceSysfs,
+		Percentage: -1,
+		key:        battery.Name,
+	}
+	if result.Name == "" {
+		result.Name = battery.Name
+	}
+	if capacity, err := battery.GetCapacity(); err == nil {
+		result.Percentage = capacity
+	}
+	result.Status, _ = battery.GetStatus()
+	return result
+}
+
+func batteryFromUpower(device upower.UpowerDevice) PeripheralBattery {
+	result := PeripheralBattery{
+		Name:       device.Model,
+		Address:    extractAddress(device.NativePath, device.Serial),
+		Source:     BatterySourceUpower,
+		Percentage: -1,
+		Status:     device.State,
+		key:        device.NativePath,
+	}
+	if result.Name == "" {
+		result.Name = device.NativePath
+	}
+	// upower marks percentages which are only estimated from the battery level with "(should be ignored)"
+	if !strings.Contains(device.Percentage, "ignored") {
+		value, _, _ := strings.Cut(device.Percentage, "%")
+		percentage, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err == nil {
+			result.Percentage = int64(percentage)
+		}
+	}
+	return result
+}
+
+// mergeBatteries merges the given lists of batteries, ordered by priority.
+// Batteries of the same device are identified by their bluetooth address or native name.
+func mergeBatteries(lists ...[]PeripheralBattery) []PeripheralBattery {
+	result := make([]PeripheralBattery, 0)
+	seen := map[string]bool{}
+	for _, list := range lists {
+		for _, battery := range list {
+			keys := []string{strings.ToLower(battery.key)}
+			if battery.Address != "" {
+				keys = append(keys, strings.ToUpper(battery.Address))
+			}
+			duplicate := false
+			for _, key := range keys {
+				if key != "" && seen[key] {
+					duplicate = true
+				}
+			}
+			if duplicate {
+				continue
+			}
+			for _, key := range keys {
+				seen[key] = true
+			}
+			result = append(result, battery)
+		}
+	}
+	return result
+}
+
+// extractAddress returns the first bluetooth address found in the given values, f.ex. in
+// "/org/bluez/hci0/dev_00_1D_43_6D_03_1A" or "hid-00:1d:43:6d:03:1a-battery", or an empty string
+func extractAddress(values ...string) string {
+	for _, value := range values {
+		if match := addressPattern.FindString(value); match != "" {
+			return strings.ToUpper(strings.NewReplacer("_", ":", "-", ":").Replace(match))
+		}
+	}
+	return ""
+}
+
+// BatteryMonitor detects batteries which drop below a threshold from successive samples
+type BatteryMonitor struct {
+	// Threshold is the battery level (in percent) below which a battery is reported as low
+	Threshold int64
+
+	low map[string]bool
+}
+
+// Update processes a new sample of batteries and returns the ones which dropped below the threshold
+// since the last update. A battery is reported again after it has been charged above the threshold.
+func (m *BatteryMonitor) Update(batteries []PeripheralBattery) []PeripheralBattery {
+	if m.low == nil {
+		m.low = map[string]bool{}
+	}
+	result := make([]PeripheralBattery, 0)
+	for _, battery := range batteries {
+		if battery.Percentage < 0 {
+			continue
+		}
+		id := battery.id()
+		if battery.Percentage >= m.Threshold {
+			delete(m.low, id)
+			continue
+		}
+		if !m.low[id] {
+			m.low[id] = true
+			result = append(result, battery)
+		}
+	}
+	return result
+}
+
+// id returns a stable identifier of the battery
+func (b PeripheralBattery) id() string {
+	if b.Address != "" {
+		return strings.ToUpper(b.Address)
+	}
+	if b.key != "" {
+		return strings.ToLower(b.key)
+	}
+	return b.Name
+}
@@ -0,0 +1,152 @@
+package bluetooth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/markusressel/system-control/internal/dbustest"
+	"github.com/markusressel/system-control/internal/upower"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBlueZBatteriesWithoutBlueZ(t *testing.T) {
+	// GIVEN
+	address := dbustest.StartBus(t)
+	adapter := NewBlueZAdapterWithConn(dbustest.Connect(t, address), dbustest.Dialer(address))
+
+	// WHEN
+	_, err := getBlueZBatteries(adapter)
+
+	// THEN
+	assert.Error(t, err)
+}
+
+func TestGetBlueZBatteries(t *testing.T) {
+	// GIVEN
+	_, adapter := startTestBlueZ(t)
+
+	// WHEN
+	batteries, err := getBlueZBatteries(adapter)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, []PeripheralBattery{{
+		Name:       "LG-TONE-FP9",
+		Address:    "00:1D:43:6D:03:1A",
+		Source:     BatterySourceBlueZ,
+		Percentage: 75,
+	}}, batteries)
+}
+
+func TestCollectBatteries(t *testing.T) {
+	// GIVEN
+	headset := PeripheralBattery{Name: "Headset", Address: "00:1D:43:6D:03:1A", Source: BatterySourceBlueZ, Percentage: 70}
+	working := batterySource{BatterySourceBlueZ, func() ([]PeripheralBattery, error) {
+		return []PeripheralBattery{headset}, nil
+	}}
+	failing := batterySource{BatterySourceUpower, func() ([]PeripheralBattery, error) {
+		return nil, errors.New("upower not found")
+	}}
+
+	// WHEN
+	batteries, err := collectBatteries(working, failing)
+	failedBatteries, failedErr := collectBatteries(failing, failing)
+
+	// THEN
+	assert.Equal(t, []PeripheralBattery{headset}, batteries)
+	assert.EqualError(t, err, "upower: upower not found")
+	assert.Nil(t, failedBatteries)
+	assert.EqualError(t, failedErr, "upower: upower not found\nupower: upower not found")
+}
+
+func TestBatteryFromUpower(t *testing.T) {
+	// GIVEN
+	device := upower.UpowerDevice{
+		NativePath: "/org/bluez/hci0/dev_00_1D_43_6D_03_1A",
+		Model:      "WH-1000XM4",
+		State:      "discharging",
+		Percentage: "70%",
+	}
+
+	// WHEN
+	battery := batteryFromUpower(device)
+
+	// THEN
+	assert.Equal(t, "WH-1000XM4", battery.Name)
+	assert.Equal(t, "00:1D:43:6D:03:1A", battery.Address)
+	assert.Equal(t, BatterySourceUpower, battery.Source)
+	assert.Equal(t, int64(70), battery.Percentage)
+	assert.Equal(t, "discharging", battery.Status)
+}
+
+func TestBatteryFromUpowerIgnoredPercentage(t *testing.T) {
+	// GIVEN
+	device := upower.UpowerDevice{
+		NativePath: "hidpp_battery_0",
+		Percentage: "55% (should be ignored)",
+	}
+
+	// WHEN
+	battery := batteryFromUpower(device)
+
+	// THEN
+	assert.Equal(t, "hidpp_battery_0", battery.Name)
+	assert.Equal(t, "", battery.Address)
+	assert.Equal(t, int64(-1), battery.Percentage)
+}
+
+func TestExtractAddress(t *testing.T) {
+	assert.Equal(t, "00:1D:43:6D:03:1A", extractAddress("/org/bluez/hci0/dev_00_1D_43_6D_03_1A"))
+	assert.Equal(t, "00:1D:43:6D:03:1A", extractAddress("hidpp_battery_0", "hid-00:1d:43:6d:03:1a-battery"))
+	assert.Equal(t, "", extractAddress("hidpp_battery_0", "4058-c0-e3-79-2a"))
+}
+
+func TestMergeBatteries(t *testing.T) {
+	// GIVEN
+	bluez := []PeripheralBattery{
+		{Name: "Headset", Address: "00:1D:43:6D:03:1A", Source: BatterySourceBlueZ, Percentage: 70},
+	}
+	sysfs := []PeripheralBattery{
+		{Name: "Logitech MX Master 3", Source: BatterySourceSysfs, Percentage: 40, key: "hidpp_battery_0"},
+	}
+	upowerBatteries := []PeripheralBattery{
+		{Name: "WH-1000XM4", Address: "00:1d:43:6d:03:1a", Source: BatterySourceUpower, Percentage: 70, key: "/org/bluez/hci0/dev_00_1D_43_6D_03_1A"},
+		{Name: "MX Master 3", Source: BatterySourceUpower, Percentage: -1, key: "hidpp_battery_0"},
+		{Name: "Keyboard", Source: BatterySourceUpower, Percentage: 90, key: "hidpp_battery_1"},
+	}
+
+	// WHEN
+	result := mergeBatteries(bluez, sysfs, upowerBatteries)
+
+	// THEN
+	var names []string
+	for _, battery := range result {
+		names = append(names, battery.Name)
+	}
+	assert.Equal(t, []string{"Headset", "Logitech MX Master 3", "Keyboard"}, names)
+}
+
+func TestBatteryMonitor(t *testing.T) {
+	// GIVEN
+	monitor := BatteryMonitor{Threshold: 20}
+	headset := PeripheralBattery{Name: "Headset", Address: "00:1D:43:6D:03:1A", Percentage: 30}
+	mouse := PeripheralBattery{Name: "Mouse", Percentage: -1, key: "hidpp_battery_0"}
+
+	// WHEN / THEN
+	assert.Empty(t, monitor.Update([]PeripheralBattery{headset, mouse}))
+
+	headset.Percentage = 19
+	low := monitor.Update([]PeripheralBattery{headset, mouse})
+	assert.Len(t, low, 1)
+	assert.Equal(t, "Headset", low[0].Name)
+
+	// only reported once while the battery stays low
+	headset.Percentage = 10
+	assert.Empty(t, monitor.Update([]PeripheralBattery{headset, mouse}))
+
+	// reported again after charging
+	headset.Percentage = 80
+	assert.Empty(t, monitor.Update([]PeripheralBattery{headset, mouse}))
+	headset.Percentage = 15
+	assert.Len(t, monitor.Update([]PeripheralBattery{headset, mouse}), 1)
+}
@@ -2,23 +2,28 @@ package bluetooth
 
 import (
 	"fmt"
+	pathpkg "path"
+	"slices"
 	"strings"
 
 	"github.com/godbus/dbus/v5"
 	"github.com/google/uuid"
 )
 
-// getDeviceInfoFromBlueZ queries BlueZ over DBus for a device's properties and
-// converts them into a BluetoothDevice struct. If DBus is not available or the
-// device object does not exist, an error is returned.
-func getDeviceInfoFromBlueZ(address string) (BluetoothDevice, error) {
-	bus, err := dbus.SystemBus()
+// getDeviceInfoFromBlueZ queries BlueZ over the given DBus connection for a device's properties and
+// converts them into a BluetoothDevice struct. If the device object does not exist, an error is returned.
+// The device is looked up as known to the adapter with the given path, see findDevicePath.
+func getDeviceInfoFromBlueZ(bus *dbus.Conn, adapterPath dbus.ObjectPath, address string) (BluetoothDevice, error) {
+	objPath, err := findDevicePath(bus, adapterPath, address)
 	if err != nil {
-		return BluetoothDevice{}, fmt.Errorf("dbus: %w", err)
+		return BluetoothDevice{}, err
 	}
+	return getDeviceInfoByPath(bus, objPath, address), nil
+}
 
-	// BlueZ object path format: /org/bluez/hci0/dev_XX_XX_XX_XX_XX_XX
-	objPath := dbus.ObjectPath("/org/bluez/hci0/dev_" + strings.ReplaceAll(address, ":", "_"))
+// getDeviceInfoByPath reads the properties of the device object with the given path,
+// which is necessary to distinguish the same device known to multiple adapters.
+func getDeviceInfoByPath(bus *dbus.Conn, objPath dbus.ObjectPath, address string) BluetoothDevice {
 	obj := bus.Object("org.bluez", objPath)
 
 	var dev BluetoothDevice
@@ -90,6 +95,12 @@ func getDeviceInfoFromBlueZ(address string) (BluetoothDevice, error) {
 		}
 	}
 
+	if v, ok := get("org.bluez.Device1.RSSI"); ok {
+		if rssi, ok := v.Value().(int16); ok {
+			dev.RSSI = &rssi
+		}
+	}
+
 	if v, ok := get("org.bluez.Device1.UUIDs"); ok {
 		// Expecting []string
 		slice, ok := v.Value().([]string)
@@ -134,17 +145,14 @@ func getDeviceInfoFromBlueZ(address string) (BluetoothDevice, error) {
 	// Ensure Address field is set
 	dev.Address = address
 
-	return dev, nil
+	return dev
 }
 
 // listAllDevicesFromBlueZ returns a list of all Device1 objects known to BlueZ,
-// enriched via getDeviceInfoFromBlueZ. It uses the ObjectManager to discover
+// enriched via getDeviceInfoByPath. It uses the ObjectManager to discover
 // device object paths and then collects their Address property.
-func listAllDevicesFromBlueZ() ([]BluetoothDevice, error) {
-	bus, err := dbus.SystemBus()
-	if err != nil {
-		return nil, fmt.Errorf("dbus: %w", err)
-	}
+// If an adapter path is given, only devices of that adapter are returned.
+func listAllDevicesFromBlueZ(bus *dbus.Conn, adapterPath dbus.ObjectPath) ([]BluetoothDevice, error) {
 	obj := bus.Object("org.bluez", "/")
 
 	var managedObjects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
@@ -153,17 +161,16 @@ func listAllDevicesFromBlueZ() ([]BluetoothDevice, error) {
 	}
 
 	devices := make([]BluetoothDevice, 0)
-	for _, ifaces := range managedObjects {
+	for path, ifaces := range managedObjects {
 		if props, ok := ifaces["org.bluez.Device1"]; ok {
+			if adapterPath != "" && deviceAdapterPath(props) != adapterPath {
+				continue
+			}
 			// Extract Address property
 			if v, ok := props["Address"]; ok {
 				if addr, ok := v.Value().(string); ok {
 					// get full device info
-					dev, err := getDeviceInfoFromBlueZ(addr)
-					if err != nil {
-						// If enrichment fails, build minimal device
-						dev = BluetoothDevice{Address: addr}
-					}
+					dev := getDeviceInfoByPath(bus, path, addr)
 					// ensure Name when missing, try to build from object path or Alias
 					if dev.Name == "" {
 						// try Alias
@@ -182,34 +189,98 @@ func listAllDevicesFromBlueZ() ([]BluetoothDevice, error) {
 	return devices, nil
 }
 
-// getDefaultAdapterPath finds the first Adapter1 object path via ObjectManager.
-func getDefaultAdapterPath() (dbus.ObjectPath, error) {
-	bus, err := dbus.SystemBus()
-	if err != nil {
-		return "", fmt.Errorf("dbus: %w", err)
-	}
+// getAdapters returns all Adapter1 objects known to BlueZ, sorted by their object path
+func getAdapters(bus *dbus.Conn) ([]Adapter, error) {
 	obj := bus.Object("org.bluez", "/")
 	var managedObjects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
 	if err := obj.Call("org.freedesktop.DBus.ObjectManager.GetManagedObjects", 0).Store(&managedObjects); err != nil {
-		return "", fmt.Errorf("GetManagedObjects: %w", err)
+		return nil, fmt.Errorf("GetManagedObjects: %w", err)
 	}
+
+	adapters := make([]Adapter, 0)
 	for path, ifaces := range managedObjects {
-		if _, ok := ifaces["org.bluez.Adapter1"]; ok {
-			return path, nil
+		if props, ok := ifaces["org.bluez.Adapter1"]; ok {
+			adapters = append(adapters, adapterFromProperties(path, props))
+		}
+	}
+	slices.SortFunc(adapters, func(a, b Adapter) int {
+		return strings.Compare(string(a.Path), string(b.Path))
+	})
+	return adapters, nil
+}
+
+func adapterFromProperties(path dbus.ObjectPath, props map[string]dbus.Variant) Adapter {
+	adapter := Adapter{
+		Name: pathpkg.Base(string(path)),
+		Path: path,
+	}
+	if v, ok := props["Address"]; ok {
+		adapter.Address, _ = v.Value().(string)
+	}
+	if v, ok := props["Alias"]; ok {
+		adapter.Alias, _ = v.Value().(string)
+	}
+	if v, ok := props["Powered"]; ok {
+		adapter.Powered, _ = v.Value().(bool)
+	}
+	if v, ok := props["Discoverable"]; ok {
+		adapter.Discoverable, _ = v.Value().(bool)
+	}
+	if v, ok := props["Pairable"]; ok {
+		adapter.Pairable, _ = v.Value().(bool)
+	}
+	if v, ok := props["Discovering"]; ok {
+		adapter.Discovering, _ = v.Value().(bool)
+	}
+	return adapter
+}
+
+// findAdapter returns the adapter with the given name (f.ex. "hci1"), address or alias
+func findAdapter(adapters []Adapter, nameOrAddress string) (Adapter, error) {
+	for _, adapter := range adapters {
+		if adapter.Name == nameOrAddress || strings.EqualFold(adapter.Address, nameOrAddress) {
+			return adapter, nil
+		}
+	}
+	for _, adapter := range adapters {
+		if adapter.Alias == nameOrAddress {
+			return adapter, nil
 		}
 	}
-	return "", fmt.Errorf("no bluez adapter found")
+	return Adapter{}, fmt.Errorf("bluetooth adapter not found: %s", nameOrAddress)
 }
 
-// setAdapterPowered sets the Powered property on the default adapter.
-func setAdapterPowered(powered bool) error {
-	path, err := getDefaultAdapterPath()
+// getDefaultAdapterPath returns the given path of the selected adapter, or the path of the first adapter
+// if none is selected.
+func getDefaultAdapterPath(bus *dbus.Conn, selectedAdapterPath dbus.ObjectPath) (dbus.ObjectPath, error) {
+	if selectedAdapterPath != "" {
+		return selectedAdapterPath, nil
+	}
+	adapters, err := getAdapters(bus)
 	if err != nil {
-		return err
+		return "", err
+	}
+	if len(adapters) == 0 {
+		return "", fmt.Errorf("no bluez adapter found")
+	}
+	return adapters[0].Path, nil
+}
+
+// deviceAdapterPath returns the path of the adapter the device with the given Device1 properties belongs to
+func deviceAdapterPath(props map[string]dbus.Variant) dbus.ObjectPath {
+	if v, ok := props["Adapter"]; ok {
+		if path, ok := v.Value().(dbus.ObjectPath); ok {
+			return path
+		}
 	}
-	bus, err := dbus.SystemBus()
+	return ""
+}
+
+// setAdapterPowered sets the Powered property on the selected adapter, or the default adapter if none is selected.
+func setAdapterPowered(bus *dbus.Conn, selectedAdapterPath dbus.ObjectPath, powered bool) error {
+	path, err := getDefaultAdapterPath(bus, selectedAdapterPath)
 	if err != nil {
-		return fmt.Errorf("dbus: %w", err)
+		return err
 	}
 	obj := bus.Object("org.bluez", path)
 	// Use org.freedesktop.DBus.Properties.Set
@@ -220,15 +291,9 @@ func setAdapterPowered(powered bool) error {
 }
 
 // removeDeviceByPath removes a device by its object path using Adapter1.RemoveDevice
-func removeDeviceByPath(devicePath dbus.ObjectPath) error {
-	adapterPath, err := getDefaultAdapterPath()
-	if err != nil {
-		return err
-	}
-	bus, err := dbus.SystemBus()
-	if err != nil {
-		return fmt.Errorf("dbus: %w", err)
-	}
+func removeDeviceByPath(bus *dbus.Conn, devicePath dbus.ObjectPath) error {
+	// devices are children of the adapter they belong to
+	adapterPath := dbus.ObjectPath(pathpkg.Dir(string(devicePath)))
 	adapterObj := bus.Object("org.bluez", adapterPath)
 	if err := adapterObj.Call("org.bluez.Adapter1.RemoveDevice", 0, devicePath).Err; err != nil {
 		return fmt.Errorf("RemoveDevice failed: %w", err)
@@ -237,11 +302,9 @@ func removeDeviceByPath(devicePath dbus.ObjectPath) error {
 }
 
 // findDevicePath looks up the DBus object path for a given device address using ObjectManager.
-func findDevicePath(address string) (dbus.ObjectPath, error) {
-	bus, err := dbus.SystemBus()
-	if err != nil {
-		return "", fmt.Errorf("dbus: %w", err)
-	}
+// If an adapter path is given, only devices known to that adapter are found, otherwise the device
+// known to the default adapter is preferred.
+func findDevicePath(bus *dbus.Conn, selectedAdapterPath dbus.ObjectPath, address string) (dbus.ObjectPath, error) {
 	obj := bus.Object("org.bluez", "/")
 
 	var managedObjects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
@@ -249,20 +312,33 @@ func findDevicePath(address string) (dbus.ObjectPath, error) {
 		return "", fmt.Errorf("GetManagedObjects: %w", err)
 	}
 
+	adapterPath, err := getDefaultAdapterPath(bus, selectedAdapterPath)
+	if err != nil {
+		return "", err
+	}
+
+	// the same device may be known to multiple adapters, prefer the default (or selected) one
+	var otherAdapterPath dbus.ObjectPath
 	for path, ifaces := range managedObjects {
 		if props, ok := ifaces["org.bluez.Device1"]; ok {
 			if v, ok := props["Address"]; ok {
 				if addr, ok := v.Value().(string); ok {
 					if strings.EqualFold(addr, address) {
-						return path, nil
+						if deviceAdapterPath(props) == adapterPath {
+							return path, nil
+						}
+						otherAdapterPath = path
 					}
 				}
 			}
 		}
 	}
+	if otherAdapterPath != "" && selectedAdapterPath == "" {
+		return otherAdapterPath, nil
+	}
 
-	// As a fallback, try constructing the common path using hci0 and probe it
-	constructed := dbus.ObjectPath("/org/bluez/hci0/dev_" + strings.ReplaceAll(address, ":", "_"))
+	// As a fallback, try constructing the common path below the adapter and probe it
+	constructed := dbus.ObjectPath(string(adapterPath) + "/dev_" + strings.ReplaceAll(strings.ToUpper(address), ":", "_"))
 	constructedObj := bus.Object("org.bluez", constructed)
 	// Probe by attempting to read the Address property
 	if _, err := constructedObj.GetProperty("org.bluez.Device1.Address"); err == nil {
@@ -273,11 +349,7 @@ func findDevicePath(address string) (dbus.ObjectPath, error) {
 }
 
 // callDeviceMethod invokes a method on the Device1 interface for the given device path.
-func callDeviceMethod(devicePath dbus.ObjectPath, method string) error {
-	bus, err := dbus.SystemBus()
-	if err != nil {
-		return fmt.Errorf("dbus: %w", err)
-	}
+func callDeviceMethod(bus *dbus.Conn, devicePath dbus.ObjectPath, method string) error {
 	devObj := bus.Object("org.bluez", devicePath)
 	if err := devObj.Call("org.bluez.Device1."+method, 0).Err; err != nil {
 		return fmt.Errorf("Device1.%s failed: %w", method, err)
@@ -285,9 +357,19 @@ func callDeviceMethod(devicePath dbus.ObjectPath, method string) error {
 	return nil
 }
 
-// listDevicesMatching allows filtering devices from ObjectManager using a predicate.
-func listDevicesMatching(pred func(BluetoothDevice) bool) ([]BluetoothDevice, error) {
-	all, err := listAllDevicesFromBlueZ()
+// setDeviceProperty sets a property of the Device1 interface for the given device path.
+func setDeviceProperty(bus *dbus.Conn, devicePath dbus.ObjectPath, name string, value any) error {
+	devObj := bus.Object("org.bluez", devicePath)
+	if err := devObj.Call("org.freedesktop.DBus.Properties.Set", 0, "org.bluez.Device1", name, dbus.MakeVariant(value)).Err; err != nil {
+		return fmt.Errorf("failed to set %s: %w", name, err)
+	}
+	return nil
+}
+
+// listDevicesMatching allows filtering devices of the adapter with the given path (all adapters if empty)
+// from ObjectManager using a predicate.
+func listDevicesMatching(bus *dbus.Conn, adapterPath dbus.ObjectPath, pred func(BluetoothDevice) bool) ([]BluetoothDevice, error) {
+	all, err := listAllDevicesFromBlueZ(bus, adapterPath)
 	if err != nil {
 		return nil, err
 	}
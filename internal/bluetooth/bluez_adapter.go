@@ -2,14 +2,23 @@ package bluetooth
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/godbus/dbus/v5"
 	"github.com/google/uuid"
 	bt "tinygo.org/x/bluetooth"
 )
 
 type BluezAdapter struct {
+	// conn is the bus connection used to talk to BlueZ, nil to use the system bus
+	conn *dbus.Conn
+	// connectPrivate opens a new connection to the same bus, used to subscribe to signals
+	connectPrivate func() (*dbus.Conn, error)
+	// adapterPath is the object path of the selected adapter, empty for the default adapter
+	adapterPath dbus.ObjectPath
+	// adapter is used to scan for advertisements, nil if scanning is not supported
 	adapter    *bt.Adapter
 	mu         sync.Mutex
 	discovered map[string]BluetoothDevice
@@ -21,27 +30,89 @@ type BluezAdapter struct {
 // NewBlueZAdapter returns an Adapter backed by tinygo.org/x/bluetooth (BlueZ on Linux).
 func NewBlueZAdapter() *BluezAdapter {
 	// Use the package-level variable directly
-	ad := bt.DefaultAdapter
-	_ = ad.Enable()
+	return newBlueZAdapter(nil, connectSystemBus, bt.DefaultAdapter)
+}
+
+// connectSystemBus opens a new private connection to the system bus
+func connectSystemBus() (*dbus.Conn, error) {
+	return dbus.ConnectSystemBus()
+}
+
+// NewBlueZAdapterWithConn returns an Adapter which talks to BlueZ using the given bus connection,
+// f.ex. a private bus providing a fake BlueZ service. connectPrivate has to open a new connection to the same bus,
+// which is used to subscribe to signals during discovery. Scanning for advertisements is not supported.
+func NewBlueZAdapterWithConn(conn *dbus.Conn, connectPrivate func() (*dbus.Conn, error)) *BluezAdapter {
+	return newBlueZAdapter(conn, connectPrivate, nil)
+}
+
+func newBlueZAdapter(conn *dbus.Conn, connectPrivate func() (*dbus.Conn, error), ad *bt.Adapter) *BluezAdapter {
+	if ad != nil {
+		_ = ad.Enable()
+	}
 
 	return &BluezAdapter{
-		adapter:    ad, // bt.DefaultAdapter is already *bt.Adapter
-		discovered: make(map[string]BluetoothDevice),
+		conn:           conn,
+		connectPrivate: connectPrivate,
+		adapter:        ad,
+		discovered:     make(map[string]BluetoothDevice),
+	}
+}
+
+// bus returns the bus connection used to talk to BlueZ
+func (t *BluezAdapter) bus() (*dbus.Conn, error) {
+	if t.conn != nil {
+		return t.conn, nil
+	}
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("dbus: %w", err)
+	}
+	return conn, nil
+}
+
+// privateBus opens a new connection to the bus used to talk to BlueZ, so signal subscriptions
+// do not leak into the shared one. The connection has to be closed by the caller.
+func (t *BluezAdapter) privateBus() (*dbus.Conn, error) {
+	conn, err := t.connectPrivate()
+	if err != nil {
+		return nil, fmt.Errorf("dbus: %w", err)
+	}
+	return conn, nil
+}
+
+// Adapters returns all adapters known to BlueZ
+func (t *BluezAdapter) Adapters() ([]Adapter, error) {
+	bus, err := t.bus()
+	if err != nil {
+		return nil, err
 	}
+	return getAdapters(bus)
 }
 
 func (t *BluezAdapter) PowerOn() error {
-	return setAdapterPowered(true)
+	bus, err := t.bus()
+	if err != nil {
+		return err
+	}
+	return setAdapterPowered(bus, t.adapterPath, true)
 }
 
 func (t *BluezAdapter) PowerOff() error {
-	return setAdapterPowered(false)
+	bus, err := t.bus()
+	if err != nil {
+		return err
+	}
+	return setAdapterPowered(bus, t.adapterPath, false)
 }
 
 func (t *BluezAdapter) Scan(enable bool) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	if t.adapter == nil {
+		return ErrNotSupported
+	}
+
 	if enable {
 		if t.scanning {
 			return nil
@@ -96,8 +167,13 @@ func (t *BluezAdapter) Scan(enable bool) error {
 }
 
 func (t *BluezAdapter) ListDevices() ([]BluetoothDevice, error) {
+	bus, err := t.bus()
+	if err != nil {
+		return nil, err
+	}
+
 	// Prefer BlueZ-managed devices via DBus; fall back to scan-based discovery
-	if devs, err := listAllDevicesFromBlueZ(); err == nil && len(devs) > 0 {
+	if devs, err := listAllDevicesFromBlueZ(bus, t.adapterPath); err == nil && len(devs) > 0 {
 		return devs, nil
 	}
 
@@ -118,7 +194,7 @@ func (t *BluezAdapter) ListDevices() ([]BluetoothDevice, error) {
 	results := make([]BluetoothDevice, 0, len(addrs))
 	for _, addr := range addrs {
 		// Try BlueZ first
-		if dev, err := getDeviceInfoFromBlueZ(addr); err == nil {
+		if dev, err := getDeviceInfoFromBlueZ(bus, t.adapterPath, addr); err == nil {
 			results = append(results, dev)
 			continue
 		}
@@ -138,17 +214,27 @@ func (t *BluezAdapter) ListDevices() ([]BluetoothDevice, error) {
 }
 
 func (t *BluezAdapter) ListConnected() ([]BluetoothDevice, error) {
-	return listDevicesMatching(func(d BluetoothDevice) bool { return d.Connected })
+	bus, err := t.bus()
+	if err != nil {
+		return nil, err
+	}
+	return listDevicesMatching(bus, t.adapterPath, func(d BluetoothDevice) bool { return d.Connected })
 }
 
 func (t *BluezAdapter) ListPaired() ([]BluetoothDevice, error) {
-	return listDevicesMatching(func(d BluetoothDevice) bool { return d.Paired })
+	bus, err := t.bus()
+	if err != nil {
+		return nil, err
+	}
+	return listDevicesMatching(bus, t.adapterPath, func(d BluetoothDevice) bool { return d.Paired })
 }
 
 func (t *BluezAdapter) Info(address string) (BluetoothDevice, error) {
 	// Try BlueZ first for rich info
-	if dev, err := getDeviceInfoFromBlueZ(address); err == nil {
-		return dev, nil
+	if bus, err := t.bus(); err == nil {
+		if dev, err := getDeviceInfoFromBlueZ(bus, t.adapterPath, address); err == nil {
+			return dev, nil
+		}
 	}
 
 	// Fall back to discovered advertisement info
@@ -166,28 +252,40 @@ func (t *BluezAdapter) Info(address string) (BluetoothDevice, error) {
 
 func (t *BluezAdapter) Pair(address string) error {
 	// Find device path
-	path, err := findDevicePath(address)
+	bus, err := t.bus()
+	if err != nil {
+		return err
+	}
+	path, err := findDevicePath(bus, t.adapterPath, address)
 	if err != nil {
 		return err
 	}
 	// call Pair on the device
-	return callDeviceMethod(path, "Pair")
+	return callDeviceMethod(bus, path, "Pair")
 }
 
 func (t *BluezAdapter) Connect(address string) error {
-	path, err := findDevicePath(address)
+	bus, err := t.bus()
 	if err != nil {
 		return err
 	}
-	return callDeviceMethod(path, "Connect")
+	path, err := findDevicePath(bus, t.adapterPath, address)
+	if err != nil {
+		return err
+	}
+	return callDeviceMethod(bus, path, "Connect")
 }
 
 func (t *BluezAdapter) Disconnect(address string) error {
-	path, err := findDevicePath(address)
+	bus, err := t.bus()
+	if err != nil {
+		return err
+	}
+	path, err := findDevicePath(bus, t.adapterPath, address)
 	if err != nil {
 		return err
 	}
-	return callDeviceMethod(path, "Disconnect")
+	return callDeviceMethod(bus, path, "Disconnect")
 }
 
 func (t *BluezAdapter) DisconnectAll() error {
@@ -202,9 +300,50 @@ func (t *BluezAdapter) DisconnectAll() error {
 }
 
 func (t *BluezAdapter) Remove(address string) error {
-	path, err := findDevicePath(address)
+	bus, err := t.bus()
+	if err != nil {
+		return err
+	}
+	path, err := findDevicePath(bus, t.adapterPath, address)
+	if err != nil {
+		return err
+	}
+	return removeDeviceByPath(bus, path)
+}
+
+func (t *BluezAdapter) SetTrusted(address string, trusted bool) error {
+	bus, err := t.bus()
+	if err != nil {
+		return err
+	}
+	path, err := findDevicePath(bus, t.adapterPath, address)
+	if err != nil {
+		return err
+	}
+	return setDeviceProperty(bus, path, "Trusted", trusted)
+}
+
+func (t *BluezAdapter) SetBlocked(address string, blocked bool) error {
+	bus, err := t.bus()
+	if err != nil {
+		return err
+	}
+	path, err := findDevicePath(bus, t.adapterPath, address)
+	if err != nil {
+		return err
+	}
+	return setDeviceProperty(bus, path, "Blocked", blocked)
+}
+
+// SetAlias sets the alias of the device, an empty alias resets it to the name of the device
+func (t *BluezAdapter) SetAlias(address string, alias string) error {
+	bus, err := t.bus()
+	if err != nil {
+		return err
+	}
+	path, err := findDevicePath(bus, t.adapterPath, address)
 	if err != nil {
 		return err
 	}
-	return removeDeviceByPath(path)
+	return setDeviceProperty(bus, path, "Alias", alias)
 }
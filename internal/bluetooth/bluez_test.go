@@ -0,0 +1,238 @@
+package bluetooth
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/markusressel/system-control/internal/dbustest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdapterFromProperties(t *testing.T) {
+	// WHEN
+	adapter := adapterFromProperties("/org/bluez/hci1", map[string]dbus.Variant{
+		"Address":      dbus.MakeVariant("00:1A:7D:DA:71:13"),
+		"Alias":        dbus.MakeVariant("dongle"),
+		"Powered":      dbus.MakeVariant(true),
+		"Discoverable": dbus.MakeVariant(false),
+		"Pairable":     dbus.MakeVariant(true),
+	})
+
+	// THEN
+	assert.Equal(t, Adapter{
+		Name:     "hci1",
+		Path:     "/org/bluez/hci1",
+		Address:  "00:1A:7D:DA:71:13",
+		Alias:    "dongle",
+		Powered:  true,
+		Pairable: true,
+	}, adapter)
+}
+
+func TestFindAdapter(t *testing.T) {
+	// GIVEN
+	adapters := []Adapter{
+		{Name: "hci0", Path: "/org/bluez/hci0", Address: "AA:BB:CC:DD:EE:00", Alias: "laptop"},
+		{Name: "hci1", Path: "/org/bluez/hci1", Address: "00:1A:7D:DA:71:13", Alias: "dongle"},
+	}
+
+	// WHEN / THEN
+	adapter, err := findAdapter(adapters, "hci1")
+	assert.NoError(t, err)
+	assert.Equal(t, adapters[1], adapter)
+
+	adapter, err = findAdapter(adapters, "aa:bb:cc:dd:ee:00")
+	assert.NoError(t, err)
+	assert.Equal(t, adapters[0], adapter)
+
+	adapter, err = findAdapter(adapters, "dongle")
+	assert.NoError(t, err)
+	assert.Equal(t, adapters[1], adapter)
+
+	_, err = findAdapter(adapters, "hci2")
+	assert.Error(t, err)
+}
+
+const (
+	testAdapterPath      = dbus.ObjectPath("/org/bluez/hci0")
+	testOtherAdapterPath = dbus.ObjectPath("/org/bluez/hci1")
+
+	testHeadsetPath  = testAdapterPath + "/dev_00_1D_43_6D_03_1A"
+	testKeyboardPath = testAdapterPath + "/dev_F1_2B_3C_4D_5E_6F"
+	// testOtherHeadsetPath is the headset as known to the second adapter
+	testOtherHeadsetPath = testOtherAdapterPath + "/dev_00_1D_43_6D_03_1A"
+)
+
+// startTestBlueZ starts a fake BlueZ service with two adapters, a connected headset with a battery
+// which is known to both adapters and an unpaired keyboard, and returns an adapter connected to it
+func startTestBlueZ(t *testing.T) (*dbustest.FakeBlueZ, *BluezAdapter) {
+	address := dbustest.StartBus(t)
+	bluez := dbustest.StartBlueZ(t, address)
+
+	bluez.AddAdapter(t, testAdapterPath, map[string]any{"Address": "AA:BB:CC:DD:EE:00", "Powered": true})
+	bluez.AddAdapter(t, testOtherAdapterPath, map[string]any{"Address": "00:1A:7D:DA:71:13"})
+	bluez.AddDevice(t, testHeadsetPath, map[string]any{
+		"Address":   "00:1D:43:6D:03:1A",
+		"Name":      "LG-TONE-FP9",
+		"Alias":     "Headphones",
+		"Class":     uint32(0x240404),
+		"Icon":      "audio-headset",
+		"Paired":    true,
+		"Connected": true,
+		"RSSI":      int16(-52),
+		"UUIDs":     []string{"0000110b-0000-1000-8000-00805f9b34fb"},
+		"Adapter":   testAdapterPath,
+	}, map[string]any{"Percentage": byte(75)})
+	bluez.AddDevice(t, testKeyboardPath, map[string]any{
+		"Address": "F1:2B:3C:4D:5E:6F",
+		"Name":    "MX Keys",
+		"Adapter": testAdapterPath,
+	}, nil)
+	bluez.AddDevice(t, testOtherHeadsetPath, map[string]any{
+		"Address": "00:1D:43:6D:03:1A",
+		"Name":    "LG-TONE-FP9",
+		"Adapter": testOtherAdapterPath,
+	}, nil)
+
+	return bluez, NewBlueZAdapterWithConn(dbustest.Connect(t, address), dbustest.Dialer(address))
+}
+
+func TestListAllDevicesFromBlueZ(t *testing.T) {
+	// GIVEN
+	_, adapter := startTestBlueZ(t)
+
+	// WHEN
+	devices, err := listAllDevicesFromBlueZ(adapter.conn, testAdapterPath)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Len(t, devices, 2)
+	slices.SortFunc(devices, func(a, b BluetoothDevice) int {
+		return strings.Compare(a.Address, b.Address)
+	})
+
+	headset := devices[0]
+	assert.Equal(t, "00:1D:43:6D:03:1A", headset.Address)
+	assert.Equal(t, "LG-TONE-FP9", headset.Name)
+	assert.Equal(t, "Headphones", headset.Alias)
+	assert.Equal(t, "0x00240404", headset.Class)
+	assert.Equal(t, "audio-headset", headset.Icon)
+	assert.True(t, headset.Paired)
+	assert.True(t, headset.Connected)
+	assert.Equal(t, int16(-52), *headset.RSSI)
+	assert.Equal(t, int64(75), *headset.BatteryPercentage)
+	assert.Contains(t, headset.UUIDs, "0000110b-0000-1000-8000-00805f9b34fb")
+
+	keyboard := devices[1]
+	assert.Equal(t, "MX Keys", keyboard.Name)
+	assert.False(t, keyboard.Paired)
+	assert.Nil(t, keyboard.RSSI)
+	assert.Nil(t, keyboard.BatteryPercentage)
+}
+
+func TestListAllDevicesFromBlueZSelectedAdapter(t *testing.T) {
+	// GIVEN
+	_, adapter := startTestBlueZ(t)
+
+	// WHEN
+	devices, err := listAllDevicesFromBlueZ(adapter.conn, testOtherAdapterPath)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Len(t, devices, 1)
+	assert.Equal(t, "00:1D:43:6D:03:1A", devices[0].Address)
+	assert.False(t, devices[0].Connected)
+}
+
+func TestFindDevicePath(t *testing.T) {
+	// GIVEN
+	_, adapter := startTestBlueZ(t)
+
+	// WHEN / THEN
+	path, err := findDevicePath(adapter.conn, "", "f1:2b:3c:4d:5e:6f")
+	assert.NoError(t, err)
+	assert.Equal(t, testKeyboardPath, path)
+
+	// the device of the default adapter is preferred
+	path, err = findDevicePath(adapter.conn, "", "00:1D:43:6D:03:1A")
+	assert.NoError(t, err)
+	assert.Equal(t, testHeadsetPath, path)
+
+	path, err = findDevicePath(adapter.conn, testOtherAdapterPath, "00:1D:43:6D:03:1A")
+	assert.NoError(t, err)
+	assert.Equal(t, testOtherHeadsetPath, path)
+
+	// devices of other adapters are not used when an adapter is selected
+	_, err = findDevicePath(adapter.conn, testOtherAdapterPath, "F1:2B:3C:4D:5E:6F")
+	assert.EqualError(t, err, "device not found: F1:2B:3C:4D:5E:6F")
+}
+
+func TestBluezAdapter_Pair(t *testing.T) {
+	// GIVEN
+	bluez, adapter := startTestBlueZ(t)
+
+	// WHEN
+	err := adapter.Pair("F1:2B:3C:4D:5E:6F")
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, []string{string(testKeyboardPath) + ".Pair"}, bluez.Calls())
+	assert.Equal(t, true, bluez.Property(testKeyboardPath, device1Interface, "Paired"))
+}
+
+func TestBluezAdapter_ConnectDisconnect(t *testing.T) {
+	// GIVEN
+	bluez, adapter := startTestBlueZ(t)
+
+	// WHEN
+	err := adapter.Connect("F1:2B:3C:4D:5E:6F")
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, true, bluez.Property(testKeyboardPath, device1Interface, "Connected"))
+	connected, err := adapter.ListConnected()
+	assert.NoError(t, err)
+	assert.Len(t, connected, 2)
+
+	// WHEN
+	err = adapter.DisconnectAll()
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, false, bluez.Property(testKeyboardPath, device1Interface, "Connected"))
+	assert.Equal(t, false, bluez.Property(testHeadsetPath, device1Interface, "Connected"))
+}
+
+func TestBluezAdapter_Remove(t *testing.T) {
+	// GIVEN
+	bluez, adapter := startTestBlueZ(t)
+
+	// WHEN
+	err := adapter.Remove("F1:2B:3C:4D:5E:6F")
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, []string{string(testAdapterPath) + ".RemoveDevice"}, bluez.Calls())
+	assert.False(t, bluez.Exists(testKeyboardPath))
+	_, err = adapter.Info("F1:2B:3C:4D:5E:6F")
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestBluezAdapter_SetProperties(t *testing.T) {
+	// GIVEN
+	bluez, adapter := startTestBlueZ(t)
+
+	// WHEN
+	assert.NoError(t, adapter.SetTrusted("00:1D:43:6D:03:1A", true))
+	assert.NoError(t, adapter.SetBlocked("00:1D:43:6D:03:1A", true))
+	assert.NoError(t, adapter.SetAlias("00:1D:43:6D:03:1A", "Work Headset"))
+	assert.NoError(t, adapter.PowerOff())
+
+	// THEN
+	assert.Equal(t, true, bluez.Property(testHeadsetPath, device1Interface, "Trusted"))
+	assert.Equal(t, true, bluez.Property(testHeadsetPath, device1Interface, "Blocked"))
+	assert.Equal(t, "Work Headset", bluez.Property(testHeadsetPath, device1Interface, "Alias"))
+	assert.Equal(t, false, bluez.Property(testAdapterPath, adapter1Interface, "Powered"))
+}
@@ -1,9 +1,22 @@
 package bluetooth
 
 import (
+	"github.com/godbus/dbus/v5"
 	"github.com/google/uuid"
 )
 
+// Adapter represents a local bluetooth adapter (controller)
+type Adapter struct {
+	Name         string // hci0
+	Path         dbus.ObjectPath
+	Address      string // 00:1A:7D:DA:71:13
+	Alias        string // my-laptop
+	Powered      bool
+	Discoverable bool
+	Pairable     bool
+	Discovering  bool
+}
+
 // BluetoothDevice represents a bluetooth device
 type BluetoothDevice struct {
 	Name              string // LG-TONE-FP9
@@ -19,6 +32,7 @@ type BluetoothDevice struct {
 	LegacyPairing     bool   // no
 	UUIDs             map[string]uuid.UUID
 	BatteryPercentage *int64 // 0x4b (75)
+	RSSI              *int16 // -52, only available while the device is in range during discovery
 }
 
 type BluetoothDeviceList []BluetoothDevice
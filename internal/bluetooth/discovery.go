@@ -0,0 +1,183 @@
+package bluetooth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	objectManagerInterface = "org.freedesktop.DBus.ObjectManager"
+	propertiesInterface    = "org.freedesktop.DBus.Properties"
+	device1Interface       = "org.bluez.Device1"
+)
+
+// DiscoverBluetoothDevices starts discovery on the selected adapter and calls the given handler for every device
+// as soon as it is seen, either because it is newly discovered or because a known device reports a signal strength.
+// Each device is reported only once. Discovery stops when the context is done or the handler returns true.
+func DiscoverBluetoothDevices(ctx context.Context, handler func(device BluetoothDevice) bool) error {
+	return bluez.Discover(ctx, handler)
+}
+
+// Discover starts discovery on the adapter, see DiscoverBluetoothDevices
+func (t *BluezAdapter) Discover(ctx context.Context, handler func(device BluetoothDevice) bool) error {
+	conn, err := t.privateBus()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	adapterPath, err := getDefaultAdapterPath(conn, t.adapterPath)
+	if err != nil {
+		return err
+	}
+	return discoverDevices(ctx, conn, adapterPath, handler)
+}
+
+func discoverDevices(ctx context.Context, conn *dbus.Conn, adapterPath dbus.ObjectPath, handler func(device BluetoothDevice) bool) error {
+	err := conn.AddMatchSignal(
+		dbus.WithMatchSender("org.bluez"),
+		dbus.WithMatchInterface(objectManagerInterface),
+		dbus.WithMatchMember("InterfacesAdded"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to InterfacesAdded: %w", err)
+	}
+	err = conn.AddMatchSignal(
+		dbus.WithMatchSender("org.bluez"),
+		dbus.WithMatchInterface(propertiesInterface),
+		dbus.WithMatchMember("PropertiesChanged"),
+		dbus.WithMatchArg(0, device1Interface),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to PropertiesChanged: %w", err)
+	}
+	signals := make(chan *dbus.Signal, 64)
+	conn.Signal(signals)
+	defer conn.RemoveSignal(signals)
+
+	// devices already known to BlueZ are only reported once they are in range, which is indicated by an RSSI update
+	var managedObjects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	err = conn.Object("org.bluez", "/").Call(objectManagerInterface+".GetManagedObjects", 0).Store(&managedObjects)
+	if err != nil {
+		return fmt.Errorf("GetManagedObjects: %w", err)
+	}
+	known := map[dbus.ObjectPath]map[string]dbus.Variant{}
+	for path, ifaces := range managedObjects {
+		if props, ok := ifaces[device1Interface]; ok && deviceAdapterPath(props) == adapterPath {
+			known[path] = props
+		}
+	}
+
+	adapter := conn.Object("org.bluez", adapterPath)
+	err = adapter.Call("org.bluez.Adapter1.StartDiscovery", 0).Err
+	if err != nil {
+		return fmt.Errorf("failed to start discovery: %w", err)
+	}
+	defer adapter.Call("org.bluez.Adapter1.StopDiscovery", 0)
+
+	reported := map[dbus.ObjectPath]bool{}
+	report := func(path dbus.ObjectPath) bool {
+		if reported[path] {
+			return false
+		}
+		reported[path] = true
+		return handler(deviceFromProperties(known[path]))
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case signal, ok := <-signals:
+			if !ok {
+				return nil
+			}
+			switch signal.Name {
+			case objectManagerInterface + ".InterfacesAdded":
+				var path dbus.ObjectPath
+				var ifaces map[string]map[string]dbus.Variant
+				if dbus.Store(signal.Body, &path, &ifaces) != nil {
+					continue
+				}
+				props, ok := ifaces[device1Interface]
+				if !ok || deviceAdapterPath(props) != adapterPath {
+					continue
+				}
+				known[path] = props
+				if report(path) {
+					return nil
+				}
+			case propertiesInterface + ".PropertiesChanged":
+				var iface string
+				var changed map[string]dbus.Variant
+				var invalidated []string
+				if dbus.Store(signal.Body, &iface, &changed, &invalidated) != nil || iface != device1Interface {
+					continue
+				}
+				props, ok := known[signal.Path]
+				if !ok {
+					continue
+				}
+				for key, value := range changed {
+					props[key] = value
+				}
+				if _, ok := changed["RSSI"]; ok && report(signal.Path) {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// deviceFromProperties converts the Device1 properties of a device to a BluetoothDevice
+func deviceFromProperties(props map[string]dbus.Variant) BluetoothDevice {
+	device := BluetoothDevice{UUIDs: make(map[string]uuid.UUID)}
+	if v, ok := props["Address"]; ok {
+		device.Address, _ = v.Value().(string)
+	}
+	if v, ok := props["Alias"]; ok {
+		device.Alias, _ = v.Value().(string)
+	}
+	if v, ok := props["Name"]; ok {
+		device.Name, _ = v.Value().(string)
+	}
+	if device.Name == "" {
+		device.Name = device.Alias
+	}
+	if v, ok := props["Icon"]; ok {
+		device.Icon, _ = v.Value().(string)
+	}
+	if v, ok := props["Paired"]; ok {
+		device.Paired, _ = v.Value().(bool)
+	}
+	if v, ok := props["Bonded"]; ok {
+		device.Bonded, _ = v.Value().(bool)
+	}
+	if v, ok := props["Trusted"]; ok {
+		device.Trusted, _ = v.Value().(bool)
+	}
+	if v, ok := props["Blocked"]; ok {
+		device.Blocked, _ = v.Value().(bool)
+	}
+	if v, ok := props["Connected"]; ok {
+		device.Connected, _ = v.Value().(bool)
+	}
+	if v, ok := props["RSSI"]; ok {
+		if rssi, ok := v.Value().(int16); ok {
+			device.RSSI = &rssi
+		}
+	}
+	if v, ok := props["UUIDs"]; ok {
+		if values, ok := v.Value().([]string); ok {
+			for _, value := range values {
+				if u, err := uuid.Parse(value); err == nil {
+					device.UUIDs[value] = u
+				}
+			}
+		}
+	}
+	return device
+}
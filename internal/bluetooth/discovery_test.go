@@ -0,0 +1,92 @@
+package bluetooth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/markusressel/system-control/internal/dbustest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBluezAdapter_Discover(t *testing.T) {
+	// GIVEN
+	address := dbustest.StartBus(t)
+	bluez := dbustest.StartBlueZ(t, address)
+	bluez.AddAdapter(t, testAdapterPath, map[string]any{"Address": "AA:BB:CC:DD:EE:00", "Powered": true})
+	bluez.AddAdapter(t, testOtherAdapterPath, map[string]any{"Address": "00:1A:7D:DA:71:13", "Powered": true})
+	bluez.AddDevice(t, testHeadsetPath, map[string]any{
+		"Address": "00:1D:43:6D:03:1A",
+		"Name":    "LG-TONE-FP9",
+		"RSSI":    int16(-80),
+		"Adapter": testAdapterPath,
+	}, nil)
+	adapter := NewBlueZAdapterWithConn(dbustest.Connect(t, address), dbustest.Dialer(address))
+	adapter.adapterPath = testAdapterPath
+
+	var discovered []BluetoothDevice
+	done := make(chan error)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		done <- adapter.Discover(ctx, func(device BluetoothDevice) bool {
+			discovered = append(discovered, device)
+			return len(discovered) == 2
+		})
+	}()
+	assert.Eventually(t, func() bool {
+		return bluez.Property(testAdapterPath, adapter1Interface, "Discovering") == true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// WHEN
+	bluez.AddDevice(t, testOtherAdapterPath+"/dev_AA_BB_CC_DD_EE_FF", map[string]any{
+		"Address": "AA:BB:CC:DD:EE:FF",
+		"Name":    "Other",
+		"RSSI":    int16(-40),
+		"Adapter": testOtherAdapterPath,
+	}, nil)
+	bluez.AddDevice(t, testKeyboardPath, map[string]any{
+		"Address": "F1:2B:3C:4D:5E:6F",
+		"Name":    "MX Keys",
+		"RSSI":    int16(-60),
+		"Adapter": testAdapterPath,
+	}, nil)
+	// updates of already reported devices are ignored
+	bluez.Set(t, testKeyboardPath, device1Interface, "RSSI", int16(-50))
+	// known devices are reported once they are in range
+	bluez.Set(t, testHeadsetPath, device1Interface, "Connected", false)
+	bluez.Set(t, testHeadsetPath, device1Interface, "RSSI", int16(-42))
+
+	// THEN
+	assert.NoError(t, <-done)
+	assert.Equal(t, []string{
+		string(testAdapterPath) + ".StartDiscovery",
+		string(testAdapterPath) + ".StopDiscovery",
+	}, bluez.Calls())
+	assert.Len(t, discovered, 2)
+	assert.Equal(t, "MX Keys", discovered[0].Name)
+	assert.Equal(t, int16(-60), *discovered[0].RSSI)
+	assert.Equal(t, "LG-TONE-FP9", discovered[1].Name)
+	assert.Equal(t, "00:1D:43:6D:03:1A", discovered[1].Address)
+	assert.Equal(t, int16(-42), *discovered[1].RSSI)
+}
+
+func TestDeviceFromProperties(t *testing.T) {
+	// WHEN
+	device := deviceFromProperties(map[string]dbus.Variant{
+		"Address":   dbus.MakeVariant("00:1D:43:6D:03:1A"),
+		"Alias":     dbus.MakeVariant("Headphones"),
+		"Paired":    dbus.MakeVariant(true),
+		"Connected": dbus.MakeVariant(true),
+		"UUIDs":     dbus.MakeVariant([]string{"0000110b-0000-1000-8000-00805f9b34fb", "invalid"}),
+	})
+
+	// THEN
+	assert.Equal(t, "00:1D:43:6D:03:1A", device.Address)
+	assert.Equal(t, "Headphones", device.Name)
+	assert.True(t, device.Paired)
+	assert.True(t, device.Connected)
+	assert.Nil(t, device.RSSI)
+	assert.Len(t, device.UUIDs, 1)
+}
@@ -0,0 +1,76 @@
+package bluetooth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/markusressel/system-control/internal/util"
+)
+
+// MajorClassAudioVideo is the major device class of headsets, headphones and speakers
+const MajorClassAudioVideo uint32 = 0x04
+
+// MajorClass returns the major device class of the device, f.ex. MajorClassAudioVideo, or false if it is unknown
+func (d BluetoothDevice) MajorClass() (uint32, bool) {
+	class, err := strconv.ParseUint(d.Class, 0, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint32(class>>8) & 0x1f, true
+}
+
+// SameClass reports whether both devices are of the same major device class, f.ex. both are audio devices.
+// If the class of one of the devices is unknown, their icons are compared instead.
+func (d BluetoothDevice) SameClass(other BluetoothDevice) bool {
+	class, ok := d.MajorClass()
+	otherClass, otherOk := other.MajorClass()
+	if ok && otherOk {
+		return class == otherClass
+	}
+	return d.Icon != "" && d.Icon == other.Icon
+}
+
+// FindBestDevice returns the first of the given candidates, ordered by priority, which is in range.
+// Connected candidates are in range, all others have to be found by a discovery, which runs until
+// the first candidate is found or the given context is done.
+func FindBestDevice(ctx context.Context, candidates []BluetoothDevice) (BluetoothDevice, error) {
+	return findBestDevice(ctx, candidates, DiscoverBluetoothDevices)
+}
+
+func findBestDevice(
+	ctx context.Context,
+	candidates []BluetoothDevice,
+	discover func(ctx context.Context, handler func(device BluetoothDevice) bool) error,
+) (BluetoothDevice, error) {
+	if len(candidates) == 0 {
+		return BluetoothDevice{}, errors.New("no preferred devices given")
+	}
+
+	best := -1
+	for i, candidate := range candidates {
+		if candidate.Connected {
+			best = i
+			break
+		}
+	}
+
+	if best != 0 {
+		err := discover(ctx, func(device BluetoothDevice) bool {
+			for i, candidate := range candidates {
+				if (best < 0 || i < best) && util.EqualsIgnoreCase(candidate.Address, device.Address) {
+					best = i
+				}
+			}
+			return best == 0
+		})
+		if err != nil {
+			return BluetoothDevice{}, err
+		}
+	}
+
+	if best < 0 {
+		return BluetoothDevice{}, errors.New("none of the preferred devices is in range")
+	}
+	return candidates[best], nil
+}
@@ -0,0 +1,84 @@
+package bluetooth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func discoverAll(devices ...BluetoothDevice) func(ctx context.Context, handler func(device BluetoothDevice) bool) error {
+	return func(ctx context.Context, handler func(device BluetoothDevice) bool) error {
+		for _, device := range devices {
+			if handler(device) {
+				return nil
+			}
+		}
+		return nil
+	}
+}
+
+func TestBluetoothDevice_SameClass(t *testing.T) {
+	headset := BluetoothDevice{Class: "0x00240404", Icon: "audio-headset"}
+	speaker := BluetoothDevice{Class: "0x00240414", Icon: "audio-card"}
+	mouse := BluetoothDevice{Class: "0x00002580", Icon: "input-mouse"}
+	unknown := BluetoothDevice{Icon: "audio-headset"}
+
+	class, ok := headset.MajorClass()
+	assert.True(t, ok)
+	assert.Equal(t, MajorClassAudioVideo, class)
+
+	assert.True(t, headset.SameClass(speaker))
+	assert.False(t, headset.SameClass(mouse))
+	assert.True(t, unknown.SameClass(headset))
+	assert.False(t, unknown.SameClass(speaker))
+}
+
+func TestFindBestDevice(t *testing.T) {
+	// GIVEN
+	first := BluetoothDevice{Name: "First", Address: "00:00:00:00:00:01"}
+	second := BluetoothDevice{Name: "Second", Address: "00:00:00:00:00:02", Connected: true}
+	third := BluetoothDevice{Name: "Third", Address: "00:00:00:00:00:03"}
+	candidates := []BluetoothDevice{first, second, third}
+
+	// WHEN
+	inRange, err := findBestDevice(context.Background(), candidates, discoverAll(third, first))
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, "First", inRange.Name)
+
+	// the connected device is used if no device of a higher priority is in range
+	connected, err := findBestDevice(context.Background(), candidates, discoverAll(third))
+	assert.NoError(t, err)
+	assert.Equal(t, "Second", connected.Name)
+}
+
+func TestFindBestDeviceSkipsDiscovery(t *testing.T) {
+	// GIVEN
+	first := BluetoothDevice{Name: "First", Address: "00:00:00:00:00:01", Connected: true}
+	discovered := false
+	discover := func(ctx context.Context, handler func(device BluetoothDevice) bool) error {
+		discovered = true
+		return nil
+	}
+
+	// WHEN
+	best, err := findBestDevice(context.Background(), []BluetoothDevice{first}, discover)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, "First", best.Name)
+	assert.False(t, discovered)
+}
+
+func TestFindBestDeviceNotInRange(t *testing.T) {
+	// GIVEN
+	candidates := []BluetoothDevice{{Name: "First", Address: "00:00:00:00:00:01"}}
+
+	// WHEN
+	_, err := findBestDevice(context.Background(), candidates, discoverAll(BluetoothDevice{Address: "00:00:00:00:00:09"}))
+
+	// THEN
+	assert.EqualError(t, err, "none of the preferred devices is in range")
+}
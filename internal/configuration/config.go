@@ -14,14 +14,102 @@ import (
 )
 
 type Configuration struct {
-	Redshift RedshiftConfig `mapstructure:"redshift" yaml:"redshift"`
+	Backlight BacklightConfig `mapstructure:"backlight" yaml:"backlight"`
+	Redshift  RedshiftConfig  `mapstructure:"redshift" yaml:"redshift"`
+	Hotspot   HotspotConfig   `mapstructure:"hotspot" yaml:"hotspot"`
+	Bluetooth BluetoothConfig `mapstructure:"bluetooth" yaml:"bluetooth"`
+	Battery   BatteryConfig   `mapstructure:"battery" yaml:"battery"`
+}
+
+type BacklightConfig struct {
+	Auto BacklightAutoConfig `mapstructure:"auto" yaml:"auto"`
+}
+
+type BacklightAutoConfig struct {
+	// Sensor is the IIO device name (f.ex. "iio:device0") or label of the ambient light sensor to use.
+	// If empty, the first found sensor is used.
+	Sensor string `mapstructure:"sensor" yaml:"sensor"`
+	// Interval is the time between two sensor readings
+	Interval time.Duration `mapstructure:"interval" yaml:"interval"`
+	// Hysteresis is the minimum change of the target brightness (in percentage points) before it is applied
+	Hysteresis float64 `mapstructure:"hysteresis" yaml:"hysteresis"`
+	// Smoothing is the weight (between 0 and 1) of a new sensor reading, 1.0 disables smoothing
+	Smoothing float64 `mapstructure:"smoothing" yaml:"smoothing"`
+	// BiasTimeout is the duration after which a manual brightness adjustment is discarded, 0 keeps it forever
+	BiasTimeout time.Duration `mapstructure:"biasTimeout" yaml:"biasTimeout"`
+	// Curve maps ambient light levels to brightness values
+	Curve []BacklightCurvePoint `mapstructure:"curve" yaml:"curve"`
+}
+
+type BacklightCurvePoint struct {
+	Lux        float64 `mapstructure:"lux" yaml:"lux"`
+	Brightness float64 `mapstructure:"brightness" yaml:"brightness"`
 }
 
 type RedshiftConfig struct {
-	TransitionDuration time.Duration                  `mapstructure:"transitionDuration" yaml:"transitionDuration"`
-	Brightness         RedshiftBrightnessConfig       `mapstructure:"brightness" yaml:"brightness"`
-	ColorTemperature   RedshiftColorTemperatureConfig `mapstructure:"colorTemperature" yaml:"colorTemperature"`
-	Gamma              RedshiftGammaConfig            `mapstructure:"gamma" yaml:"gamma"`
+	TransitionDuration time.Duration          `mapstructure:"transitionDuration" yaml:"transitionDuration"`
+	Location           RedshiftLocationConfig `mapstructure:"location" yaml:"location"`
+	Schedule           RedshiftScheduleConfig `mapstructure:"schedule" yaml:"schedule"`
+	Profiles           RedshiftProfilesConfig `mapstructure:"profiles" yaml:"profiles"`
+	// Displays contains per-display overrides of the profiles, keyed by display name
+	Displays         map[string]RedshiftProfilesConfig `mapstructure:"displays" yaml:"displays"`
+	Brightness       RedshiftBrightnessConfig          `mapstructure:"brightness" yaml:"brightness"`
+	ColorTemperature RedshiftColorTemperatureConfig    `mapstructure:"colorTemperature" yaml:"colorTemperature"`
+	Gamma            RedshiftGammaConfig               `mapstructure:"gamma" yaml:"gamma"`
+}
+
+const (
+	// LocationProviderAuto uses the redshift configuration file if it contains a location, the timezone otherwise
+	LocationProviderAuto = "auto"
+	// LocationProviderManual uses the configured latitude and longitude
+	LocationProviderManual = "manual"
+	// LocationProviderTimezone derives an approximate location from the system timezone
+	LocationProviderTimezone = "timezone"
+	// LocationProviderRedshift reads the location from the [manual] section of ~/.config/redshift.conf
+	LocationProviderRedshift = "redshift"
+
+	// ScheduleModeSolar switches between day and night based on the elevation of the sun
+	ScheduleModeSolar = "solar"
+	// ScheduleModeTime switches between day and night at fixed times of the day
+	ScheduleModeTime = "time"
+)
+
+type RedshiftLocationConfig struct {
+	// Provider is one of "auto", "manual", "timezone" or "redshift"
+	Provider  string  `mapstructure:"provider" yaml:"provider"`
+	Latitude  float64 `mapstructure:"latitude" yaml:"latitude"`
+	Longitude float64 `mapstructure:"longitude" yaml:"longitude"`
+}
+
+type RedshiftScheduleConfig struct {
+	// Mode is either "solar" or "time"
+	Mode string `mapstructure:"mode" yaml:"mode"`
+	// Dawn is the time range of the transition from night to day, only used in "time" mode
+	Dawn RedshiftTimeRangeConfig `mapstructure:"dawn" yaml:"dawn"`
+	// Dusk is the time range of the transition from day to night, only used in "time" mode
+	Dusk RedshiftTimeRangeConfig `mapstructure:"dusk" yaml:"dusk"`
+}
+
+type RedshiftTimeRangeConfig struct {
+	// Start is the time of day in the format HH:MM
+	Start string `mapstructure:"start" yaml:"start"`
+	// End is the time of day in the format HH:MM
+	End string `mapstructure:"end" yaml:"end"`
+}
+
+// RedshiftProfilesConfig contains the target values for each period of the day.
+// During dawn and dusk, values are interpolated between night, twilight and day.
+type RedshiftProfilesConfig struct {
+	Day      RedshiftProfileConfig `mapstructure:"day" yaml:"day"`
+	Twilight RedshiftProfileConfig `mapstructure:"twilight" yaml:"twilight"`
+	Night    RedshiftProfileConfig `mapstructure:"night" yaml:"night"`
+}
+
+// RedshiftProfileConfig contains target values for a single period of the day, 0 means unset.
+type RedshiftProfileConfig struct {
+	ColorTemperature int64   `mapstructure:"colorTemperature" yaml:"colorTemperature"`
+	Brightness       float64 `mapstructure:"brightness" yaml:"brightness"`
+	Gamma            float64 `mapstructure:"gamma" yaml:"gamma"`
 }
 
 type RedshiftBrightnessConfig struct {
@@ -39,6 +127,53 @@ type RedshiftGammaConfig struct {
 	MaximumGamma float64 `mapstructure:"maximumGamma" yaml:"maximumGamma"`
 }
 
+type HotspotConfig struct {
+	// Interface is the WiFi device used for the hotspot, if empty the first WiFi device is used
+	Interface string `mapstructure:"interface" yaml:"interface"`
+	// SSID is the name of the hotspot network, if empty the hostname is used
+	SSID string `mapstructure:"ssid" yaml:"ssid"`
+	// Password of the hotspot network, if empty a random password is generated on first use
+	Password string `mapstructure:"password" yaml:"password"`
+	// Band is either "2.4" or "5", if empty NetworkManager selects the band
+	Band string `mapstructure:"band" yaml:"band"`
+	// Channel is the channel number or "auto" to use the least congested channel of the band
+	Channel string `mapstructure:"channel" yaml:"channel"`
+	// Security is one of "wpa2", "wpa3" or "open"
+	Security string `mapstructure:"security" yaml:"security"`
+	// Firewall is the backend used to block and limit clients, one of "auto", "nftables" or "iptables"
+	Firewall string `mapstructure:"firewall" yaml:"firewall"`
+}
+
+type BluetoothConfig struct {
+	// Preferred are the names or addresses of the preferred audio devices, ordered by priority (highest first)
+	Preferred   []string                   `mapstructure:"preferred" yaml:"preferred"`
+	Autoconnect BluetoothAutoconnectConfig `mapstructure:"autoconnect" yaml:"autoconnect"`
+	Battery     BluetoothBatteryConfig     `mapstructure:"battery" yaml:"battery"`
+}
+
+type BluetoothAutoconnectConfig struct {
+	// Devices are the names or addresses of the favorite devices, which are reconnected automatically
+	Devices []string `mapstructure:"devices" yaml:"devices"`
+	// Interval is the time between two reconnection attempts of favorite devices which are not in range
+	Interval time.Duration `mapstructure:"interval" yaml:"interval"`
+}
+
+type BluetoothBatteryConfig struct {
+	// Threshold is the battery level (in percent) below which a low battery notification is sent
+	Threshold int `mapstructure:"threshold" yaml:"threshold"`
+	// Interval is the time between two checks of the battery levels
+	Interval time.Duration `mapstructure:"interval" yaml:"interval"`
+}
+
+type BatteryConfig struct {
+	History BatteryHistoryConfig `mapstructure:"history" yaml:"history"`
+}
+
+type BatteryHistoryConfig struct {
+	// Threshold is the capacity (in percent of the design capacity) for which the date it is reached is projected
+	Threshold float64 `mapstructure:"threshold" yaml:"threshold"`
+}
+
 var CurrentConfig Configuration
 
 var currentUser, _ = user.Current()
@@ -77,13 +212,51 @@ func ensureConfigDirExists() error {
 }
 
 func setDefaultValues() {
+	viper.SetDefault("backlight.auto.sensor", "")
+	viper.SetDefault("backlight.auto.interval", 1*time.Second)
+	viper.SetDefault("backlight.auto.hysteresis", 3.0)
+	viper.SetDefault("backlight.auto.smoothing", 0.3)
+	viper.SetDefault("backlight.auto.biasTimeout", 30*time.Minute)
+	viper.SetDefault("backlight.auto.curve", []map[string]any{
+		{"lux": 0, "brightness": 5},
+		{"lux": 10, "brightness": 20},
+		{"lux": 100, "brightness": 40},
+		{"lux": 1000, "brightness": 75},
+		{"lux": 10000, "brightness": 100},
+	})
 	viper.SetDefault("redshift.transitionDuration", 60*time.Minute)
+	viper.SetDefault("redshift.location.provider", LocationProviderAuto)
+	viper.SetDefault("redshift.location.latitude", 0.0)
+	viper.SetDefault("redshift.location.longitude", 0.0)
+	viper.SetDefault("redshift.schedule.mode", ScheduleModeSolar)
+	viper.SetDefault("redshift.schedule.dawn.start", "06:00")
+	viper.SetDefault("redshift.schedule.dawn.end", "07:00")
+	viper.SetDefault("redshift.schedule.dusk.start", "19:00")
+	viper.SetDefault("redshift.schedule.dusk.end", "20:00")
+	for _, period := range []string{"day", "twilight", "night"} {
+		viper.SetDefault("redshift.profiles."+period+".colorTemperature", 0)
+		viper.SetDefault("redshift.profiles."+period+".brightness", 0.0)
+		viper.SetDefault("redshift.profiles."+period+".gamma", 0.0)
+	}
 	viper.SetDefault("redshift.brightness.minimumBrightness", 0.1)
 	viper.SetDefault("redshift.brightness.maximumBrightness", 1.0)
 	viper.SetDefault("redshift.colorTemperature.minimumColorTemperature", 1000)
 	viper.SetDefault("redshift.colorTemperature.maximumColorTemperature", 25000)
 	viper.SetDefault("redshift.gamma.minimumGamma", 0.1)
 	viper.SetDefault("redshift.gamma.maximumGamma", 2.0)
+	viper.SetDefault("hotspot.interface", "")
+	viper.SetDefault("hotspot.ssid", "")
+	viper.SetDefault("hotspot.password", "")
+	viper.SetDefault("hotspot.band", "")
+	viper.SetDefault("hotspot.channel", "")
+	viper.SetDefault("hotspot.security", "wpa2")
+	viper.SetDefault("hotspot.firewall", "auto")
+	viper.SetDefault("bluetooth.preferred", []string{})
+	viper.SetDefault("bluetooth.autoconnect.devices", []string{})
+	viper.SetDefault("bluetooth.autoconnect.interval", 30*time.Second)
+	viper.SetDefault("bluetooth.battery.threshold", 20)
+	viper.SetDefault("bluetooth.battery.interval", 5*time.Minute)
+	viper.SetDefault("battery.history.threshold", 80.0)
 }
 
 // DetectAndReadConfigFile detects the path of the first existing config file
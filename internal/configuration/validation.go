@@ -1,9 +1,182 @@
 package configuration
 
-func Validate(configPath string) error {
-	return validateConfig(&CurrentConfig, configPath)
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/markusressel/system-control/internal/util"
+)
+
+// Validate checks all sections of the current configuration and returns the errors of all invalid sections.
+// Commands only validate the sections they use, so an invalid setting of one feature does not break unrelated commands.
+func Validate() error {
+	return errors.Join(
+		ValidateBacklight(),
+		ValidateRedshift(),
+		ValidateHotspot(),
+		ValidateBluetooth(),
+		ValidateBattery(),
+	)
+}
+
+// ValidateBacklight checks the backlight section of the current configuration
+func ValidateBacklight() error {
+	return validateBacklightConfig(CurrentConfig.Backlight, GetFilePath())
+}
+
+func validateBacklightConfig(config BacklightConfig, path string) error {
+	auto := config.Auto
+	if auto.Interval <= 0 {
+		return fmt.Errorf("%s: backlight.auto.interval must be positive", path)
+	}
+	if auto.Smoothing <= 0 || auto.Smoothing > 1 {
+		return fmt.Errorf("%s: backlight.auto.smoothing must be in (0, 1]", path)
+	}
+	if auto.Hysteresis < 0 {
+		return fmt.Errorf("%s: backlight.auto.hysteresis must not be negative", path)
+	}
+	for i, point := range auto.Curve {
+		if point.Lux < 0 {
+			return fmt.Errorf("%s: backlight.auto.curve[%d].lux must not be negative", path, i)
+		}
+		if point.Brightness < 0 || point.Brightness > 100 {
+			return fmt.Errorf("%s: backlight.auto.curve[%d].brightness must be between 0 and 100", path, i)
+		}
+	}
+	return nil
+}
+
+// ValidateRedshift checks the redshift section of the current configuration
+func ValidateRedshift() error {
+	return validateRedshiftConfig(CurrentConfig.Redshift, GetFilePath())
+}
+
+func validateRedshiftConfig(config RedshiftConfig, path string) error {
+	location := config.Location
+	switch location.Provider {
+	case LocationProviderAuto, LocationProviderManual, LocationProviderTimezone, LocationProviderRedshift:
+	default:
+		return fmt.Errorf("%s: unknown redshift.location.provider '%s'", path, location.Provider)
+	}
+	if location.Latitude < -90 || location.Latitude > 90 {
+		return fmt.Errorf("%s: redshift.location.latitude must be between -90 and 90", path)
+	}
+	if location.Longitude < -180 || location.Longitude > 180 {
+		return fmt.Errorf("%s: redshift.location.longitude must be between -180 and 180", path)
+	}
+
+	profiles := map[string]RedshiftProfilesConfig{"profiles": config.Profiles}
+	for name, displayProfiles := range config.Displays {
+		profiles["displays."+name] = displayProfiles
+	}
+	for name, p := range profiles {
+		for period, profile := range map[string]RedshiftProfileConfig{"day": p.Day, "twilight": p.Twilight, "night": p.Night} {
+			if profile.ColorTemperature != 0 && (profile.ColorTemperature < 1000 || profile.ColorTemperature > 25000) {
+				return fmt.Errorf("%s: redshift.%s.%s.colorTemperature must be between 1000 and 25000", path, name, period)
+			}
+			if profile.Brightness < 0 || profile.Brightness > 1.0 {
+				return fmt.Errorf("%s: redshift.%s.%s.brightness must be between 0 and 1.0", path, name, period)
+			}
+			if profile.Gamma < 0 {
+				return fmt.Errorf("%s: redshift.%s.%s.gamma must be positive", path, name, period)
+			}
+		}
+	}
+
+	schedule := config.Schedule
+	switch schedule.Mode {
+	case ScheduleModeSolar:
+	case ScheduleModeTime:
+		times := []string{schedule.Dawn.Start, schedule.Dawn.End, schedule.Dusk.Start, schedule.Dusk.End}
+		for i, value := range times {
+			timeOfDay, err := util.ParseTimeOfDay(value)
+			if err != nil {
+				return fmt.Errorf("%s: redshift.schedule: %w", path, err)
+			}
+			if i > 0 {
+				previous, _ := util.ParseTimeOfDay(times[i-1])
+				if timeOfDay < previous {
+					return fmt.Errorf("%s: redshift.schedule: dawn and dusk times must be in order (dawn.start <= dawn.end <= dusk.start <= dusk.end)", path)
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("%s: unknown redshift.schedule.mode '%s'", path, schedule.Mode)
+	}
+
+	return nil
+}
+
+// ValidateHotspot checks the hotspot section of the current configuration
+func ValidateHotspot() error {
+	return ValidateHotspotConfig(CurrentConfig.Hotspot, GetFilePath())
+}
+
+// ValidateHotspotConfig checks the given hotspot settings, f.ex. after they have been overridden by flags.
+// Errors are prefixed with the given source of the settings.
+func ValidateHotspotConfig(config HotspotConfig, path string) error {
+	switch config.Band {
+	case "", "2.4", "5":
+	default:
+		return fmt.Errorf("%s: unknown hotspot.band '%s'", path, config.Band)
+	}
+	if config.Channel != "" {
+		if config.Band == "" {
+			return fmt.Errorf("%s: hotspot.channel requires hotspot.band", path)
+		}
+		channel, err := strconv.Atoi(config.Channel)
+		if config.Channel != "auto" && (err != nil || channel <= 0) {
+			return fmt.Errorf("%s: hotspot.channel must be a channel number or 'auto'", path)
+		}
+	}
+	switch config.Security {
+	case "wpa2", "wpa3", "open":
+	default:
+		return fmt.Errorf("%s: unknown hotspot.security '%s'", path, config.Security)
+	}
+	switch config.Firewall {
+	case "auto", "nftables", "iptables":
+	default:
+		return fmt.Errorf("%s: unknown hotspot.firewall '%s'", path, config.Firewall)
+	}
+	if config.Password != "" && (len(config.Password) < 8 || len(config.Password) > 63) {
+		return fmt.Errorf("%s: hotspot.password must be between 8 and 63 characters long", path)
+	}
+	return nil
+}
+
+// ValidateBluetooth checks the bluetooth section of the current configuration
+func ValidateBluetooth() error {
+	return validateBluetoothConfig(CurrentConfig.Bluetooth, GetFilePath())
+}
+
+func validateBluetoothConfig(config BluetoothConfig, path string) error {
+	for _, device := range config.Preferred {
+		if device == "" {
+			return fmt.Errorf("%s: bluetooth.preferred must not contain empty entries", path)
+		}
+	}
+	if config.Autoconnect.Interval <= 0 {
+		return fmt.Errorf("%s: bluetooth.autoconnect.interval must be positive", path)
+	}
+	if config.Battery.Threshold < 0 || config.Battery.Threshold > 100 {
+		return fmt.Errorf("%s: bluetooth.battery.threshold must be between 0 and 100", path)
+	}
+	if config.Battery.Interval <= 0 {
+		return fmt.Errorf("%s: bluetooth.battery.interval must be positive", path)
+	}
+	return nil
+}
+
+// ValidateBattery checks the battery section of the current configuration
+func ValidateBattery() error {
+	return validateBatteryConfig(CurrentConfig.Battery, GetFilePath())
 }
 
-func validateConfig(config *Configuration, path string) error {
+func validateBatteryConfig(config BatteryConfig, path string) error {
+	if config.History.Threshold <= 0 || config.History.Threshold >= 100 {
+		return fmt.Errorf("%s: battery.history.threshold must be between 0 and 100", path)
+	}
 	return nil
 }
@@ -0,0 +1,189 @@
+package daylight
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/markusressel/system-control/internal/configuration"
+	"github.com/markusressel/system-control/internal/util"
+)
+
+const (
+	zoneInfoPath = "/usr/share/zoneinfo"
+	localTime    = "/etc/localtime"
+	timezoneFile = "/etc/timezone"
+)
+
+// zoneTabFiles are the tzdata tables containing the coordinates of each timezone's principal city
+var zoneTabFiles = []string{"zone1970.tab", "zone.tab"}
+
+// Location is a position on earth
+type Location struct {
+	Latitude  float64
+	Longitude float64
+	// Source describes where the location was taken from
+	Source string
+}
+
+// ResolveLocation returns the location described by the given configuration
+func ResolveLocation(config configuration.RedshiftLocationConfig) (Location, error) {
+	switch config.Provider {
+	case configuration.LocationProviderManual:
+		return Location{
+			Latitude:  config.Latitude,
+			Longitude: config.Longitude,
+			Source:    configuration.LocationProviderManual,
+		}, nil
+	case configuration.LocationProviderTimezone:
+		return LocationFromTimezone()
+	case configuration.LocationProviderRedshift:
+		return LocationFromRedshiftConfig()
+	case configuration.LocationProviderAuto, "":
+		location, err := LocationFromRedshiftConfig()
+		if err == nil {
+			return location, nil
+		}
+		return LocationFromTimezone()
+	default:
+		return Location{}, fmt.Errorf("unknown location provider: %s", config.Provider)
+	}
+}
+
+// LocationFromRedshiftConfig reads the location from the [manual] section of ~/.config/redshift.conf
+func LocationFromRedshiftConfig() (Location, error) {
+	redshiftConfig, err := util.ReadRedshiftConfig()
+	if err != nil {
+		return Location{}, err
+	}
+	if redshiftConfig.Manual.Lat == 0 && redshiftConfig.Manual.Lon == 0 {
+		return Location{}, errors.New("redshift configuration does not contain a location")
+	}
+	return Location{
+		Latitude:  redshiftConfig.Manual.Lat,
+		Longitude: redshiftConfig.Manual.Lon,
+		Source:    configuration.LocationProviderRedshift,
+	}, nil
+}
+
+// LocationFromTimezone returns the approximate location of the system timezone, which is the location
+// of its principal city as listed in the tzdata zone tables. If the timezone is not listed,
+// the longitude is estimated from its UTC offset.
+func LocationFromTimezone() (Location, error) {
+	name := detectTimezoneName()
+	for _, tabFile := range zoneTabFiles {
+		location, err := findZoneLocation(filepath.Join(zoneInfoPath, tabFile), name)
+		if err == nil {
+			return location, nil
+		}
+	}
+
+	_, offset := time.Now().Zone()
+	return Location{
+		Latitude:  0,
+		Longitude: float64(offset) / (60 * 60) * 15,
+		Source:    fmt.Sprintf("%s (UTC offset)", configuration.LocationProviderTimezone),
+	}, nil
+}
+
+// detectTimezoneName returns the IANA name of the system timezone, f.ex. "Europe/Berlin"
+func detectTimezoneName() string {
+	if tz := strings.TrimPrefix(os.Getenv("TZ"), ":"); len(tz) > 0 {
+		return tz
+	}
+	if target, err := filepath.EvalSymlinks(localTime); err == nil {
+		if _, name, found := strings.Cut(target, "zoneinfo/"); found {
+			return name
+		}
+	}
+	if content, err := util.ReadTextFromFile(timezoneFile); err == nil {
+		return strings.TrimSpace(content)
+	}
+	return time.Local.String()
+}
+
+// findZoneLocation looks up the coordinates of the given timezone in a tzdata zone table
+func findZoneLocation(tabFile string, timezone string) (Location, error) {
+	file, err := os.Open(tabFile)
+	if err != nil {
+		return Location{}, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		columns := strings.Split(line, "\t")
+		if len(columns) < 3 || columns[2] != timezone {
+			continue
+		}
+		latitude, longitude, err := ParseISO6709(columns[1])
+		if err != nil {
+			return Location{}, err
+		}
+		return Location{
+			Latitude:  latitude,
+			Longitude: longitude,
+			Source:    fmt.Sprintf("%s (%s)", configuration.LocationProviderTimezone, timezone),
+		}, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return Location{}, err
+	}
+	return Location{}, fmt.Errorf("timezone %s not found in %s", timezone, tabFile)
+}
+
+// ParseISO6709 parses coordinates in the ISO 6709 sign-degrees-minutes(-seconds) format
+// used by the tzdata zone tables, f.ex. "+5230+01322" or "-332154+1493942".
+func ParseISO6709(value string) (latitude float64, longitude float64, err error) {
+	split := strings.IndexAny(value[1:], "+-") + 1
+	if split <= 0 {
+		return 0, 0, fmt.Errorf("invalid coordinates: %s", value)
+	}
+	latitude, err = parseISO6709Component(value[:split], 2)
+	if err != nil {
+		return 0, 0, err
+	}
+	longitude, err = parseISO6709Component(value[split:], 3)
+	if err != nil {
+		return 0, 0, err
+	}
+	return latitude, longitude, nil
+}
+
+func parseISO6709Component(value string, degreeDigits int) (float64, error) {
+	if len(value) < 1+degreeDigits+2 {
+		return 0, fmt.Errorf("invalid coordinate: %s", value)
+	}
+	sign := 1.0
+	if value[0] == '-' {
+		sign = -1.0
+	}
+	digits := value[1:]
+
+	degrees, err := strconv.Atoi(digits[:degreeDigits])
+	if err != nil {
+		return 0, err
+	}
+	minutes, err := strconv.Atoi(digits[degreeDigits : degreeDigits+2])
+	if err != nil {
+		return 0, err
+	}
+	seconds := 0
+	if len(digits) >= degreeDigits+4 {
+		seconds, err = strconv.Atoi(digits[degreeDigits+2 : degreeDigits+4])
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return sign * (float64(degrees) + float64(minutes)/60 + float64(seconds)/3600), nil
+}
@@ -0,0 +1,43 @@
+package daylight
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseISO6709(t *testing.T) {
+	latitude, longitude, err := ParseISO6709("+5230+01322")
+	assert.NoError(t, err)
+	assert.InDelta(t, 52.5, latitude, 1e-9)
+	assert.InDelta(t, 13.3667, longitude, 1e-4)
+
+	latitude, longitude, err = ParseISO6709("-332154+1493942")
+	assert.NoError(t, err)
+	assert.InDelta(t, -33.365, latitude, 1e-3)
+	assert.InDelta(t, 149.6617, longitude, 1e-3)
+
+	_, _, err = ParseISO6709("+52")
+	assert.Error(t, err)
+}
+
+func TestFindZoneLocation(t *testing.T) {
+	// GIVEN
+	tabFile := filepath.Join(t.TempDir(), "zone1970.tab")
+	content := "# comment\n" +
+		"DE,DK,NO,SE,SJ\t+5230+01322\tEurope/Berlin\tmost of Germany\n" +
+		"US\t+404251-0740023\tAmerica/New_York\tEastern (most areas)\n"
+	assert.NoError(t, os.WriteFile(tabFile, []byte(content), 0644))
+
+	// WHEN
+	location, err := findZoneLocation(tabFile, "America/New_York")
+	_, missingErr := findZoneLocation(tabFile, "Europe/Paris")
+
+	// THEN
+	assert.NoError(t, err)
+	assert.InDelta(t, 40.714, location.Latitude, 1e-3)
+	assert.InDelta(t, -74.006, location.Longitude, 1e-3)
+	assert.Error(t, missingErr)
+}
@@ -0,0 +1,196 @@
+package daylight
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/markusressel/system-control/internal/configuration"
+	"github.com/markusressel/system-control/internal/util"
+	"github.com/nathan-osman/go-sunrise"
+)
+
+const (
+	// TransitionElevationThreshold is the sun's elevation threshold at which the day is fully reached.
+	TransitionElevationThreshold = 20
+
+	PeriodNight = "night"
+	PeriodDawn  = "dawn"
+	PeriodDay   = "day"
+	PeriodDusk  = "dusk"
+)
+
+// Event marks the start of a period
+type Event struct {
+	// Period is the period that starts with this event
+	Period string
+	Time   time.Time
+}
+
+// Schedule describes when it is day and when it is night
+type Schedule interface {
+	// DaylightFactor returns 0 during the night, 1 during the day and a value in between during dawn and dusk
+	DaylightFactor(t time.Time) float64
+	// Events returns all events between from and to, sorted by time
+	Events(from time.Time, to time.Time) []Event
+}
+
+// NewSchedule creates the Schedule described by the given configuration
+func NewSchedule(config configuration.RedshiftConfig) (Schedule, error) {
+	switch config.Schedule.Mode {
+	case configuration.ScheduleModeTime:
+		return NewTimeSchedule(config.Schedule)
+	case configuration.ScheduleModeSolar, "":
+		location, err := ResolveLocation(config.Location)
+		if err != nil {
+			return nil, err
+		}
+		return SolarSchedule{Location: location}, nil
+	default:
+		return nil, fmt.Errorf("unknown schedule mode: %s", config.Schedule.Mode)
+	}
+}
+
+// CurrentPeriod returns the period of the given schedule at the given point in time
+func CurrentPeriod(schedule Schedule, t time.Time) string {
+	events := schedule.Events(t.Add(-48*time.Hour), t)
+	if len(events) > 0 {
+		return events[len(events)-1].Period
+	}
+	// no events, f.ex. during polar day or night
+	if schedule.DaylightFactor(t) >= 1 {
+		return PeriodDay
+	}
+	return PeriodNight
+}
+
+// SolarSchedule is based on the elevation of the sun at a given location
+type SolarSchedule struct {
+	Location Location
+}
+
+func (s SolarSchedule) DaylightFactor(t time.Time) float64 {
+	elevation := sunrise.Elevation(s.Location.Latitude, s.Location.Longitude, t)
+	return util.Clamp(elevation/TransitionElevationThreshold, 0.0, 1.0)
+}
+
+func (s SolarSchedule) Events(from time.Time, to time.Time) []Event {
+	var events []Event
+	for day := from.AddDate(0, 0, -1); !day.After(to.AddDate(0, 0, 1)); day = day.AddDate(0, 0, 1) {
+		year, month, dayOfMonth := day.Date()
+		horizonMorning, horizonEvening := sunrise.TimeOfElevation(s.Location.Latitude, s.Location.Longitude, 0, year, month, dayOfMonth)
+		thresholdMorning, thresholdEvening := sunrise.TimeOfElevation(s.Location.Latitude, s.Location.Longitude, TransitionElevationThreshold, year, month, dayOfMonth)
+
+		events = appendEvent(events, PeriodDawn, horizonMorning, from, to)
+		events = appendEvent(events, PeriodDay, thresholdMorning, from, to)
+		events = appendEvent(events, PeriodDusk, thresholdEvening, from, to)
+		events = appendEvent(events, PeriodNight, horizonEvening, from, to)
+	}
+	return sortEvents(events)
+}
+
+// TimeSchedule switches between day and night at fixed times of the day
+type TimeSchedule struct {
+	DawnStart time.Duration
+	DawnEnd   time.Duration
+	DuskStart time.Duration
+	DuskEnd   time.Duration
+}
+
+func NewTimeSchedule(config configuration.RedshiftScheduleConfig) (TimeSchedule, error) {
+	var schedule TimeSchedule
+	values := []struct {
+		target *time.Duration
+		value  string
+	}{
+		{&schedule.DawnStart, config.Dawn.Start},
+		{&schedule.DawnEnd, config.Dawn.End},
+		{&schedule.DuskStart, config.Dusk.Start},
+		{&schedule.DuskEnd, config.Dusk.End},
+	}
+	for _, v := range values {
+		parsed, err := util.ParseTimeOfDay(v.value)
+		if err != nil {
+			return schedule, err
+		}
+		*v.target = parsed
+	}
+	return schedule, nil
+}
+
+func (s TimeSchedule) DaylightFactor(t time.Time) float64 {
+	timeOfDay := clockTime(t)
+	switch {
+	case timeOfDay < s.DawnStart || timeOfDay >= s.DuskEnd:
+		return 0
+	case timeOfDay < s.DawnEnd:
+		return progress(timeOfDay, s.DawnStart, s.DawnEnd)
+	case timeOfDay < s.DuskStart:
+		return 1
+	default:
+		return 1 - progress(timeOfDay, s.DuskStart, s.DuskEnd)
+	}
+}
+
+func (s TimeSchedule) Events(from time.Time, to time.Time) []Event {
+	var events []Event
+	for day := midnight(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		events = appendEvent(events, PeriodDawn, atClockTime(day, s.DawnStart), from, to)
+		events = appendEvent(events, PeriodDay, atClockTime(day, s.DawnEnd), from, to)
+		events = appendEvent(events, PeriodDusk, atClockTime(day, s.DuskStart), from, to)
+		events = appendEvent(events, PeriodNight, atClockTime(day, s.DuskEnd), from, to)
+	}
+	return sortEvents(events)
+}
+
+func midnight(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// clockTime returns the wall clock time of t as the duration since midnight,
+// which differs from the elapsed time since midnight on days with a DST transition
+func clockTime(t time.Time) time.Duration {
+	hour, minute, second := t.Clock()
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute +
+		time.Duration(second)*time.Second + time.Duration(t.Nanosecond())
+}
+
+// atClockTime returns the point in time at which the wall clock shows the given time of day on the given day
+func atClockTime(day time.Time, timeOfDay time.Duration) time.Time {
+	year, month, dayOfMonth := day.Date()
+	hour := int(timeOfDay / time.Hour)
+	minute := int(timeOfDay % time.Hour / time.Minute)
+	return time.Date(year, month, dayOfMonth, hour, minute, 0, 0, day.Location())
+}
+
+func progress(value time.Duration, start time.Duration, end time.Duration) float64 {
+	if end <= start {
+		return 1
+	}
+	return float64(value-start) / float64(end-start)
+}
+
+func appendEvent(events []Event, period string, t time.Time, from time.Time, to time.Time) []Event {
+	if t.IsZero() || t.Before(from) || t.After(to) {
+		return events
+	}
+	return append(events, Event{Period: period, Time: t})
+}
+
+func sortEvents(events []Event) []Event {
+	slices.SortFunc(events, func(a, b Event) int {
+		return a.Time.Compare(b.Time)
+	})
+	return events
+}
+
+// Interpolate returns the value for the given daylight factor, interpolating linearly
+// between night (factor 0), twilight (factor 0.5) and day (factor 1).
+func Interpolate(factor float64, night float64, twilight float64, day float64) float64 {
+	factor = util.Clamp(factor, 0.0, 1.0)
+	if factor < 0.5 {
+		return night + (twilight-night)*factor*2
+	}
+	return twilight + (day-twilight)*(factor-0.5)*2
+}
@@ -0,0 +1,91 @@
+package daylight
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testTimeSchedule = TimeSchedule{
+	DawnStart: 6 * time.Hour,
+	DawnEnd:   7 * time.Hour,
+	DuskStart: 19 * time.Hour,
+	DuskEnd:   20 * time.Hour,
+}
+
+func at(hour int, minute int) time.Time {
+	return time.Date(2024, 6, 1, hour, minute, 0, 0, time.UTC)
+}
+
+func TestTimeScheduleDaylightFactor(t *testing.T) {
+	assert.Equal(t, 0.0, testTimeSchedule.DaylightFactor(at(3, 0)))
+	assert.Equal(t, 0.5, testTimeSchedule.DaylightFactor(at(6, 30)))
+	assert.Equal(t, 1.0, testTimeSchedule.DaylightFactor(at(12, 0)))
+	assert.Equal(t, 0.75, testTimeSchedule.DaylightFactor(at(19, 15)))
+	assert.Equal(t, 0.0, testTimeSchedule.DaylightFactor(at(22, 0)))
+}
+
+func TestTimeScheduleEvents(t *testing.T) {
+	// WHEN
+	events := testTimeSchedule.Events(at(12, 0), at(12, 0).Add(24*time.Hour))
+
+	// THEN
+	assert.Equal(t, []Event{
+		{Period: PeriodDusk, Time: at(19, 0)},
+		{Period: PeriodNight, Time: at(20, 0)},
+		{Period: PeriodDawn, Time: at(6, 0).AddDate(0, 0, 1)},
+		{Period: PeriodDay, Time: at(7, 0).AddDate(0, 0, 1)},
+	}, events)
+	assert.Equal(t, PeriodDay, CurrentPeriod(testTimeSchedule, at(12, 0)))
+	assert.Equal(t, PeriodDusk, CurrentPeriod(testTimeSchedule, at(19, 30)))
+}
+
+func TestTimeScheduleDaylightSavingTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	// clocks are set forward from 02:00 to 03:00 on 2024-03-31 and back from 03:00 to 02:00 on 2024-10-27
+	for _, day := range []time.Time{time.Date(2024, 3, 31, 0, 0, 0, 0, berlin), time.Date(2024, 10, 27, 0, 0, 0, 0, berlin)} {
+		localAt := func(hour int, minute int) time.Time {
+			return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, berlin)
+		}
+
+		// WHEN
+		events := testTimeSchedule.Events(localAt(0, 0), localAt(23, 59))
+
+		// THEN
+		assert.Equal(t, []Event{
+			{Period: PeriodDawn, Time: localAt(6, 0)},
+			{Period: PeriodDay, Time: localAt(7, 0)},
+			{Period: PeriodDusk, Time: localAt(19, 0)},
+			{Period: PeriodNight, Time: localAt(20, 0)},
+		}, events)
+		assert.Equal(t, 0.5, testTimeSchedule.DaylightFactor(localAt(6, 30)))
+		assert.Equal(t, 0.75, testTimeSchedule.DaylightFactor(localAt(19, 15)))
+	}
+}
+
+func TestSolarScheduleDaylightFactor(t *testing.T) {
+	schedule := SolarSchedule{Location: Location{Latitude: 52.5, Longitude: 13.4}}
+
+	noon := time.Date(2024, 6, 21, 11, 0, 0, 0, time.UTC)
+	midnight := time.Date(2024, 6, 21, 23, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 1.0, schedule.DaylightFactor(noon))
+	assert.Equal(t, 0.0, schedule.DaylightFactor(midnight))
+	assert.Len(t, schedule.Events(midnight.Add(-24*time.Hour), midnight), 4)
+}
+
+func TestInterpolate(t *testing.T) {
+	assert.Equal(t, 4500.0, Interpolate(0, 4500, 5500, 6500))
+	assert.Equal(t, 5000.0, Interpolate(0.25, 4500, 5500, 6500))
+	assert.Equal(t, 5500.0, Interpolate(0.5, 4500, 5500, 6500))
+	assert.Equal(t, 6000.0, Interpolate(0.75, 4500, 5500, 6500))
+	assert.Equal(t, 6500.0, Interpolate(1, 4500, 5500, 6500))
+	assert.Equal(t, 6500.0, Interpolate(2, 4500, 5500, 6500))
+
+	// twilight does not have to be between night and day
+	assert.Equal(t, 0.7, Interpolate(0.5, 0.8, 0.7, 1.0))
+}
@@ -0,0 +1,335 @@
+package dbustest
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+const (
+	bluezBusName           = "org.bluez"
+	objectManagerInterface = "org.freedesktop.DBus.ObjectManager"
+	adapter1Interface      = "org.bluez.Adapter1"
+	device1Interface       = "org.bluez.Device1"
+	battery1Interface      = "org.bluez.Battery1"
+	agentManagerInterface  = "org.bluez.AgentManager1"
+	agentManagerPath       = dbus.ObjectPath("/org/bluez")
+)
+
+var errDoesNotExist = dbus.NewError("org.bluez.Error.DoesNotExist", []any{"Does Not Exist"})
+
+// FakeBlueZ is a fake org.bluez service, which exports adapters and devices with their
+// Adapter1, Device1 and Battery1 interfaces via the ObjectManager, as well as the AgentManager1.
+// All properties are writable.
+type FakeBlueZ struct {
+	conn *dbus.Conn
+
+	mu sync.Mutex
+	// objects are the properties of all exported objects, keyed by path and interface
+	objects map[dbus.ObjectPath]*prop.Properties
+	// interfaces are the names of the property interfaces of all exported objects
+	interfaces map[dbus.ObjectPath][]string
+	calls      []string
+	// agents are the capabilities of all registered agents, keyed by path
+	agents       map[dbus.ObjectPath]string
+	defaultAgent dbus.ObjectPath
+}
+
+// StartBlueZ exports a fake BlueZ service without any adapters on the bus with the given address
+func StartBlueZ(t testing.TB, address string) *FakeBlueZ {
+	t.Helper()
+
+	bluez := &FakeBlueZ{
+		conn:       Connect(t, address),
+		objects:    map[dbus.ObjectPath]*prop.Properties{},
+		interfaces: map[dbus.ObjectPath][]string{},
+		agents:     map[dbus.ObjectPath]string{},
+	}
+	err := bluez.conn.Export(fakeObjectManager{bluez}, "/", objectManagerInterface)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = bluez.conn.Export(fakeAgentManager{bluez}, agentManagerPath, agentManagerInterface)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = bluez.conn.RequestName(bluezBusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bluez
+}
+
+// AddAdapter exports an adapter with the given Adapter1 properties, f.ex. "Address" and "Powered"
+func (b *FakeBlueZ) AddAdapter(t testing.TB, path dbus.ObjectPath, properties map[string]any) {
+	t.Helper()
+
+	err := b.conn.Export(fakeAdapter{b, path}, path, adapter1Interface)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.addObject(t, path, map[string]map[string]any{
+		adapter1Interface: withDefaults(properties, map[string]any{
+			"Powered":     false,
+			"Discovering": false,
+		}),
+	})
+}
+
+// AddDevice exports a device with the given Device1 properties, f.ex. "Address", "Name" and "Adapter".
+// If battery is not nil, the Battery1 interface is exported with the given properties, f.ex. "Percentage".
+func (b *FakeBlueZ) AddDevice(t testing.TB, path dbus.ObjectPath, properties map[string]any, battery map[string]any) {
+	t.Helper()
+
+	err := b.conn.Export(fakeDevice{b, path}, path, device1Interface)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interfaces := map[string]map[string]any{
+		device1Interface: withDefaults(properties, map[string]any{
+			"Alias":     "",
+			"Paired":    false,
+			"Bonded":    false,
+			"Trusted":   false,
+			"Blocked":   false,
+			"Connected": false,
+		}),
+	}
+	if battery != nil {
+		interfaces[battery1Interface] = battery
+	}
+	b.addObject(t, path, interfaces)
+}
+
+func (b *FakeBlueZ) addObject(t testing.TB, path dbus.ObjectPath, interfaces map[string]map[string]any) {
+	t.Helper()
+
+	propMap := prop.Map{}
+	names := make([]string, 0, len(interfaces))
+	for iface, values := range interfaces {
+		names = append(names, iface)
+		propMap[iface] = map[string]*prop.Prop{}
+		for name, value := range values {
+			propMap[iface][name] = &prop.Prop{Value: value, Writable: true, Emit: prop.EmitTrue}
+		}
+	}
+	properties, err := prop.Export(b.conn, path, propMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.mu.Lock()
+	b.objects[path] = properties
+	b.interfaces[path] = names
+	b.mu.Unlock()
+
+	_ = b.conn.Emit("/", objectManagerInterface+".InterfacesAdded", path, b.managedObject(path))
+}
+
+// Property returns the current value of a property of the object with the given path, nil if it does not exist
+func (b *FakeBlueZ) Property(path dbus.ObjectPath, iface string, name string) any {
+	b.mu.Lock()
+	properties, ok := b.objects[path]
+	b.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	value, err := properties.Get(iface, name)
+	if err != nil {
+		return nil
+	}
+	return value.Value()
+}
+
+// Set changes a property of the object with the given path, emitting PropertiesChanged
+func (b *FakeBlueZ) Set(t testing.TB, path dbus.ObjectPath, iface string, name string, value any) {
+	t.Helper()
+
+	if err := b.set(path, iface, name, value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// DefaultAgent returns the path and capability of the default agent, an empty path if there is none
+func (b *FakeBlueZ) DefaultAgent() (dbus.ObjectPath, string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.defaultAgent, b.agents[b.defaultAgent]
+}
+
+// Exists reports whether an object with the given path is exported
+func (b *FakeBlueZ) Exists(path dbus.ObjectPath) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.objects[path]
+	return ok
+}
+
+// Calls returns all method calls received by adapters, devices and the agent manager so far, f.ex. "/org/bluez/hci0/dev_00_1D_43_6D_03_1A.Connect"
+func (b *FakeBlueZ) Calls() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string{}, b.calls...)
+}
+
+func (b *FakeBlueZ) recordCall(path dbus.ObjectPath, method string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls = append(b.calls, string(path)+"."+method)
+}
+
+// set changes a property of an object, emitting PropertiesChanged
+func (b *FakeBlueZ) set(path dbus.ObjectPath, iface string, name string, value any) *dbus.Error {
+	b.mu.Lock()
+	properties, ok := b.objects[path]
+	b.mu.Unlock()
+	if !ok {
+		return errDoesNotExist
+	}
+	return properties.Set(iface, name, dbus.MakeVariant(value))
+}
+
+// withDefaults returns the given properties, completed by the given defaults
+func withDefaults(properties map[string]any, defaults map[string]any) map[string]any {
+	result := make(map[string]any, len(properties)+len(defaults))
+	for name, value := range defaults {
+		result[name] = value
+	}
+	for name, value := range properties {
+		result[name] = value
+	}
+	return result
+}
+
+func (b *FakeBlueZ) managedObject(path dbus.ObjectPath) map[string]map[string]dbus.Variant {
+	b.mu.Lock()
+	properties := b.objects[path]
+	names := b.interfaces[path]
+	b.mu.Unlock()
+
+	result := map[string]map[string]dbus.Variant{}
+	for _, iface := range names {
+		values, err := properties.GetAll(iface)
+		if err == nil {
+			result[iface] = values
+		}
+	}
+	return result
+}
+
+func (b *FakeBlueZ) remove(path dbus.ObjectPath) *dbus.Error {
+	b.mu.Lock()
+	names, ok := b.interfaces[path]
+	delete(b.objects, path)
+	delete(b.interfaces, path)
+	b.mu.Unlock()
+	if !ok {
+		return errDoesNotExist
+	}
+
+	_ = b.conn.Export(nil, path, device1Interface)
+	_ = b.conn.Export(nil, path, "org.freedesktop.DBus.Properties")
+	_ = b.conn.Emit("/", objectManagerInterface+".InterfacesRemoved", path, names)
+	return nil
+}
+
+type fakeObjectManager struct {
+	bluez *FakeBlueZ
+}
+
+func (m fakeObjectManager) GetManagedObjects() (map[dbus.ObjectPath]map[string]map[string]dbus.Variant, *dbus.Error) {
+	m.bluez.mu.Lock()
+	paths := make([]dbus.ObjectPath, 0, len(m.bluez.objects))
+	for path := range m.bluez.objects {
+		paths = append(paths, path)
+	}
+	m.bluez.mu.Unlock()
+
+	result := map[dbus.ObjectPath]map[string]map[string]dbus.Variant{}
+	for _, path := range paths {
+		result[path] = m.bluez.managedObject(path)
+	}
+	return result, nil
+}
+
+type fakeAdapter struct {
+	bluez *FakeBlueZ
+	path  dbus.ObjectPath
+}
+
+func (a fakeAdapter) StartDiscovery() *dbus.Error {
+	a.bluez.recordCall(a.path, "StartDiscovery")
+	return a.bluez.set(a.path, adapter1Interface, "Discovering", true)
+}
+
+func (a fakeAdapter) StopDiscovery() *dbus.Error {
+	a.bluez.recordCall(a.path, "StopDiscovery")
+	return a.bluez.set(a.path, adapter1Interface, "Discovering", false)
+}
+
+func (a fakeAdapter) RemoveDevice(device dbus.ObjectPath) *dbus.Error {
+	a.bluez.recordCall(a.path, "RemoveDevice")
+	return a.bluez.remove(device)
+}
+
+type fakeDevice struct {
+	bluez *FakeBlueZ
+	path  dbus.ObjectPath
+}
+
+func (d fakeDevice) Pair() *dbus.Error {
+	d.bluez.recordCall(d.path, "Pair")
+	if err := d.bluez.set(d.path, device1Interface, "Paired", true); err != nil {
+		return err
+	}
+	return d.bluez.set(d.path, device1Interface, "Bonded", true)
+}
+
+func (d fakeDevice) Connect() *dbus.Error {
+	d.bluez.recordCall(d.path, "Connect")
+	return d.bluez.set(d.path, device1Interface, "Connected", true)
+}
+
+func (d fakeDevice) Disconnect() *dbus.Error {
+	d.bluez.recordCall(d.path, "Disconnect")
+	return d.bluez.set(d.path, device1Interface, "Connected", false)
+}
+
+type fakeAgentManager struct {
+	bluez *FakeBlueZ
+}
+
+func (m fakeAgentManager) RegisterAgent(path dbus.ObjectPath, capability string) *dbus.Error {
+	m.bluez.recordCall(agentManagerPath, "RegisterAgent")
+	m.bluez.mu.Lock()
+	defer m.bluez.mu.Unlock()
+	m.bluez.agents[path] = capability
+	return nil
+}
+
+func (m fakeAgentManager) RequestDefaultAgent(path dbus.ObjectPath) *dbus.Error {
+	m.bluez.recordCall(agentManagerPath, "RequestDefaultAgent")
+	m.bluez.mu.Lock()
+	defer m.bluez.mu.Unlock()
+	if _, ok := m.bluez.agents[path]; !ok {
+		return errDoesNotExist
+	}
+	m.bluez.defaultAgent = path
+	return nil
+}
+
+func (m fakeAgentManager) UnregisterAgent(path dbus.ObjectPath) *dbus.Error {
+	m.bluez.recordCall(agentManagerPath, "UnregisterAgent")
+	m.bluez.mu.Lock()
+	defer m.bluez.mu.Unlock()
+	if _, ok := m.bluez.agents[path]; !ok {
+		return errDoesNotExist
+	}
+	delete(m.bluez.agents, path)
+	if m.bluez.defaultAgent == path {
+		m.bluez.defaultAgent = ""
+	}
+	return nil
+}
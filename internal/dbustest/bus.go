@@ -0,0 +1,107 @@
+// Package dbustest provides a private D-Bus message bus to test D-Bus clients against fake services.
+package dbustest
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+const busConfig = `<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:path=%s</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`
+
+// StartBus starts a private dbus-daemon for the duration of the test and returns its address.
+// The test is skipped if dbus-daemon is not installed.
+func StartBus(t testing.TB) string {
+	t.Helper()
+
+	daemonPath, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not found")
+	}
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "bus.conf")
+	err = os.WriteFile(configPath, []byte(fmt.Sprintf(busConfig, filepath.Join(dir, "bus"))), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	daemon := exec.Command(daemonPath, "--config-file="+configPath, "--nofork", "--print-address")
+	stdout, err := daemon.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := daemon.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = daemon.Process.Kill()
+		_ = daemon.Wait()
+	})
+
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("failed to read bus address: %v", err)
+	}
+	return strings.TrimSpace(address)
+}
+
+// Connect opens a new connection to the bus with the given address, which is closed at the end of the test
+func Connect(t testing.TB, address string) *dbus.Conn {
+	t.Helper()
+
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return conn
+}
+
+// ExportProperties exports the given read-only properties, keyed by interface and property name,
+// on the given object path via org.freedesktop.DBus.Properties
+func ExportProperties(t testing.TB, conn *dbus.Conn, path dbus.ObjectPath, properties map[string]map[string]any) *prop.Properties {
+	t.Helper()
+
+	propMap := prop.Map{}
+	for iface, values := range properties {
+		propMap[iface] = map[string]*prop.Prop{}
+		for name, value := range values {
+			propMap[iface][name] = &prop.Prop{Value: value, Emit: prop.EmitTrue}
+		}
+	}
+	exported, err := prop.Export(conn, path, propMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return exported
+}
+
+// Dialer returns a function which opens a new connection to the bus with the given address on every call.
+// The connections have to be closed by the caller.
+func Dialer(address string) func() (*dbus.Conn, error) {
+	return func() (*dbus.Conn, error) {
+		return dbus.Connect(address)
+	}
+}
@@ -0,0 +1,249 @@
+package gammaramp
+
+// blackbodyColor contains whitepoint values for color temperatures from 1000K to 25100K in 100K steps,
+// taken from the blackbody_color table of redshift (src/colorramp.c), which was provided by Ingo Thies (2013).
+// Values between two entries are interpolated linearly.
+var blackbodyColor = [][3]float64{
+	{1.00000000, 0.18172716, 0.00000000}, // 1000K
+	{1.00000000, 0.25503671, 0.00000000}, // 1100K
+	{1.00000000, 0.30942099, 0.00000000}, // 1200K
+	{1.00000000, 0.35357379, 0.00000000}, // 1300K
+	{1.00000000, 0.39091524, 0.00000000}, // 1400K
+	{1.00000000, 0.42322816, 0.00000000}, // 1500K
+	{1.00000000, 0.45159884, 0.00000000}, // 1600K
+	{1.00000000, 0.47675916, 0.00000000}, // 1700K
+	{1.00000000, 0.49923747, 0.00000000}, // 1800K
+	{1.00000000, 0.51943421, 0.00000000}, // 1900K
+	{1.00000000, 0.54360078, 0.08679949}, // 2000K
+	{1.00000000, 0.56618736, 0.14065513}, // 2100K
+	{1.00000000, 0.58734976, 0.18362641}, // 2200K
+	{1.00000000, 0.60724493, 0.22137978}, // 2300K
+	{1.00000000, 0.62600248, 0.25591950}, // 2400K
+	{1.00000000, 0.64373109, 0.28819679}, // 2500K
+	{1.00000000, 0.66052319, 0.31873863}, // 2600K
+	{1.00000000, 0.67645822, 0.34786758}, // 2700K
+	{1.00000000, 0.69160518, 0.37579588}, // 2800K
+	{1.00000000, 0.70602449, 0.40267128}, // 2900K
+	{1.00000000, 0.71976951, 0.42860152}, // 3000K
+	{1.00000000, 0.73288760, 0.45366838}, // 3100K
+	{1.00000000, 0.74542112, 0.47793608}, // 3200K
+	{1.00000000, 0.75740814, 0.50145662}, // 3300K
+	{1.00000000, 0.76888303, 0.52427322}, // 3400K
+	{1.00000000, 0.77987699, 0.54642268}, // 3500K
+	{1.00000000, 0.79041843, 0.56793692}, // 3600K
+	{1.00000000, 0.80053332, 0.58884417}, // 3700K
+	{1.00000000, 0.81024551, 0.60916971}, // 3800K
+	{1.00000000, 0.81957693, 0.62893653}, // 3900K
+	{1.00000000, 0.82854786, 0.64816570}, // 4000K
+	{1.00000000, 0.83717703, 0.66687674}, // 4100K
+	{1.00000000, 0.84548188, 0.68508786}, // 4200K
+	{1.00000000, 0.85347859, 0.70281616}, // 4300K
+	{1.00000000, 0.86118227, 0.72007777}, // 4400K
+	{1.00000000, 0.86860704, 0.73688797}, // 4500K
+	{1.00000000, 0.87576611, 0.75326132}, // 4600K
+	{1.00000000, 0.88267187, 0.76921169}, // 4700K
+	{1.00000000, 0.88933596, 0.78475236}, // 4800K
+	{1.00000000, 0.89576933, 0.79989606}, // 4900K
+	{1.00000000, 0.90198230, 0.81465502}, // 5000K
+	{1.00000000, 0.90963069, 0.82838210}, // 5100K
+	{1.00000000, 0.91710889, 0.84190889}, // 5200K
+	{1.00000000, 0.92441842, 0.85523742}, // 5300K
+	{1.00000000, 0.93156127, 0.86836903}, // 5400K
+	{1.00000000, 0.93853986, 0.88130458}, // 5500K
+	{1.00000000, 0.94535695, 0.89404470}, // 5600K
+	{1.00000000, 0.95201559, 0.90658983}, // 5700K
+	{1.00000000, 0.95851906, 0.91894041}, // 5800K
+	{1.00000000, 0.96487079, 0.93109690}, // 5900K
+	{1.00000000, 0.97107439, 0.94305985}, // 6000K
+	{1.00000000, 0.97713351, 0.95482993}, // 6100K
+	{1.00000000, 0.98305189, 0.96640795}, // 6200K
+	{1.00000000, 0.98883326, 0.97779486}, // 6300K
+	{1.00000000, 0.99448139, 0.98899179}, // 6400K
+	{1.00000000, 1.00000000, 1.00000000}, // 6500K
+	{0.98947904, 0.99348723, 1.00000000}, // 6600K
+	{0.97940448, 0.98722715, 1.00000000}, // 6700K
+	{0.96975025, 0.98120637, 1.00000000}, // 6800K
+	{0.96049223, 0.97541240, 1.00000000}, // 6900K
+	{0.95160805, 0.96983355, 1.00000000}, // 7000K
+	{0.94303638, 0.96443333, 1.00000000}, // 7100K
+	{0.93480451, 0.95923080, 1.00000000}, // 7200K
+	{0.92689056, 0.95421394, 1.00000000}, // 7300K
+	{0.91927697, 0.94937330, 1.00000000}, // 7400K
+	{0.91194747, 0.94470005, 1.00000000}, // 7500K
+	{0.90488690, 0.94018594, 1.00000000}, // 7600K
+	{0.89808115, 0.93582323, 1.00000000}, // 7700K
+	{0.89151710, 0.93160469, 1.00000000}, // 7800K
+	{0.88518247, 0.92752354, 1.00000000}, // 7900K
+	{0.87906581, 0.92357340, 1.00000000}, // 8000K
+	{0.87315640, 0.91974827, 1.00000000}, // 8100K
+	{0.86744421, 0.91604254, 1.00000000}, // 8200K
+	{0.86191983, 0.91245088, 1.00000000}, // 8300K
+	{0.85657444, 0.90896831, 1.00000000}, // 8400K
+	{0.85139976, 0.90559011, 1.00000000}, // 8500K
+	{0.84638799, 0.90231183, 1.00000000}, // 8600K
+	{0.84153180, 0.89912926, 1.00000000}, // 8700K
+	{0.83682430, 0.89603843, 1.00000000}, // 8800K
+	{0.83225897, 0.89303558, 1.00000000}, // 8900K
+	{0.82782969, 0.89011714, 1.00000000}, // 9000K
+	{0.82353066, 0.88727974, 1.00000000}, // 9100K
+	{0.81935641, 0.88452017, 1.00000000}, // 9200K
+	{0.81530175, 0.88183541, 1.00000000}, // 9300K
+	{0.81136180, 0.87922257, 1.00000000}, // 9400K
+	{0.80753191, 0.87667891, 1.00000000}, // 9500K
+	{0.80380769, 0.87420182, 1.00000000}, // 9600K
+	{0.80018497, 0.87178882, 1.00000000}, // 9700K
+	{0.79665980, 0.86943756, 1.00000000}, // 9800K
+	{0.79322843, 0.86714579, 1.00000000}, // 9900K
+	{0.78988728, 0.86491137, 1.00000000}, // 10000K
+	{0.78663296, 0.86273225, 1.00000000}, // 10100K
+	{0.78346225, 0.86060650, 1.00000000}, // 10200K
+	{0.78037207, 0.85853224, 1.00000000}, // 10300K
+	{0.77735950, 0.85650771, 1.00000000}, // 10400K
+	{0.77442176, 0.85453121, 1.00000000}, // 10500K
+	{0.77155617, 0.85260112, 1.00000000}, // 10600K
+	{0.76876022, 0.85071588, 1.00000000}, // 10700K
+	{0.76603147, 0.84887402, 1.00000000}, // 10800K
+	{0.76336762, 0.84707411, 1.00000000}, // 10900K
+	{0.76076645, 0.84531479, 1.00000000}, // 11000K
+	{0.75822586, 0.84359476, 1.00000000}, // 11100K
+	{0.75574383, 0.84191277, 1.00000000}, // 11200K
+	{0.75331843, 0.84026762, 1.00000000}, // 11300K
+	{0.75094780, 0.83865816, 1.00000000}, // 11400K
+	{0.74863017, 0.83708329, 1.00000000}, // 11500K
+	{0.74636386, 0.83554194, 1.00000000}, // 11600K
+	{0.74414722, 0.83403311, 1.00000000}, // 11700K
+	{0.74197871, 0.83255582, 1.00000000}, // 11800K
+	{0.73985682, 0.83110912, 1.00000000}, // 11900K
+	{0.73778012, 0.82969211, 1.00000000}, // 12000K
+	{0.73574723, 0.82830393, 1.00000000}, // 12100K
+	{0.73375683, 0.82694373, 1.00000000}, // 12200K
+	{0.73180765, 0.82561071, 1.00000000}, // 12300K
+	{0.72989845, 0.82430410, 1.00000000}, // 12400K
+	{0.72802807, 0.82302316, 1.00000000}, // 12500K
+	{0.72619537, 0.82176715, 1.00000000}, // 12600K
+	{0.72439927, 0.82053539, 1.00000000}, // 12700K
+	{0.72263872, 0.81932722, 1.00000000}, // 12800K
+	{0.72091270, 0.81814197, 1.00000000}, // 12900K
+	{0.71922025, 0.81697905, 1.00000000}, // 13000K
+	{0.71756043, 0.81583783, 1.00000000}, // 13100K
+	{0.71593234, 0.81471775, 1.00000000}, // 13200K
+	{0.71433510, 0.81361825, 1.00000000}, // 13300K
+	{0.71276788, 0.81253878, 1.00000000}, // 13400K
+	{0.71122987, 0.81147883, 1.00000000}, // 13500K
+	{0.70972029, 0.81043789, 1.00000000}, // 13600K
+	{0.70823838, 0.80941546, 1.00000000}, // 13700K
+	{0.70678342, 0.80841109, 1.00000000}, // 13800K
+	{0.70535469, 0.80742432, 1.00000000}, // 13900K
+	{0.70395153, 0.80645469, 1.00000000}, // 14000K
+	{0.70257327, 0.80550180, 1.00000000}, // 14100K
+	{0.70121928, 0.80456522, 1.00000000}, // 14200K
+	{0.69988894, 0.80364455, 1.00000000}, // 14300K
+	{0.69858167, 0.80273941, 1.00000000}, // 14400K
+	{0.69729688, 0.80184943, 1.00000000}, // 14500K
+	{0.69603402, 0.80097423, 1.00000000}, // 14600K
+	{0.69479255, 0.80011347, 1.00000000}, // 14700K
+	{0.69357196, 0.79926681, 1.00000000}, // 14800K
+	{0.69237173, 0.79843391, 1.00000000}, // 14900K
+	{0.69119138, 0.79761446, 1.00000000}, // 15000K
+	{0.69003044, 0.79680814, 1.00000000}, // 15100K
+	{0.68888844, 0.79601466, 1.00000000}, // 15200K
+	{0.68776494, 0.79523371, 1.00000000}, // 15300K
+	{0.68665951, 0.79446502, 1.00000000}, // 15400K
+	{0.68557173, 0.79370830, 1.00000000}, // 15500K
+	{0.68450119, 0.79296330, 1.00000000}, // 15600K
+	{0.68344751, 0.79222975, 1.00000000}, // 15700K
+	{0.68241029, 0.79150740, 1.00000000}, // 15800K
+	{0.68138918, 0.79079600, 1.00000000}, // 15900K
+	{0.68038380, 0.79009531, 1.00000000}, // 16000K
+	{0.67939381, 0.78940511, 1.00000000}, // 16100K
+	{0.67841888, 0.78872517, 1.00000000}, // 16200K
+	{0.67745866, 0.78805526, 1.00000000}, // 16300K
+	{0.67651284, 0.78739518, 1.00000000}, // 16400K
+	{0.67558112, 0.78674472, 1.00000000}, // 16500K
+	{0.67466317, 0.78610368, 1.00000000}, // 16600K
+	{0.67375872, 0.78547186, 1.00000000}, // 16700K
+	{0.67286748, 0.78484907, 1.00000000}, // 16800K
+	{0.67198916, 0.78423512, 1.00000000}, // 16900K
+	{0.67112350, 0.78362984, 1.00000000}, // 17000K
+	{0.67027024, 0.78303305, 1.00000000}, // 17100K
+	{0.66942911, 0.78244457, 1.00000000}, // 17200K
+	{0.66859988, 0.78186425, 1.00000000}, // 17300K
+	{0.66778228, 0.78129191, 1.00000000}, // 17400K
+	{0.66697610, 0.78072740, 1.00000000}, // 17500K
+	{0.66618110, 0.78017057, 1.00000000}, // 17600K
+	{0.66539706, 0.77962127, 1.00000000}, // 17700K
+	{0.66462376, 0.77907934, 1.00000000}, // 17800K
+	{0.66386098, 0.77854465, 1.00000000}, // 17900K
+	{0.66310852, 0.77801705, 1.00000000}, // 18000K
+	{0.66236618, 0.77749642, 1.00000000}, // 18100K
+	{0.66163375, 0.77698261, 1.00000000}, // 18200K
+	{0.66091106, 0.77647551, 1.00000000}, // 18300K
+	{0.66019791, 0.77597498, 1.00000000}, // 18400K
+	{0.65949412, 0.77548090, 1.00000000}, // 18500K
+	{0.65879952, 0.77499315, 1.00000000}, // 18600K
+	{0.65811392, 0.77451161, 1.00000000}, // 18700K
+	{0.65743716, 0.77403618, 1.00000000}, // 18800K
+	{0.65676908, 0.77356673, 1.00000000}, // 18900K
+	{0.65610952, 0.77310316, 1.00000000}, // 19000K
+	{0.65545831, 0.77264537, 1.00000000}, // 19100K
+	{0.65481530, 0.77219325, 1.00000000}, // 19200K
+	{0.65418036, 0.77174669, 1.00000000}, // 19300K
+	{0.65355332, 0.77130561, 1.00000000}, // 19400K
+	{0.65293404, 0.77086990, 1.00000000}, // 19500K
+	{0.65232240, 0.77043948, 1.00000000}, // 19600K
+	{0.65171824, 0.77001425, 1.00000000}, // 19700K
+	{0.65112144, 0.76959412, 1.00000000}, // 19800K
+	{0.65053187, 0.76917901, 1.00000000}, // 19900K
+	{0.64994941, 0.76876884, 1.00000000}, // 20000K
+	{0.64937392, 0.76836352, 1.00000000}, // 20100K
+	{0.64880528, 0.76796296, 1.00000000}, // 20200K
+	{0.64824339, 0.76756710, 1.00000000}, // 20300K
+	{0.64768812, 0.76717585, 1.00000000}, // 20400K
+	{0.64713935, 0.76678914, 1.00000000}, // 20500K
+	{0.64659699, 0.76640690, 1.00000000}, // 20600K
+	{0.64606092, 0.76602904, 1.00000000}, // 20700K
+	{0.64553103, 0.76565551, 1.00000000}, // 20800K
+	{0.64500722, 0.76528623, 1.00000000}, // 20900K
+	{0.64448939, 0.76492113, 1.00000000}, // 21000K
+	{0.64397745, 0.76456016, 1.00000000}, // 21100K
+	{0.64347129, 0.76420324, 1.00000000}, // 21200K
+	{0.64297081, 0.76385032, 1.00000000}, // 21300K
+	{0.64247594, 0.76350133, 1.00000000}, // 21400K
+	{0.64198657, 0.76315620, 1.00000000}, // 21500K
+	{0.64150261, 0.76281489, 1.00000000}, // 21600K
+	{0.64102399, 0.76247733, 1.00000000}, // 21700K
+	{0.64055061, 0.76214346, 1.00000000}, // 21800K
+	{0.64008239, 0.76181323, 1.00000000}, // 21900K
+	{0.63961926, 0.76148658, 1.00000000}, // 22000K
+	{0.63916112, 0.76116346, 1.00000000}, // 22100K
+	{0.63870791, 0.76084381, 1.00000000}, // 22200K
+	{0.63825955, 0.76052758, 1.00000000}, // 22300K
+	{0.63781598, 0.76021471, 1.00000000}, // 22400K
+	{0.63737711, 0.75990517, 1.00000000}, // 22500K
+	{0.63694288, 0.75959889, 1.00000000}, // 22600K
+	{0.63651321, 0.75929584, 1.00000000}, // 22700K
+	{0.63608805, 0.75899596, 1.00000000}, // 22800K
+	{0.63566733, 0.75869920, 1.00000000}, // 22900K
+	{0.63525097, 0.75840553, 1.00000000}, // 23000K
+	{0.63483892, 0.75811490, 1.00000000}, // 23100K
+	{0.63443112, 0.75782727, 1.00000000}, // 23200K
+	{0.63402750, 0.75754259, 1.00000000}, // 23300K
+	{0.63362801, 0.75726082, 1.00000000}, // 23400K
+	{0.63323259, 0.75698193, 1.00000000}, // 23500K
+	{0.63284117, 0.75670586, 1.00000000}, // 23600K
+	{0.63245371, 0.75643259, 1.00000000}, // 23700K
+	{0.63207015, 0.75616207, 1.00000000}, // 23800K
+	{0.63169043, 0.75589427, 1.00000000}, // 23900K
+	{0.63131450, 0.75562914, 1.00000000}, // 24000K
+	{0.63094230, 0.75536666, 1.00000000}, // 24100K
+	{0.63057379, 0.75510678, 1.00000000}, // 24200K
+	{0.63020892, 0.75484948, 1.00000000}, // 24300K
+	{0.62984763, 0.75459472, 1.00000000}, // 24400K
+	{0.62948987, 0.75434246, 1.00000000}, // 24500K
+	{0.62913561, 0.75409268, 1.00000000}, // 24600K
+	{0.62878478, 0.75384534, 1.00000000}, // 24700K
+	{0.62843735, 0.75360041, 1.00000000}, // 24800K
+	{0.62809327, 0.75335786, 1.00000000}, // 24900K
+	{0.62775249, 0.75311766, 1.00000000}, // 25000K
+	{0.62741498, 0.75287977, 1.00000000}, // 25100K
+}
@@ -0,0 +1,73 @@
+package gammaramp
+
+import (
+	"math"
+)
+
+const (
+	MinimumColorTemperature = 1000
+	MaximumColorTemperature = 25000
+	NeutralColorTemperature = 6500
+)
+
+// ColorSetting describes the color adjustment applied to a display
+type ColorSetting struct {
+	// Temperature is the color temperature in Kelvin, between 1000 and 25000
+	Temperature int64
+	// Brightness is the brightness multiplier, between 0.1 and 1.0
+	Brightness float64
+	// Gamma is the gamma correction value, between 0.1 and 10.0
+	Gamma float64
+}
+
+// NeutralColorSetting returns a ColorSetting that does not alter the display colors
+func NeutralColorSetting() ColorSetting {
+	return ColorSetting{
+		Temperature: NeutralColorTemperature,
+		Brightness:  1.0,
+		Gamma:       1.0,
+	}
+}
+
+// WhitePoint returns the (interpolated) whitepoint for the given color temperature
+func WhitePoint(temperature int64) [3]float64 {
+	temperature = max(MinimumColorTemperature, min(temperature, MaximumColorTemperature))
+
+	index := int((temperature - MinimumColorTemperature) / 100)
+	alpha := float64(temperature%100) / 100.0
+
+	lower := blackbodyColor[index]
+	upper := blackbodyColor[index+1]
+
+	var result [3]float64
+	for i := range result {
+		result[i] = (1.0-alpha)*lower[i] + alpha*upper[i]
+	}
+	return result
+}
+
+// FillRamps computes gamma ramps of the given size for the given color setting.
+// This is a port of colorramp_fill from redshift.
+func FillRamps(size int, setting ColorSetting) (red []uint16, green []uint16, blue []uint16) {
+	whitePoint := WhitePoint(setting.Temperature)
+
+	gamma := setting.Gamma
+	if gamma <= 0 {
+		gamma = 1.0
+	}
+
+	ramps := [3][]uint16{
+		make([]uint16, size),
+		make([]uint16, size),
+		make([]uint16, size),
+	}
+	for i := 0; i < size; i++ {
+		value := float64(i) / float64(size)
+		for c := range ramps {
+			v := math.Pow(value*setting.Brightness*whitePoint[c], 1.0/gamma)
+			ramps[c][i] = uint16(max(0, min(v*(math.MaxUint16+1), math.MaxUint16)))
+		}
+	}
+
+	return ramps[0], ramps[1], ramps[2]
+}
@@ -0,0 +1,84 @@
+package gammaramp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhitePointNeutral(t *testing.T) {
+	assert.Equal(t, [3]float64{1.0, 1.0, 1.0}, WhitePoint(NeutralColorTemperature))
+}
+
+func TestWhitePointInterpolates(t *testing.T) {
+	// GIVEN
+	lower := WhitePoint(3000)
+	upper := WhitePoint(3100)
+
+	// WHEN
+	result := WhitePoint(3050)
+
+	// THEN
+	for i := range result {
+		assert.InDelta(t, (lower[i]+upper[i])/2, result[i], 1e-9)
+	}
+}
+
+func TestWhitePointClamps(t *testing.T) {
+	assert.Equal(t, WhitePoint(MinimumColorTemperature), WhitePoint(0))
+	assert.Equal(t, WhitePoint(MaximumColorTemperature), WhitePoint(100000))
+}
+
+func TestFillRampsNeutralIsIdentity(t *testing.T) {
+	// WHEN
+	r, g, b := FillRamps(256, NeutralColorSetting())
+
+	// THEN
+	for i := 0; i < 256; i++ {
+		expected := uint16(i * 256)
+		assert.Equal(t, expected, r[i])
+		assert.Equal(t, expected, g[i])
+		assert.Equal(t, expected, b[i])
+	}
+}
+
+func TestFillRampsWarmTemperature(t *testing.T) {
+	// WHEN
+	r, g, b := FillRamps(1024, ColorSetting{
+		Temperature: 3000,
+		Brightness:  0.5,
+		Gamma:       1.0,
+	})
+
+	// THEN
+	last := len(r) - 1
+	assert.Greater(t, r[last], g[last])
+	assert.Greater(t, g[last], b[last])
+	assert.LessOrEqual(t, int(r[last]), math.MaxUint16/2+1)
+}
+
+func TestWhitePointMatchesRedshift(t *testing.T) {
+	tests := []struct {
+		temperature int64
+		expected    [3]float64
+	}{
+		{1000, [3]float64{1.00000000, 0.18172716, 0.00000000}},
+		{2000, [3]float64{1.00000000, 0.54360078, 0.08679949}},
+		{3000, [3]float64{1.00000000, 0.71976951, 0.42860152}},
+		{4500, [3]float64{1.00000000, 0.86860704, 0.73688797}},
+		{6500, [3]float64{1.00000000, 1.00000000, 1.00000000}},
+		{10000, [3]float64{0.78988728, 0.86491137, 1.00000000}},
+		{25000, [3]float64{0.62775249, 0.75311766, 1.00000000}},
+	}
+
+	for _, test := range tests {
+		// WHEN
+		result := WhitePoint(test.temperature)
+
+		// THEN
+		for i := range result {
+			assert.InDelta(t, test.expected[i], result[i], 1e-8, "%dK", test.temperature)
+		}
+	}
+}
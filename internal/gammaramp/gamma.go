@@ -0,0 +1,39 @@
+package gammaramp
+
+import (
+	"errors"
+	"os"
+)
+
+// Backend applies gamma ramps to the outputs of a display server
+type Backend interface {
+	// Name returns a human readable name of the backend
+	Name() string
+	// Outputs returns the names of all outputs whose gamma ramps can be adjusted
+	Outputs() ([]string, error)
+	// SetColor applies the given color setting to the output with the given name
+	SetColor(output string, setting ColorSetting) error
+	// KeepsStateOnExit returns true if applied gamma ramps are kept by the display server
+	// after the backend is closed. If false, the process has to keep running for the
+	// adjustment to stay in effect.
+	KeepsStateOnExit() bool
+	// Close releases all resources held by the backend
+	Close() error
+}
+
+// NewBackend returns a Backend for the currently running display server.
+// Wayland is preferred over X11 if both are available.
+func NewBackend() (Backend, error) {
+	if len(os.Getenv("WAYLAND_DISPLAY")) > 0 {
+		return NewWaylandBackend()
+	}
+	if len(os.Getenv("DISPLAY")) > 0 {
+		return NewRandrBackend()
+	}
+	return nil, errors.New("neither WAYLAND_DISPLAY nor DISPLAY is set, cannot adjust gamma")
+}
+
+// ResetColor restores the default gamma ramps of the given output
+func ResetColor(backend Backend, output string) error {
+	return backend.SetColor(output, NeutralColorSetting())
+}
@@ -0,0 +1,112 @@
+package gammaramp
+
+import (
+	"fmt"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/randr"
+	"github.com/jezek/xgb/xproto"
+)
+
+// RandrBackend adjusts gamma ramps using the CRTC gamma API of the X11 RandR extension
+type RandrBackend struct {
+	conn *xgb.Conn
+	root xproto.Window
+}
+
+func NewRandrBackend() (*RandrBackend, error) {
+	conn, err := xgb.NewConn()
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to X server: %w", err)
+	}
+	err = randr.Init(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("RandR extension not available: %w", err)
+	}
+
+	root := xproto.Setup(conn).DefaultScreen(conn).Root
+	return &RandrBackend{
+		conn: conn,
+		root: root,
+	}, nil
+}
+
+func (b *RandrBackend) Name() string {
+	return "randr"
+}
+
+func (b *RandrBackend) Outputs() ([]string, error) {
+	crtcs, err := b.outputCrtcs()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(crtcs))
+	for _, output := range crtcs {
+		names = append(names, output.name)
+	}
+	return names, nil
+}
+
+func (b *RandrBackend) SetColor(output string, setting ColorSetting) error {
+	crtcs, err := b.outputCrtcs()
+	if err != nil {
+		return err
+	}
+
+	for _, o := range crtcs {
+		if o.name != output {
+			continue
+		}
+		gammaSize, err := randr.GetCrtcGammaSize(b.conn, o.crtc).Reply()
+		if err != nil {
+			return err
+		}
+		if gammaSize.Size <= 1 {
+			return fmt.Errorf("output %s does not support gamma adjustment", output)
+		}
+
+		r, g, bl := FillRamps(int(gammaSize.Size), setting)
+		return randr.SetCrtcGammaChecked(b.conn, o.crtc, gammaSize.Size, r, g, bl).Check()
+	}
+
+	return fmt.Errorf("output %s not found", output)
+}
+
+func (b *RandrBackend) KeepsStateOnExit() bool {
+	return true
+}
+
+func (b *RandrBackend) Close() error {
+	b.conn.Close()
+	return nil
+}
+
+type randrOutput struct {
+	name string
+	crtc randr.Crtc
+}
+
+// outputCrtcs returns all connected outputs that are currently driven by a CRTC
+func (b *RandrBackend) outputCrtcs() ([]randrOutput, error) {
+	resources, err := randr.GetScreenResourcesCurrent(b.conn, b.root).Reply()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []randrOutput
+	for _, output := range resources.Outputs {
+		info, err := randr.GetOutputInfo(b.conn, output, resources.ConfigTimestamp).Reply()
+		if err != nil {
+			return nil, err
+		}
+		if info.Connection != randr.ConnectionConnected || info.Crtc == 0 {
+			continue
+		}
+		result = append(result, randrOutput{
+			name: string(info.Name),
+			crtc: info.Crtc,
+		})
+	}
+	return result, nil
+}
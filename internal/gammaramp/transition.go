@@ -0,0 +1,44 @@
+package gammaramp
+
+import (
+	"math"
+	"time"
+)
+
+// Transition describes a gradual change of a ColorSetting towards a (possibly moving) target
+type Transition struct {
+	// From is the color setting at the start of the transition
+	From ColorSetting
+	// Start is the point in time the transition was started
+	Start time.Time
+	// Duration is the time it takes to fully reach the target
+	Duration time.Duration
+}
+
+// Progress returns the progress of the transition at the given point in time, between 0 and 1
+func (t Transition) Progress(now time.Time) float64 {
+	if t.Duration <= 0 {
+		return 1
+	}
+	progress := float64(now.Sub(t.Start)) / float64(t.Duration)
+	return max(0, min(progress, 1))
+}
+
+// ValueAt returns the color setting at the given point in time, when transitioning towards target
+func (t Transition) ValueAt(target ColorSetting, now time.Time) ColorSetting {
+	return Interpolate(t.From, target, t.Progress(now))
+}
+
+// IsDone returns true if the transition has fully reached its target at the given point in time
+func (t Transition) IsDone(now time.Time) bool {
+	return t.Progress(now) >= 1
+}
+
+// Interpolate linearly interpolates between two color settings, progress is expected to be between 0 and 1
+func Interpolate(from ColorSetting, to ColorSetting, progress float64) ColorSetting {
+	return ColorSetting{
+		Temperature: from.Temperature + int64(math.Round(float64(to.Temperature-from.Temperature)*progress)),
+		Brightness:  from.Brightness + (to.Brightness-from.Brightness)*progress,
+		Gamma:       from.Gamma + (to.Gamma-from.Gamma)*progress,
+	}
+}
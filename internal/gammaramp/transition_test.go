@@ -0,0 +1,42 @@
+package gammaramp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransitionValueAt(t *testing.T) {
+	// GIVEN
+	start := time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC)
+	transition := Transition{
+		From:     ColorSetting{Temperature: 6500, Brightness: 1.0, Gamma: 1.0},
+		Start:    start,
+		Duration: time.Hour,
+	}
+	target := ColorSetting{Temperature: 3500, Brightness: 0.5, Gamma: 0.8}
+
+	// WHEN
+	before := transition.ValueAt(target, start.Add(-time.Minute))
+	halfway := transition.ValueAt(target, start.Add(30*time.Minute))
+	after := transition.ValueAt(target, start.Add(2*time.Hour))
+
+	// THEN
+	assert.Equal(t, transition.From, before)
+	assert.Equal(t, int64(5000), halfway.Temperature)
+	assert.InDelta(t, 0.75, halfway.Brightness, 1e-9)
+	assert.InDelta(t, 0.9, halfway.Gamma, 1e-9)
+	assert.Equal(t, target, after)
+	assert.False(t, transition.IsDone(start.Add(30*time.Minute)))
+	assert.True(t, transition.IsDone(start.Add(time.Hour)))
+}
+
+func TestTransitionWithoutDuration(t *testing.T) {
+	transition := Transition{
+		From: NeutralColorSetting(),
+	}
+	target := ColorSetting{Temperature: 3500, Brightness: 0.5, Gamma: 0.8}
+
+	assert.Equal(t, target, transition.ValueAt(target, time.Now()))
+}
@@ -0,0 +1,379 @@
+package gammaramp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Wayland interface names and opcodes used by the WaylandBackend, see
+// https://wayland.freedesktop.org/docs/html/apa.html and
+// https://gitlab.freedesktop.org/wlroots/wlr-protocols/-/blob/master/unstable/wlr-gamma-control-unstable-v1.xml
+const (
+	waylandDisplayID = 1
+
+	wlDisplaySync        = 0
+	wlDisplayGetRegistry = 1
+	wlDisplayEventError  = 0
+
+	wlRegistryBind        = 0
+	wlRegistryEventGlobal = 0
+
+	wlCallbackEventDone = 0
+
+	wlOutputInterface     = "wl_output"
+	wlOutputMaxVersion    = 4
+	wlOutputEventName     = 4
+	gammaManagerInterface = "zwlr_gamma_control_manager_v1"
+
+	gammaManagerGetGammaControl = 0
+
+	gammaControlSetGamma       = 0
+	gammaControlDestroy        = 1
+	gammaControlEventGammaSize = 0
+	gammaControlEventFailed    = 1
+)
+
+// WaylandBackend adjusts gamma ramps using the wlr-gamma-control protocol,
+// which is supported by wlroots based compositors (sway, Hyprland, river, ...).
+//
+// Note: The compositor restores the original gamma ramps as soon as the connection is closed,
+// so the process has to keep running for an adjustment to stay in effect.
+type WaylandBackend struct {
+	conn   *net.UnixConn
+	nextID uint32
+
+	registryID uint32
+	managerID  uint32
+	outputs    []*waylandOutput
+	callbacks  map[uint32]bool
+}
+
+type waylandOutput struct {
+	id         uint32
+	globalName uint32
+	name       string
+
+	gammaControlID uint32
+	gammaSize      uint32
+	failed         bool
+}
+
+func NewWaylandBackend() (*WaylandBackend, error) {
+	socketPath := os.Getenv("WAYLAND_DISPLAY")
+	if len(socketPath) <= 0 {
+		socketPath = "wayland-0"
+	}
+	if !filepath.IsAbs(socketPath) {
+		runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+		if len(runtimeDir) <= 0 {
+			return nil, errors.New("XDG_RUNTIME_DIR is not set")
+		}
+		socketPath = filepath.Join(runtimeDir, socketPath)
+	}
+
+	conn, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: socketPath, Net: "unix"})
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to wayland compositor: %w", err)
+	}
+
+	b := &WaylandBackend{
+		conn:      conn,
+		nextID:    waylandDisplayID + 1,
+		callbacks: map[uint32]bool{},
+	}
+
+	b.registryID = b.newID()
+	err = b.send(waylandDisplayID, wlDisplayGetRegistry, newMessage().uint32(b.registryID), -1)
+	if err != nil {
+		_ = b.Close()
+		return nil, err
+	}
+	// first roundtrip binds all globals, the second one receives the output properties
+	for i := 0; i < 2; i++ {
+		err = b.roundtrip()
+		if err != nil {
+			_ = b.Close()
+			return nil, err
+		}
+	}
+
+	if b.managerID == 0 {
+		_ = b.Close()
+		return nil, fmt.Errorf("compositor does not support %s", gammaManagerInterface)
+	}
+
+	return b, nil
+}
+
+func (b *WaylandBackend) Name() string {
+	return "wlr-gamma-control"
+}
+
+func (b *WaylandBackend) Outputs() ([]string, error) {
+	names := make([]string, 0, len(b.outputs))
+	for _, output := range b.outputs {
+		names = append(names, output.name)
+	}
+	return names, nil
+}
+
+func (b *WaylandBackend) SetColor(output string, setting ColorSetting) error {
+	var target *waylandOutput
+	for _, o := range b.outputs {
+		if o.name == output {
+			target = o
+			break
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("output %s not found", output)
+	}
+
+	if target.gammaControlID == 0 {
+		target.gammaControlID = b.newID()
+		err := b.send(b.managerID, gammaManagerGetGammaControl, newMessage().uint32(target.gammaControlID).uint32(target.id), -1)
+		if err != nil {
+			return err
+		}
+		err = b.roundtrip()
+		if err != nil {
+			return err
+		}
+	}
+	if target.failed {
+		return fmt.Errorf("gamma control for output %s failed, is another application adjusting the gamma ramps?", output)
+	}
+	if target.gammaSize <= 1 {
+		return fmt.Errorf("output %s does not support gamma adjustment", output)
+	}
+
+	r, g, bl := FillRamps(int(target.gammaSize), setting)
+	rampFile, err := createRampFile(r, g, bl)
+	if err != nil {
+		return err
+	}
+	defer rampFile.Close()
+
+	err = b.send(target.gammaControlID, gammaControlSetGamma, newMessage(), int(rampFile.Fd()))
+	if err != nil {
+		return err
+	}
+	err = b.roundtrip()
+	if err != nil {
+		return err
+	}
+	if target.failed {
+		return fmt.Errorf("could not set gamma ramps of output %s", output)
+	}
+	return nil
+}
+
+func (b *WaylandBackend) KeepsStateOnExit() bool {
+	return false
+}
+
+func (b *WaylandBackend) Close() error {
+	for _, output := range b.outputs {
+		if output.gammaControlID != 0 && !output.failed {
+			_ = b.send(output.gammaControlID, gammaControlDestroy, newMessage(), -1)
+		}
+	}
+	return b.conn.Close()
+}
+
+func (b *WaylandBackend) newID() uint32 {
+	id := b.nextID
+	b.nextID++
+	return id
+}
+
+// roundtrip sends a wl_display.sync request and dispatches all events until the
+// compositor acknowledges it
+func (b *WaylandBackend) roundtrip() error {
+	callbackID := b.newID()
+	err := b.send(waylandDisplayID, wlDisplaySync, newMessage().uint32(callbackID), -1)
+	if err != nil {
+		return err
+	}
+	b.callbacks[callbackID] = false
+
+	for !b.callbacks[callbackID] {
+		err = b.dispatch()
+		if err != nil {
+			return err
+		}
+	}
+	delete(b.callbacks, callbackID)
+	return nil
+}
+
+// dispatch reads and handles a single event
+func (b *WaylandBackend) dispatch() error {
+	header := make([]byte, 8)
+	_, err := io.ReadFull(b.conn, header)
+	if err != nil {
+		return err
+	}
+	objectID := binary.NativeEndian.Uint32(header[0:4])
+	sizeAndOpcode := binary.NativeEndian.Uint32(header[4:8])
+	size := sizeAndOpcode >> 16
+	opcode := sizeAndOpcode & 0xffff
+	if size < 8 {
+		return fmt.Errorf("invalid wayland message size %d", size)
+	}
+
+	payload := make([]byte, size-8)
+	_, err = io.ReadFull(b.conn, payload)
+	if err != nil {
+		return err
+	}
+	args := &messageReader{data: payload}
+
+	switch {
+	case objectID == waylandDisplayID && opcode == wlDisplayEventError:
+		object := args.uint32()
+		code := args.uint32()
+		message := args.string()
+		return fmt.Errorf("wayland error on object %d (code %d): %s", object, code, message)
+	case objectID == b.registryID && opcode == wlRegistryEventGlobal:
+		return b.handleGlobal(args.uint32(), args.string(), args.uint32())
+	}
+
+	if _, ok := b.callbacks[objectID]; ok && opcode == wlCallbackEventDone {
+		b.callbacks[objectID] = true
+		return nil
+	}
+
+	for _, output := range b.outputs {
+		switch {
+		case objectID == output.id && opcode == wlOutputEventName:
+			output.name = args.string()
+		case objectID == output.gammaControlID && opcode == gammaControlEventGammaSize:
+			output.gammaSize = args.uint32()
+		case objectID == output.gammaControlID && opcode == gammaControlEventFailed:
+			output.failed = true
+		}
+	}
+
+	return nil
+}
+
+func (b *WaylandBackend) handleGlobal(name uint32, iface string, version uint32) error {
+	switch iface {
+	case wlOutputInterface:
+		output := &waylandOutput{
+			id:         b.newID(),
+			globalName: name,
+			// wl_output.name is only available since version 4
+			name: fmt.Sprintf("output-%d", name),
+		}
+		b.outputs = append(b.outputs, output)
+		return b.bind(name, iface, min(version, wlOutputMaxVersion), output.id)
+	case gammaManagerInterface:
+		b.managerID = b.newID()
+		return b.bind(name, iface, 1, b.managerID)
+	}
+	return nil
+}
+
+func (b *WaylandBackend) bind(name uint32, iface string, version uint32, id uint32) error {
+	return b.send(b.registryID, wlRegistryBind, newMessage().uint32(name).string(iface).uint32(version).uint32(id), -1)
+}
+
+// send writes a request to the compositor, optionally passing a file descriptor (-1 for none)
+func (b *WaylandBackend) send(objectID uint32, opcode uint32, message *messageWriter, fd int) error {
+	size := uint32(8 + len(message.data))
+	data := make([]byte, 8, size)
+	binary.NativeEndian.PutUint32(data[0:4], objectID)
+	binary.NativeEndian.PutUint32(data[4:8], size<<16|opcode)
+	data = append(data, message.data...)
+
+	var oob []byte
+	if fd >= 0 {
+		oob = syscall.UnixRights(fd)
+	}
+	_, _, err := b.conn.WriteMsgUnix(data, oob, nil)
+	return err
+}
+
+// createRampFile writes the given gamma ramps into an anonymous file, as expected by zwlr_gamma_control_v1.set_gamma
+func createRampFile(red []uint16, green []uint16, blue []uint16) (*os.File, error) {
+	fd, err := unix.MemfdCreate("system-control-gamma", unix.MFD_CLOEXEC)
+	if err != nil {
+		return nil, err
+	}
+	file := os.NewFile(uintptr(fd), "system-control-gamma")
+
+	data := make([]byte, 0, 2*(len(red)+len(green)+len(blue)))
+	for _, ramp := range [][]uint16{red, green, blue} {
+		for _, value := range ramp {
+			data = binary.NativeEndian.AppendUint16(data, value)
+		}
+	}
+	_, err = file.Write(data)
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+type messageWriter struct {
+	data []byte
+}
+
+func newMessage() *messageWriter {
+	return &messageWriter{}
+}
+
+func (m *messageWriter) uint32(value uint32) *messageWriter {
+	m.data = binary.NativeEndian.AppendUint32(m.data, value)
+	return m
+}
+
+func (m *messageWriter) string(value string) *messageWriter {
+	length := len(value) + 1
+	m.uint32(uint32(length))
+	m.data = append(m.data, value...)
+	m.data = append(m.data, 0)
+	for length%4 != 0 {
+		m.data = append(m.data, 0)
+		length++
+	}
+	return m
+}
+
+type messageReader struct {
+	data   []byte
+	offset int
+}
+
+func (m *messageReader) uint32() uint32 {
+	if m.offset+4 > len(m.data) {
+		return 0
+	}
+	value := binary.NativeEndian.Uint32(m.data[m.offset:])
+	m.offset += 4
+	return value
+}
+
+func (m *messageReader) string() string {
+	length := int(m.uint32())
+	if length <= 0 || m.offset+length > len(m.data) {
+		return ""
+	}
+	value := string(m.data[m.offset : m.offset+length-1])
+	m.offset += (length + 3) &^ 3
+	return value
+}
@@ -0,0 +1,157 @@
+package gammaramp
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fakeGammaSize = 16
+
+// fakeCompositor implements just enough of a wayland compositor to exercise the WaylandBackend
+type fakeCompositor struct {
+	conn *net.UnixConn
+
+	registryID     uint32
+	outputID       uint32
+	managerID      uint32
+	gammaControlID uint32
+
+	ramps chan []uint16
+}
+
+func (c *fakeCompositor) send(objectID uint32, opcode uint32, message *messageWriter) {
+	size := uint32(8 + len(message.data))
+	data := binary.NativeEndian.AppendUint32(nil, objectID)
+	data = binary.NativeEndian.AppendUint32(data, size<<16|opcode)
+	data = append(data, message.data...)
+	_, _ = c.conn.Write(data)
+}
+
+func (c *fakeCompositor) serve() {
+	defer c.conn.Close()
+	for {
+		header := make([]byte, 8)
+		oob := make([]byte, syscall.CmsgSpace(4))
+		n, oobn, _, _, err := c.conn.ReadMsgUnix(header, oob)
+		if err != nil || n < 8 {
+			return
+		}
+		objectID := binary.NativeEndian.Uint32(header[0:4])
+		sizeAndOpcode := binary.NativeEndian.Uint32(header[4:8])
+		payload := make([]byte, (sizeAndOpcode>>16)-8)
+		_, err = io.ReadFull(c.conn, payload)
+		if err != nil {
+			return
+		}
+		args := &messageReader{data: payload}
+		opcode := sizeAndOpcode & 0xffff
+
+		switch {
+		case objectID == waylandDisplayID && opcode == wlDisplayGetRegistry:
+			c.registryID = args.uint32()
+			c.send(c.registryID, wlRegistryEventGlobal, newMessage().uint32(1).string(wlOutputInterface).uint32(4))
+			c.send(c.registryID, wlRegistryEventGlobal, newMessage().uint32(2).string(gammaManagerInterface).uint32(1))
+		case objectID == waylandDisplayID && opcode == wlDisplaySync:
+			c.send(args.uint32(), wlCallbackEventDone, newMessage().uint32(0))
+		case objectID == c.registryID && opcode == wlRegistryBind:
+			name := args.uint32()
+			_ = args.string()
+			_ = args.uint32()
+			id := args.uint32()
+			if name == 1 {
+				c.outputID = id
+				c.send(id, wlOutputEventName, newMessage().string("DP-1"))
+			} else {
+				c.managerID = id
+			}
+		case objectID == c.managerID && opcode == gammaManagerGetGammaControl:
+			c.gammaControlID = args.uint32()
+			c.send(c.gammaControlID, gammaControlEventGammaSize, newMessage().uint32(fakeGammaSize))
+		case objectID == c.gammaControlID && opcode == gammaControlSetGamma:
+			messages, _ := syscall.ParseSocketControlMessage(oob[:oobn])
+			fds, _ := syscall.ParseUnixRights(&messages[0])
+			file := os.NewFile(uintptr(fds[0]), "ramps")
+			data, _ := io.ReadAll(file)
+			_ = file.Close()
+			ramps := make([]uint16, len(data)/2)
+			for i := range ramps {
+				ramps[i] = binary.NativeEndian.Uint16(data[i*2:])
+			}
+			c.ramps <- ramps
+		}
+	}
+}
+
+func startFakeCompositor(t *testing.T) *fakeCompositor {
+	runtimeDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	t.Setenv("WAYLAND_DISPLAY", "wayland-test")
+
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: filepath.Join(runtimeDir, "wayland-test"), Net: "unix"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	compositor := &fakeCompositor{ramps: make(chan []uint16, 1)}
+	go func() {
+		conn, err := listener.AcceptUnix()
+		if err != nil {
+			return
+		}
+		compositor.conn = conn
+		compositor.serve()
+	}()
+	return compositor
+}
+
+func TestWaylandBackendOutputs(t *testing.T) {
+	// GIVEN
+	startFakeCompositor(t)
+
+	// WHEN
+	backend, err := NewBackend()
+	require.NoError(t, err)
+	defer backend.Close()
+	outputs, err := backend.Outputs()
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"DP-1"}, outputs)
+	assert.False(t, backend.KeepsStateOnExit())
+}
+
+func TestWaylandBackendSetColor(t *testing.T) {
+	// GIVEN
+	compositor := startFakeCompositor(t)
+	backend, err := NewWaylandBackend()
+	require.NoError(t, err)
+	defer backend.Close()
+	setting := ColorSetting{Temperature: 4000, Brightness: 0.8, Gamma: 1.0}
+
+	// WHEN
+	err = backend.SetColor("DP-1", setting)
+
+	// THEN
+	assert.NoError(t, err)
+	r, g, b := FillRamps(fakeGammaSize, setting)
+	expected := append(append(r, g...), b...)
+	assert.Equal(t, expected, <-compositor.ramps)
+}
+
+func TestWaylandBackendSetColorUnknownOutput(t *testing.T) {
+	startFakeCompositor(t)
+	backend, err := NewWaylandBackend()
+	require.NoError(t, err)
+	defer backend.Close()
+
+	err = backend.SetColor("HDMI-1", NeutralColorSetting())
+
+	assert.Error(t, err)
+}
@@ -12,6 +12,9 @@ import (
 
 var (
 	BaseDir = path.Join(configuration.BaseDir, "persistence")
+	// SystemDir contains the state shared by all users, f.ex. written by commands which require root privileges
+	// and read by commands run as a normal user
+	SystemDir = "/var/lib/system-control"
 )
 
 func init() {
@@ -41,6 +44,17 @@ func ReadFloat(key string) (float64, error) {
 	return util.ReadFloatFromFile(file)
 }
 
+// SaveString persists the given value, the file is only readable by the current user
+func SaveString(key string, value string) error {
+	file := path.Join(BaseDir, key+".sav")
+	return os.WriteFile(file, []byte(value), 0600)
+}
+
+func ReadString(key string) (string, error) {
+	file := path.Join(BaseDir, key+".sav")
+	return util.ReadTextFromFile(file)
+}
+
 func SaveStruct(key string, value interface{}) error {
 	file := path.Join(BaseDir, key+".sav")
 	jsonString, _ := json.MarshalIndent(value, "", "  ")
@@ -49,6 +63,30 @@ func SaveStruct(key string, value interface{}) error {
 
 func ReadStruct(key string, target interface{}) error {
 	file := path.Join(BaseDir, key+".sav")
+	return readStruct(file, target)
+}
+
+// SaveSystemStruct persists the given value in SystemDir, readable by all users
+func SaveSystemStruct(key string, value interface{}) error {
+	err := os.MkdirAll(SystemDir, 0755)
+	if err != nil {
+		return err
+	}
+	file := path.Join(SystemDir, key+".sav")
+	jsonString, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, jsonString, 0644)
+}
+
+// ReadSystemStruct reads a value persisted using SaveSystemStruct
+func ReadSystemStruct(key string, target interface{}) error {
+	file := path.Join(SystemDir, key+".sav")
+	return readStruct(file, target)
+}
+
+func readStruct(file string, target interface{}) error {
 	content, err := os.ReadFile(file)
 	if err != nil {
 		return err
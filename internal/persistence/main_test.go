@@ -46,3 +46,38 @@ func TestReadStruct(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, test, value)
 }
+
+func TestReadString(t *testing.T) {
+	// GIVEN
+	BaseDir = "./"
+	key := "key"
+	_ = SaveString(key, "hello")
+
+	// WHEN
+	value, err := ReadString(key)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", value)
+}
+
+func TestReadSystemStruct(t *testing.T) {
+	// GIVEN
+	SystemDir = t.TempDir() + "/system-control"
+	key := "key"
+	test := dummy{
+		Text:   "hello",
+		Number: 42,
+	}
+	err := SaveSystemStruct(key, test)
+	assert.NoError(t, err)
+
+	var value = dummy{}
+
+	// WHEN
+	err = ReadSystemStruct(key, &value)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, test, value)
+}
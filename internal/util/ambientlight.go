@@ -0,0 +1,107 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	IioDevicesPath = "/sys/bus/iio/devices"
+
+	illuminanceInput  = "in_illuminance_input"
+	illuminanceRaw    = "in_illuminance_raw"
+	illuminanceScale  = "in_illuminance_scale"
+	illuminanceOffset = "in_illuminance_offset"
+)
+
+// AmbientLightSensor represents an IIO ambient light sensor
+type AmbientLightSensor struct {
+	// Name is the name of the IIO device, f.ex. "iio:device0"
+	Name string
+	// Label is the name reported by the driver, f.ex. "als"
+	Label string
+
+	path string
+}
+
+func NewAmbientLightSensor(name string) AmbientLightSensor {
+	path := filepath.Join(IioDevicesPath, name)
+	label, _ := ReadTextFromFile(filepath.Join(path, "name"))
+	return AmbientLightSensor{
+		Name:  name,
+		Label: strings.TrimSpace(label),
+		path:  path,
+	}
+}
+
+// GetAmbientLightSensors returns all IIO devices that expose an illuminance channel
+func GetAmbientLightSensors() ([]AmbientLightSensor, error) {
+	files, err := os.ReadDir(IioDevicesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var sensors []AmbientLightSensor
+	for _, file := range files {
+		sensor := NewAmbientLightSensor(file.Name())
+		if sensor.hasProperty(illuminanceInput) || sensor.hasProperty(illuminanceRaw) {
+			sensors = append(sensors, sensor)
+		}
+	}
+
+	return sensors, nil
+}
+
+// FindAmbientLightSensor returns the sensor with the given device name or label.
+// If name is empty, the first found sensor is returned.
+func FindAmbientLightSensor(name string) (AmbientLightSensor, error) {
+	sensors, err := GetAmbientLightSensors()
+	if err != nil {
+		return AmbientLightSensor{}, err
+	}
+	if len(sensors) == 0 {
+		return AmbientLightSensor{}, errors.New("no ambient light sensors found")
+	}
+	if len(name) <= 0 {
+		return sensors[0], nil
+	}
+	for _, sensor := range sensors {
+		if sensor.Name == name || EqualsIgnoreCase(sensor.Label, name) {
+			return sensor, nil
+		}
+	}
+	return AmbientLightSensor{}, fmt.Errorf("ambient light sensor %s not found", name)
+}
+
+// GetIlluminance returns the current ambient light level in lux
+func (s AmbientLightSensor) GetIlluminance() (float64, error) {
+	if s.hasProperty(illuminanceInput) {
+		return ReadFloatFromFile(filepath.Join(s.path, illuminanceInput))
+	}
+
+	raw, err := ReadFloatFromFile(filepath.Join(s.path, illuminanceRaw))
+	if err != nil {
+		return -1, err
+	}
+
+	// scale and offset are optional, see
+	// https://www.kernel.org/doc/Documentation/ABI/testing/sysfs-bus-iio
+	scale, err := ReadFloatFromFile(filepath.Join(s.path, illuminanceScale))
+	if err != nil {
+		scale = 1.0
+	}
+	offset, err := ReadFloatFromFile(filepath.Join(s.path, illuminanceOffset))
+	if err != nil {
+		offset = 0
+	}
+
+	return (raw + offset) * scale, nil
+}
+
+func (s AmbientLightSensor) hasProperty(property string) bool {
+	_, err := os.Stat(filepath.Join(s.path, property))
+	return err == nil
+}
@@ -0,0 +1,102 @@
+package util
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// BrightnessCurvePoint maps an ambient light level (in lux) to a backlight brightness (in percent)
+type BrightnessCurvePoint struct {
+	Lux        float64
+	Brightness float64
+}
+
+// BrightnessCurve is a list of points that describe the desired backlight brightness
+// for a given ambient light level.
+type BrightnessCurve []BrightnessCurvePoint
+
+// Evaluate returns the brightness (in percent) for the given ambient light level.
+// Values between two points are interpolated linearly on a logarithmic lux scale, since
+// the perceived brightness of the environment is roughly logarithmic as well.
+// Values outside the range of the curve are clamped to the first or last point.
+func (curve BrightnessCurve) Evaluate(lux float64) float64 {
+	if len(curve) == 0 {
+		return 100
+	}
+
+	points := slices.Clone(curve)
+	slices.SortFunc(points, func(a, b BrightnessCurvePoint) int {
+		return cmp.Compare(a.Lux, b.Lux)
+	})
+
+	if lux <= points[0].Lux {
+		return points[0].Brightness
+	}
+	last := points[len(points)-1]
+	if lux >= last.Lux {
+		return last.Brightness
+	}
+
+	for i := 1; i < len(points); i++ {
+		lower := points[i-1]
+		upper := points[i]
+		if lux > upper.Lux {
+			continue
+		}
+		lowerLog := math.Log10(lower.Lux + 1)
+		upperLog := math.Log10(upper.Lux + 1)
+		if upperLog == lowerLog {
+			return upper.Brightness
+		}
+		ratio := (math.Log10(lux+1) - lowerLog) / (upperLog - lowerLog)
+		return lower.Brightness + (upper.Brightness-lower.Brightness)*ratio
+	}
+
+	return last.Brightness
+}
+
+// AmbientBacklightController computes the target backlight brightness from ambient light sensor samples.
+type AmbientBacklightController struct {
+	// Curve maps the (smoothed) ambient light level to a brightness value
+	Curve BrightnessCurve
+	// Hysteresis is the minimum change (in percentage points) of the target brightness
+	// required before a new brightness is applied
+	Hysteresis float64
+	// Smoothing is the weight (between 0 and 1) of a new sample in the exponential moving average
+	// of the ambient light level. 1.0 disables smoothing.
+	Smoothing float64
+
+	smoothedLux   float64
+	lastApplied   float64
+	hasSample     bool
+	hasLastTarget bool
+}
+
+// Update feeds a new ambient light sample into the controller and returns the resulting
+// target brightness (in percent). bias is added to the value computed from the curve.
+// The returned bool is true if the target differs enough from the previously applied value
+// to be applied to the backlight.
+func (c *AmbientBacklightController) Update(lux float64, bias float64) (float64, bool) {
+	if !c.hasSample {
+		c.smoothedLux = lux
+		c.hasSample = true
+	} else {
+		alpha := Clamp(c.Smoothing, 0.0, 1.0)
+		c.smoothedLux = alpha*lux + (1-alpha)*c.smoothedLux
+	}
+
+	target := Clamp(c.Curve.Evaluate(c.smoothedLux)+bias, 0.0, 100.0)
+	if c.hasLastTarget && math.Abs(target-c.lastApplied) < c.Hysteresis {
+		return c.lastApplied, false
+	}
+
+	c.lastApplied = target
+	c.hasLastTarget = true
+	return target, true
+}
+
+// SmoothedIlluminance returns the current smoothed ambient light level in lux
+func (c *AmbientBacklightController) SmoothedIlluminance() float64 {
+	return c.smoothedLux
+}
@@ -0,0 +1,73 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testCurve = BrightnessCurve{
+	{Lux: 1000, Brightness: 80},
+	{Lux: 0, Brightness: 10},
+	{Lux: 9, Brightness: 40},
+}
+
+func TestBrightnessCurveEvaluate(t *testing.T) {
+	assert.Equal(t, 10.0, testCurve.Evaluate(0))
+	assert.Equal(t, 40.0, testCurve.Evaluate(9))
+	assert.Equal(t, 80.0, testCurve.Evaluate(5000))
+	// log10(0+1)=0, log10(9+1)=1, so 2.16 lux is roughly half way
+	assert.InDelta(t, 25.0, testCurve.Evaluate(2.1623), 0.01)
+}
+
+func TestBrightnessCurveEvaluateEmpty(t *testing.T) {
+	assert.Equal(t, 100.0, BrightnessCurve{}.Evaluate(42))
+}
+
+func TestAmbientBacklightControllerHysteresis(t *testing.T) {
+	// GIVEN
+	controller := AmbientBacklightController{
+		Curve:      testCurve,
+		Hysteresis: 5,
+		Smoothing:  1.0,
+	}
+
+	// WHEN
+	first, firstChanged := controller.Update(9, 0)
+	second, secondChanged := controller.Update(9, 2)
+	third, thirdChanged := controller.Update(9, 10)
+
+	// THEN
+	assert.True(t, firstChanged)
+	assert.Equal(t, 40.0, first)
+	assert.False(t, secondChanged)
+	assert.Equal(t, 40.0, second)
+	assert.True(t, thirdChanged)
+	assert.Equal(t, 50.0, third)
+}
+
+func TestAmbientBacklightControllerSmoothing(t *testing.T) {
+	// GIVEN
+	controller := AmbientBacklightController{
+		Curve:     testCurve,
+		Smoothing: 0.5,
+	}
+
+	// WHEN
+	controller.Update(100, 0)
+	controller.Update(0, 0)
+
+	// THEN
+	assert.Equal(t, 50.0, controller.SmoothedIlluminance())
+}
+
+func TestAmbientBacklightControllerClampsBias(t *testing.T) {
+	controller := AmbientBacklightController{
+		Curve:     testCurve,
+		Smoothing: 1.0,
+	}
+
+	target, _ := controller.Update(5000, 50)
+
+	assert.Equal(t, 100.0, target)
+}
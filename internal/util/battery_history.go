@@ -0,0 +1,223 @@
+package util
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+)
+
+var batteryHistoryHeader = []string{"time", "serial", "energy_full", "energy_full_design", "cycle_count", "capacity", "degradation"}
+
+// BatteryHistorySample is the health of a battery at a point in time
+type BatteryHistorySample struct {
+	Time   time.Time
+	Serial string
+	// EnergyFull is the energy of the battery in Wh when fully charged
+	EnergyFull float64
+	// EnergyFullDesign is the energy of the battery in Wh when fully charged, as designed
+	EnergyFullDesign float64
+	// CycleCount is the number of charge cycles, -1 if unknown
+	CycleCount int64
+	// Capacity is EnergyFull in percent of EnergyFullDesign
+	Capacity float64
+	// Degradation is the lost capacity in percent
+	Degradation float64
+}
+
+// BatteryHistoryTrend is the change of the battery health over a series of samples
+type BatteryHistoryTrend struct {
+	First BatteryHistorySample
+	Last  BatteryHistorySample
+	// CapacityPerYear is the change of the capacity in percentage points per year, determined by a linear regression
+	CapacityPerYear float64
+	// capacityStart is the capacity of the regression line at the time of the first sample
+	capacityStart float64
+}
+
+// GetHistorySample returns the current health of the battery
+func (battery BatteryInfo) GetHistorySample(now time.Time) (BatteryHistorySample, error) {
+	energyFull, err := battery.GetEnergyFull()
+	if err != nil {
+		return BatteryHistorySample{}, err
+	}
+	energyFullDesign, err := battery.GetEnergyFullDesign()
+	if err != nil {
+		return BatteryHistorySample{}, err
+	}
+	if energyFullDesign <= 0 {
+		return BatteryHistorySample{}, fmt.Errorf("battery %s does not report its design capacity", battery.Name)
+	}
+	cycleCount, err := battery.GetCycleCount()
+	if err != nil {
+		cycleCount = -1
+	}
+
+	capacity := energyFull / energyFullDesign * 100
+	return BatteryHistorySample{
+		Time:             now,
+		Serial:           battery.SerialNumber,
+		EnergyFull:       energyFull,
+		EnergyFullDesign: energyFullDesign,
+		CycleCount:       cycleCount,
+		Capacity:         capacity,
+		Degradation:      100 - capacity,
+	}, nil
+}
+
+// AppendBatteryHistory appends the given sample to the history file at the given path, creating it if necessary
+func AppendBatteryHistory(path string, sample BatteryHistorySample) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(file)
+	if info.Size() == 0 {
+		err = writer.Write(batteryHistoryHeader)
+		if err != nil {
+			return err
+		}
+	}
+	err = writer.Write([]string{
+		sample.Time.Format(time.RFC3339),
+		sample.Serial,
+		strconv.FormatFloat(sample.EnergyFull, 'f', 3, 64),
+		strconv.FormatFloat(sample.EnergyFullDesign, 'f', 3, 64),
+		strconv.FormatInt(sample.CycleCount, 10),
+		strconv.FormatFloat(sample.Capacity, 'f', 2, 64),
+		strconv.FormatFloat(sample.Degradation, 'f', 2, 64),
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadBatteryHistory reads all samples of the history file at the given path, ordered by time
+func ReadBatteryHistory(path string) ([]BatteryHistorySample, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parseBatteryHistory(file)
+}
+
+func parseBatteryHistory(input io.Reader) ([]BatteryHistorySample, error) {
+	reader := csv.NewReader(input)
+	reader.FieldsPerRecord = len(batteryHistoryHeader)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]BatteryHistorySample, 0, len(records))
+	for i, record := range records {
+		if i == 0 && record[0] == batteryHistoryHeader[0] {
+			continue
+		}
+		sample, err := parseBatteryHistoryRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		samples = append(samples, sample)
+	}
+	slices.SortStableFunc(samples, func(a, b BatteryHistorySample) int {
+		return a.Time.Compare(b.Time)
+	})
+	return samples, nil
+}
+
+func parseBatteryHistoryRecord(record []string) (BatteryHistorySample, error) {
+	sample := BatteryHistorySample{Serial: record[1]}
+	var err error
+	if sample.Time, err = time.Parse(time.RFC3339, record[0]); err != nil {
+		return sample, err
+	}
+	if sample.EnergyFull, err = strconv.ParseFloat(record[2], 64); err != nil {
+		return sample, err
+	}
+	if sample.EnergyFullDesign, err = strconv.ParseFloat(record[3], 64); err != nil {
+		return sample, err
+	}
+	if sample.CycleCount, err = strconv.ParseInt(record[4], 10, 64); err != nil {
+		return sample, err
+	}
+	if sample.Capacity, err = strconv.ParseFloat(record[5], 64); err != nil {
+		return sample, err
+	}
+	sample.Degradation, err = strconv.ParseFloat(record[6], 64)
+	return sample, err
+}
+
+// CurrentBatterySamples returns the samples recorded since the battery was last replaced,
+// which is detected by a change of the serial number
+func CurrentBatterySamples(samples []BatteryHistorySample) []BatteryHistorySample {
+	for i := len(samples) - 1; i > 0; i-- {
+		if samples[i-1].Serial != samples[i].Serial {
+			return samples[i:]
+		}
+	}
+	return samples
+}
+
+// GetBatteryHistoryTrend determines the change of the battery health over the given samples
+func GetBatteryHistoryTrend(samples []BatteryHistorySample) (BatteryHistoryTrend, error) {
+	if len(samples) < 2 {
+		return BatteryHistoryTrend{}, errors.New("at least two samples are required to determine a trend")
+	}
+	first := samples[0]
+	last := samples[len(samples)-1]
+	if !last.Time.After(first.Time) {
+		return BatteryHistoryTrend{}, errors.New("all samples were recorded at the same time")
+	}
+
+	// least squares fit of the capacity over time
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range samples {
+		x := sample.Time.Sub(first.Time).Hours() / 24 / 365.25
+		y := sample.Capacity
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	n := float64(len(samples))
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+
+	return BatteryHistoryTrend{
+		First:           first,
+		Last:            last,
+		CapacityPerYear: slope,
+		capacityStart:   (sumY - slope*sumX) / n,
+	}, nil
+}
+
+// ProjectCapacityDate returns the date at which the capacity is projected to fall below the given
+// percentage, or false if the capacity is not decreasing. If it already is below, the time of the last sample is returned.
+func (trend BatteryHistoryTrend) ProjectCapacityDate(threshold float64) (time.Time, bool) {
+	if trend.Last.Capacity < threshold {
+		return trend.Last.Time, true
+	}
+	if trend.CapacityPerYear >= 0 {
+		return time.Time{}, false
+	}
+	years := (threshold - trend.capacityStart) / trend.CapacityPerYear
+	date := trend.First.Time.Add(time.Duration(years * 365.25 * 24 * float64(time.Hour)))
+	if date.Before(trend.Last.Time) {
+		return trend.Last.Time, true
+	}
+	return date, true
+}
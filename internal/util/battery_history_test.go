@@ -0,0 +1,162 @@
+package util
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func historySample(day int, serial string, capacity float64) BatteryHistorySample {
+	return BatteryHistorySample{
+		Time:             time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, day),
+		Serial:           serial,
+		EnergyFull:       capacity / 2,
+		EnergyFullDesign: 50,
+		CycleCount:       int64(day),
+		Capacity:         capacity,
+		Degradation:      100 - capacity,
+	}
+}
+
+func TestAppendAndReadBatteryHistory(t *testing.T) {
+	// GIVEN
+	path := filepath.Join(t.TempDir(), "BAT0_history.csv")
+	samples := []BatteryHistorySample{
+		historySample(0, "1234", 98),
+		historySample(1, "1234", 97.5),
+	}
+
+	// WHEN
+	for _, sample := range samples {
+		assert.NoError(t, AppendBatteryHistory(path, sample))
+	}
+	result, err := ReadBatteryHistory(path)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	for i, sample := range samples {
+		assert.True(t, sample.Time.Equal(result[i].Time))
+		assert.Equal(t, sample.Serial, result[i].Serial)
+		assert.Equal(t, sample.CycleCount, result[i].CycleCount)
+		assert.InDelta(t, sample.EnergyFull, result[i].EnergyFull, 0.001)
+		assert.InDelta(t, sample.Capacity, result[i].Capacity, 0.01)
+	}
+	assert.Equal(t, 1, strings.Count(readTestFile(t, path), "time,serial"))
+}
+
+func TestParseBatteryHistory(t *testing.T) {
+	// GIVEN
+	input := `time,serial,energy_full,energy_full_design,cycle_count,capacity,degradation
+2024-01-02T00:00:00Z,1234,48.000,50.000,-1,96.00,4.00
+2024-01-01T00:00:00Z,1234,49.000,50.000,10,98.00,2.00
+`
+
+	// WHEN
+	result, err := parseBatteryHistory(strings.NewReader(input))
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.EqualValues(t, 10, result[0].CycleCount)
+	assert.EqualValues(t, -1, result[1].CycleCount)
+	assert.Equal(t, 96.0, result[1].Capacity)
+}
+
+func TestParseBatteryHistoryInvalid(t *testing.T) {
+	// GIVEN
+	input := "2024-01-01T00:00:00Z,1234,abc,50.000,10,98.00,2.00\n"
+
+	// WHEN
+	_, err := parseBatteryHistory(strings.NewReader(input))
+
+	// THEN
+	assert.ErrorContains(t, err, "line 1")
+}
+
+func TestCurrentBatterySamples(t *testing.T) {
+	// GIVEN
+	samples := []BatteryHistorySample{
+		historySample(0, "old", 70),
+		historySample(1, "old", 69),
+		historySample(2, "new", 100),
+		historySample(3, "new", 99),
+	}
+
+	// WHEN
+	result := CurrentBatterySamples(samples)
+
+	// THEN
+	assert.Equal(t, samples[2:], result)
+}
+
+func TestGetBatteryHistoryTrend(t *testing.T) {
+	// GIVEN
+	samples := []BatteryHistorySample{
+		historySample(0, "1234", 100),
+		historySample(365, "1234", 95),
+		historySample(730, "1234", 90),
+	}
+
+	// WHEN
+	trend, err := GetBatteryHistoryTrend(samples)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, samples[0], trend.First)
+	assert.Equal(t, samples[2], trend.Last)
+	assert.InDelta(t, -5, trend.CapacityPerYear, 0.01)
+
+	date, ok := trend.ProjectCapacityDate(80)
+	assert.True(t, ok)
+	assert.Equal(t, "2027-12-31", date.Format(time.DateOnly))
+}
+
+func TestGetBatteryHistoryTrendTooFewSamples(t *testing.T) {
+	// GIVEN
+	samples := []BatteryHistorySample{historySample(0, "1234", 100)}
+
+	// WHEN
+	_, err := GetBatteryHistoryTrend(samples)
+
+	// THEN
+	assert.Error(t, err)
+}
+
+func TestProjectCapacityDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		samples   []BatteryHistorySample
+		expectOk  bool
+		expectDay int
+	}{
+		{"not decreasing", []BatteryHistorySample{
+			historySample(0, "1234", 95),
+			historySample(10, "1234", 96),
+		}, false, 0},
+		{"already below", []BatteryHistorySample{
+			historySample(0, "1234", 81),
+			historySample(10, "1234", 79),
+		}, true, 10},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// GIVEN
+			trend, err := GetBatteryHistoryTrend(test.samples)
+			assert.NoError(t, err)
+
+			// WHEN
+			date, ok := trend.ProjectCapacityDate(80)
+
+			// THEN
+			assert.Equal(t, test.expectOk, ok)
+			if test.expectOk {
+				assert.Equal(t, historySample(test.expectDay, "", 0).Time, date)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ChargeModeFull always charges the battery completely
+	ChargeModeFull = "full"
+	// ChargeModeBalanced keeps the battery mostly charged while reducing wear
+	ChargeModeBalanced = "balanced"
+	// ChargeModeLongevity maximizes the lifespan of a battery which is mostly used on AC
+	ChargeModeLongevity = "longevity"
+
+	PlatformBasePath = "/sys/devices/platform/"
+)
+
+// ChargeModes are the names of all charge modes, ordered by the amount of charge
+var ChargeModes = []string{ChargeModeFull, ChargeModeBalanced, ChargeModeLongevity}
+
+var chargeModeThresholds = map[string]ChargeThresholds{
+	ChargeModeFull:      {Start: 95, End: 100},
+	ChargeModeBalanced:  {Start: 75, End: 80},
+	ChargeModeLongevity: {Start: 55, End: 60},
+}
+
+// ChargeThresholds are the battery levels (in percent) at which charging starts and stops
+type ChargeThresholds struct {
+	// Start is the level below which charging starts, -1 if unknown or not supported
+	Start int
+	// End is the level at which charging stops
+	End int
+}
+
+// GetChargeModeThresholds returns the thresholds of the charge mode with the given name
+func GetChargeModeThresholds(mode string) (ChargeThresholds, error) {
+	thresholds, ok := chargeModeThresholds[mode]
+	if !ok {
+		return ChargeThresholds{}, fmt.Errorf("unknown charge mode '%s', expected one of %v", mode, ChargeModes)
+	}
+	return thresholds, nil
+}
+
+// Mode returns the name of the charge mode matching the thresholds, or an empty string.
+// If the start threshold is unknown, only the end threshold is compared.
+func (t ChargeThresholds) Mode() string {
+	for _, mode := range ChargeModes {
+		thresholds := chargeModeThresholds[mode]
+		if thresholds.End == t.End && (t.Start < 0 || thresholds.Start == t.Start) {
+			return mode
+		}
+	}
+	return ""
+}
+
+// Validate checks that the thresholds are within 0..100 and the start threshold is below the end threshold,
+// a start threshold of -1 is not applied
+func (t ChargeThresholds) Validate() error {
+	if t.End < 1 || t.End > 100 {
+		return fmt.Errorf("end threshold must be between 1 and 100, got %d", t.End)
+	}
+	if t.Start < -1 {
+		return fmt.Errorf("start threshold must be between 0 and 99, got %d", t.Start)
+	}
+	if t.Start >= t.End {
+		return fmt.Errorf("start threshold (%d) must be below the end threshold (%d)", t.Start, t.End)
+	}
+	return nil
+}
+
+// ChargeThresholdBackend reads and writes the charge thresholds of a battery using a vendor specific interface
+type ChargeThresholdBackend interface {
+	// Name is the name of the interface, f.ex. "natacpi" or "tp_smapi"
+	Name() string
+	// SupportsStart reports whether the start threshold can be set
+	SupportsStart() bool
+	GetThresholds() (ChargeThresholds, error)
+	// SetThresholds applies the given thresholds, a negative start threshold is left unchanged
+	SetThresholds(thresholds ChargeThresholds) error
+}
+
+// DetectChargeThresholdBackend returns the backend which controls the charge thresholds of the given battery
+func DetectChargeThresholdBackend(battery string) (ChargeThresholdBackend, error) {
+	return detectChargeThresholdBackend(PowerSupplyBasePath, PlatformBasePath, battery)
+}
+
+func detectChargeThresholdBackend(powerSupplyPath string, platformPath string, battery string) (ChargeThresholdBackend, error) {
+	batteryPath := filepath.Join(powerSupplyPath, battery)
+	if !fileExists(batteryPath) {
+		return nil, fmt.Errorf("battery %s not found", battery)
+	}
+
+	// ThinkPads with the (out of tree) tp_smapi module
+	smapiPath := filepath.Join(platformPath, "smapi", battery)
+	if fileExists(filepath.Join(smapiPath, "stop_charge_thresh")) {
+		return sysfsThresholdBackend{
+			name:      "tp_smapi",
+			startPath: filepath.Join(smapiPath, "start_charge_thresh"),
+			endPath:   filepath.Join(smapiPath, "stop_charge_thresh"),
+		}, nil
+	}
+
+	// Huawei MateBooks expose both thresholds in a single file
+	huaweiPath := filepath.Join(platformPath, "huawei-wmi", "charge_control_thresholds")
+	if fileExists(huaweiPath) {
+		return huaweiThresholdBackend{path: huaweiPath}, nil
+	}
+
+	// ThinkPads with kernels before 5.9 use the old names of natacpi
+	if fileExists(filepath.Join(batteryPath, "charge_stop_threshold")) {
+		return sysfsThresholdBackend{
+			name:      "natacpi",
+			startPath: optionalPath(filepath.Join(batteryPath, "charge_start_threshold")),
+			endPath:   filepath.Join(batteryPath, "charge_stop_threshold"),
+		}, nil
+	}
+
+	endPath := filepath.Join(batteryPath, "charge_control_end_threshold")
+	if fileExists(endPath) {
+		name := "kernel"
+		switch {
+		case fileExists(filepath.Join(platformPath, "thinkpad_acpi")):
+			name = "natacpi"
+		case fileExists(filepath.Join(platformPath, "asus-nb-wmi")):
+			name = "asus-wmi"
+		case fileExists(filepath.Join(platformPath, "cros-charge-control")):
+			name = "framework"
+		}
+		return sysfsThresholdBackend{
+			name:      name,
+			startPath: optionalPath(filepath.Join(batteryPath, "charge_control_start_threshold")),
+			endPath:   endPath,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("battery %s does not support charge thresholds", battery)
+}
+
+// sysfsThresholdBackend controls the thresholds using one file per threshold
+type sysfsThresholdBackend struct {
+	name string
+	// startPath is the file of the start threshold, empty if not supported
+	startPath string
+	endPath   string
+}
+
+func (b sysfsThresholdBackend) Name() string {
+	return b.name
+}
+
+func (b sysfsThresholdBackend) SupportsStart() bool {
+	return b.startPath != ""
+}
+
+func (b sysfsThresholdBackend) GetThresholds() (ChargeThresholds, error) {
+	thresholds := ChargeThresholds{Start: -1}
+	end, err := ReadIntFromFile(b.endPath)
+	if err != nil {
+		return thresholds, err
+	}
+	// some interfaces use 0 as the default, which is a full charge
+	if end == 0 {
+		end = 100
+	}
+	thresholds.End = int(end)
+
+	if b.SupportsStart() {
+		start, err := ReadIntFromFile(b.startPath)
+		if err != nil {
+			return thresholds, err
+		}
+		thresholds.Start = int(start)
+	}
+	return thresholds, nil
+}
+
+func (b sysfsThresholdBackend) SetThresholds(thresholds ChargeThresholds) error {
+	if !b.SupportsStart() || thresholds.Start < 0 {
+		return WriteIntToFile(thresholds.End, b.endPath)
+	}
+
+	// the firmware rejects a start threshold above the current end threshold (and vice versa),
+	// so the order of the writes depends on the current thresholds
+	current, err := b.GetThresholds()
+	if err == nil && thresholds.Start >= current.End {
+		err = WriteIntToFile(thresholds.End, b.endPath)
+		if err != nil {
+			return err
+		}
+		return WriteIntToFile(thresholds.Start, b.startPath)
+	}
+	err = WriteIntToFile(thresholds.Start, b.startPath)
+	if err != nil {
+		return err
+	}
+	return WriteIntToFile(thresholds.End, b.endPath)
+}
+
+// huaweiThresholdBackend controls the thresholds of huawei-wmi, which expects "<start> <end>"
+type huaweiThresholdBackend struct {
+	path string
+}
+
+func (b huaweiThresholdBackend) Name() string {
+	return "huawei-wmi"
+}
+
+func (b huaweiThresholdBackend) SupportsStart() bool {
+	return true
+}
+
+func (b huaweiThresholdBackend) GetThresholds() (ChargeThresholds, error) {
+	content, err := ReadTextFromFile(b.path)
+	if err != nil {
+		return ChargeThresholds{Start: -1}, err
+	}
+	fields := strings.Fields(content)
+	if len(fields) != 2 {
+		return ChargeThresholds{Start: -1}, fmt.Errorf("unexpected content of %s: %s", b.path, content)
+	}
+	start, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return ChargeThresholds{Start: -1}, err
+	}
+	end, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return ChargeThresholds{Start: -1}, err
+	}
+	// "0 0" disables the thresholds, which is a full charge
+	if end == 0 {
+		end = 100
+	}
+	return ChargeThresholds{Start: start, End: end}, nil
+}
+
+func (b huaweiThresholdBackend) SetThresholds(thresholds ChargeThresholds) error {
+	if thresholds.Start < 0 {
+		current, err := b.GetThresholds()
+		if err != nil {
+			return err
+		}
+		thresholds.Start = min(current.Start, thresholds.End-1)
+	}
+	return os.WriteFile(b.path, []byte(fmt.Sprintf("%d %d", thresholds.Start, thresholds.End)), 0644)
+}
+
+// GetChargeBehaviour returns the active and all available charge behaviours of the given battery,
+// f.ex. "auto", "inhibit-charge" and "force-discharge" on Framework laptops
+func GetChargeBehaviour(battery string) (string, []string, error) {
+	content, err := ReadTextFromFile(filepath.Join(PowerSupplyBasePath, battery, "charge_behaviour"))
+	if err != nil {
+		return "", nil, err
+	}
+	active, available := parseChargeBehaviour(content)
+	return active, available, nil
+}
+
+// SetChargeBehaviour sets the charge behaviour of the given battery
+func SetChargeBehaviour(battery string, behaviour string) error {
+	_, available, err := GetChargeBehaviour(battery)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(available, behaviour) {
+		return fmt.Errorf("unsupported charge behaviour '%s', expected one of %v", behaviour, available)
+	}
+	return os.WriteFile(filepath.Join(PowerSupplyBasePath, battery, "charge_behaviour"), []byte(behaviour), 0644)
+}
+
+// parseChargeBehaviour parses the content of charge_behaviour, in which the active behaviour
+// is enclosed in brackets, f.ex. "[auto] inhibit-charge force-discharge"
+func parseChargeBehaviour(content string) (string, []string) {
+	var active string
+	available := make([]string, 0)
+	for _, field := range strings.Fields(content) {
+		if strings.HasPrefix(field, "[") && strings.HasSuffix(field, "]") {
+			field = strings.Trim(field, "[]")
+			active = field
+		}
+		available = append(available, field)
+	}
+	return active, available
+}
+
+// ChargeThresholdRestoreCommand returns the command line which restores the saved thresholds of the given battery
+func ChargeThresholdRestoreCommand(executable string, battery string) string {
+	return fmt.Sprintf("%s battery threshold restore --name %s", executable, battery)
+}
+
+// ChargeThresholdSystemdUnit returns a systemd service which restores the saved thresholds on boot and after resume
+func ChargeThresholdSystemdUnit(executable string, battery string) string {
+	targets := "multi-user.target suspend.target hibernate.target hybrid-sleep.target suspend-then-hibernate.target"
+	return fmt.Sprintf(`[Unit]
+Description=Restore the charge thresholds of battery %s
+After=%s
+
+[Service]
+Type=oneshot
+ExecStart=%s
+
+[Install]
+WantedBy=%s
+`, battery, targets, ChargeThresholdRestoreCommand(executable, battery), targets)
+}
+
+// ChargeThresholdUdevRule returns a udev rule which restores the saved thresholds when the battery is added, f.ex. on boot
+func ChargeThresholdUdevRule(executable string, battery string) string {
+	return fmt.Sprintf(
+		"ACTION==\"add\", SUBSYSTEM==\"power_supply\", KERNEL==\"%s\", RUN+=\"%s\"\n",
+		battery, ChargeThresholdRestoreCommand(executable, battery),
+	)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return !errors.Is(err, os.ErrNotExist)
+}
+
+// optionalPath returns the given path if it exists, or an empty string
+func optionalPath(path string) string {
+	if fileExists(path) {
+		return path
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestFiles creates the given files (relative to root) with the given content
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	return string(content)
+}
+
+func TestDetectChargeThresholdBackend(t *testing.T) {
+	tests := []struct {
+		files         map[string]string
+		expected      string
+		supportsStart bool
+	}{
+		{map[string]string{
+			"power_supply/BAT0/charge_control_start_threshold": "75\n",
+			"power_supply/BAT0/charge_control_end_threshold":   "80\n",
+			"platform/thinkpad_acpi/uevent":                    "",
+		}, "natacpi", true},
+		{map[string]string{
+			"power_supply/BAT0/charge_start_threshold": "75\n",
+			"power_supply/BAT0/charge_stop_threshold":  "80\n",
+		}, "natacpi", true},
+		{map[string]string{
+			"power_supply/BAT0/charge_control_end_threshold": "80\n",
+			"platform/smapi/BAT0/start_charge_thresh":        "75\n",
+			"platform/smapi/BAT0/stop_charge_thresh":         "80\n",
+		}, "tp_smapi", true},
+		{map[string]string{
+			"power_supply/BAT0/charge_control_end_threshold": "80\n",
+			"platform/asus-nb-wmi/uevent":                    "",
+		}, "asus-wmi", false},
+		{map[string]string{
+			"power_supply/BAT0/charge_control_end_threshold": "80\n",
+			"platform/huawei-wmi/charge_control_thresholds":  "75 80\n",
+		}, "huawei-wmi", true},
+		{map[string]string{
+			"power_supply/BAT0/charge_control_end_threshold": "80\n",
+		}, "kernel", false},
+	}
+	for _, test := range tests {
+		// GIVEN
+		root := t.TempDir()
+		writeTestFiles(t, root, test.files)
+
+		// WHEN
+		backend, err := detectChargeThresholdBackend(filepath.Join(root, "power_supply"), filepath.Join(root, "platform"), "BAT0")
+
+		// THEN
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, backend.Name())
+		assert.Equal(t, test.supportsStart, backend.SupportsStart(), test.expected)
+	}
+}
+
+func TestDetectChargeThresholdBackendUnsupported(t *testing.T) {
+	// GIVEN
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{"power_supply/BAT0/capacity": "50\n"})
+
+	// WHEN
+	_, err := detectChargeThresholdBackend(filepath.Join(root, "power_supply"), filepath.Join(root, "platform"), "BAT0")
+
+	// THEN
+	assert.EqualError(t, err, "battery BAT0 does not support charge thresholds")
+}
+
+func TestSysfsThresholdBackend(t *testing.T) {
+	// GIVEN
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{"start": "40\n", "end": "50\n"})
+	backend := sysfsThresholdBackend{name: "kernel", startPath: filepath.Join(root, "start"), endPath: filepath.Join(root, "end")}
+
+	// WHEN
+	err := backend.SetThresholds(ChargeThresholds{Start: 75, End: 80})
+
+	// THEN
+	assert.NoError(t, err)
+	thresholds, err := backend.GetThresholds()
+	assert.NoError(t, err)
+	assert.Equal(t, ChargeThresholds{Start: 75, End: 80}, thresholds)
+	assert.Equal(t, ChargeModeBalanced, thresholds.Mode())
+
+	// a negative start threshold is left unchanged
+	err = backend.SetThresholds(ChargeThresholds{Start: -1, End: 90})
+	assert.NoError(t, err)
+	assert.Equal(t, "75", readTestFile(t, backend.startPath))
+	assert.Equal(t, "90", readTestFile(t, backend.endPath))
+}
+
+func TestHuaweiThresholdBackend(t *testing.T) {
+	// GIVEN
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{"thresholds": "0 0\n"})
+	backend := huaweiThresholdBackend{path: filepath.Join(root, "thresholds")}
+
+	// WHEN
+	thresholds, err := backend.GetThresholds()
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, ChargeThresholds{Start: 0, End: 100}, thresholds)
+
+	err = backend.SetThresholds(ChargeThresholds{Start: 55, End: 60})
+	assert.NoError(t, err)
+	assert.Equal(t, "55 60", readTestFile(t, backend.path))
+}
+
+func TestChargeThresholdsValidate(t *testing.T) {
+	assert.NoError(t, ChargeThresholds{Start: -1, End: 80}.Validate())
+	assert.NoError(t, ChargeThresholds{Start: 0, End: 100}.Validate())
+	assert.EqualError(t, ChargeThresholds{Start: -1, End: 101}.Validate(), "end threshold must be between 1 and 100, got 101")
+	assert.EqualError(t, ChargeThresholds{Start: 80, End: 80}.Validate(), "start threshold (80) must be below the end threshold (80)")
+	assert.EqualError(t, ChargeThresholds{Start: -5, End: 80}.Validate(), "start threshold must be between 0 and 99, got -5")
+}
+
+func TestGetChargeModeThresholds(t *testing.T) {
+	thresholds, err := GetChargeModeThresholds(ChargeModeLongevity)
+	assert.NoError(t, err)
+	assert.Equal(t, ChargeThresholds{Start: 55, End: 60}, thresholds)
+	assert.Equal(t, ChargeModeFull, ChargeThresholds{Start: -1, End: 100}.Mode())
+	assert.Equal(t, "", ChargeThresholds{Start: 10, End: 100}.Mode())
+
+	_, err = GetChargeModeThresholds("eco")
+	assert.EqualError(t, err, "unknown charge mode 'eco', expected one of [full balanced longevity]")
+}
+
+func TestParseChargeBehaviour(t *testing.T) {
+	active, available := parseChargeBehaviour("auto [inhibit-charge] force-discharge\n")
+
+	assert.Equal(t, "inhibit-charge", active)
+	assert.Equal(t, []string{"auto", "inhibit-charge", "force-discharge"}, available)
+}
+
+func TestChargeThresholdInstallation(t *testing.T) {
+	unit := ChargeThresholdSystemdUnit("/usr/bin/system-control", "BAT0")
+	assert.Contains(t, unit, "ExecStart=/usr/bin/system-control battery threshold restore --name BAT0\n")
+	assert.Contains(t, unit, "WantedBy=multi-user.target suspend.target")
+
+	rule := ChargeThresholdUdevRule("/usr/bin/system-control", "BAT1")
+	assert.Equal(t, `ACTION=="add", SUBSYSTEM=="power_supply", KERNEL=="BAT1", RUN+="/usr/bin/system-control battery threshold restore --name BAT1"`+"\n", rule)
+}
@@ -1,6 +1,9 @@
 package util
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // ReplacePlaceholders replaces placeholders in a template string with the values from a map.
 // Placeholders are defined by a percent sign followed by the key and another percent sign, e.g. %key%.
@@ -11,3 +14,17 @@ func ReplacePlaceholders(template string, placeholders map[string]string) string
 	}
 	return result
 }
+
+// FormatBytes formats the given number of bytes using binary units, f.ex. "1.5 MiB"
+func FormatBytes(bytes float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	unit := 0
+	for bytes >= 1024 && unit < len(units)-1 {
+		bytes /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", bytes, units[unit])
+	}
+	return fmt.Sprintf("%.1f %s", bytes, units[unit])
+}
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	notificationsBusName    = "org.freedesktop.Notifications"
+	notificationsObjectPath = "/org/freedesktop/Notifications"
+	notificationsInterface  = "org.freedesktop.Notifications"
+
+	NotificationUrgencyLow      byte = 0
+	NotificationUrgencyNormal   byte = 1
+	NotificationUrgencyCritical byte = 2
+)
+
+// SendNotification shows a desktop notification using org.freedesktop.Notifications on the session bus.
+// If replacesId is not 0, the notification with this id is replaced. The id of the new notification is returned.
+func SendNotification(replacesId uint32, icon string, summary string, body string, urgency byte) (uint32, error) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return 0, err
+	}
+	return sendNotification(conn, replacesId, icon, summary, body, urgency)
+}
+
+func sendNotification(conn *dbus.Conn, replacesId uint32, icon string, summary string, body string, urgency byte) (uint32, error) {
+	hints := map[string]dbus.Variant{
+		"urgency": dbus.MakeVariant(urgency),
+	}
+	var id uint32
+	err := conn.Object(notificationsBusName, notificationsObjectPath).Call(
+		notificationsInterface+".Notify", 0,
+		"system-control", replacesId, icon, summary, body, []string{}, hints, int32(-1),
+	).Store(&id)
+	return id, err
+}
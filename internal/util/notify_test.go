@@ -0,0 +1,49 @@
+package util
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/markusressel/system-control/internal/dbustest"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeNotifications struct {
+	mu       sync.Mutex
+	summary  string
+	urgency  byte
+	replaces uint32
+}
+
+func (n *fakeNotifications) Notify(appName string, replacesId uint32, icon string, summary string, body string, actions []string, hints map[string]dbus.Variant, timeout int32) (uint32, *dbus.Error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.summary = summary
+	n.replaces = replacesId
+	n.urgency, _ = hints["urgency"].Value().(byte)
+	return 42, nil
+}
+
+func TestSendNotification(t *testing.T) {
+	// GIVEN
+	address := dbustest.StartBus(t)
+	service := dbustest.Connect(t, address)
+	notifications := &fakeNotifications{}
+	err := service.Export(notifications, notificationsObjectPath, notificationsInterface)
+	assert.NoError(t, err)
+	_, err = service.RequestName(notificationsBusName, dbus.NameFlagDoNotQueue)
+	assert.NoError(t, err)
+
+	// WHEN
+	id, err := sendNotification(dbustest.Connect(t, address), 7, "battery-caution", "Battery low", "Headset: 10%", NotificationUrgencyCritical)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(42), id)
+	notifications.mu.Lock()
+	defer notifications.mu.Unlock()
+	assert.Equal(t, "Battery low", notifications.summary)
+	assert.Equal(t, uint32(7), notifications.replaces)
+	assert.Equal(t, NotificationUrgencyCritical, notifications.urgency)
+}
@@ -0,0 +1,22 @@
+package util
+
+import (
+	"fmt"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// PrintQRCode prints the given content as a QR code to the console, using unicode block characters
+func PrintQRCode(content string) error {
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return err
+	}
+	fmt.Print(code.ToSmallString(false))
+	return nil
+}
+
+// WriteQRCodePNG writes the given content as a QR code to a PNG file with the given width and height in pixels
+func WriteQRCodePNG(content string, path string, size int) error {
+	return qrcode.WriteFile(content, qrcode.Medium, size, path)
+}
@@ -28,6 +28,9 @@ func ReadRedshiftConfig() (RedshiftConfig, error) {
 	home, _ := os.UserHomeDir()
 	configPath := filepath.Join(home, ".config/redshift.conf")
 	doc, err := os.ReadFile(configPath)
+	if err != nil {
+		return RedshiftConfig{}, err
+	}
 
 	lines := strings.Split(string(doc), "\n")
 	var linesWithoutComments []string
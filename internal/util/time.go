@@ -0,0 +1,15 @@
+package util
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParseTimeOfDay parses a time of day in the format HH:MM and returns it as the duration since midnight
+func ParseTimeOfDay(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day '%s', expected format HH:MM", value)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
@@ -0,0 +1,491 @@
+package wifi
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	BandAny = ""
+	Band2G  = "2.4"
+	Band5G  = "5"
+	Band6G  = "6"
+
+	EAPMethodPEAP = "peap"
+	EAPMethodTTLS = "ttls"
+
+	keyMgmtNone      = ""
+	keyMgmtWEP       = "none"
+	keyMgmtPSK       = "wpa-psk"
+	keyMgmtSAE       = "sae"
+	keyMgmtOWE       = "owe"
+	keyMgmtEAP       = "wpa-eap"
+	keyMgmtEAPSuiteB = "wpa-eap-suite-b-192"
+
+	activationTimeout = 60 * time.Second
+
+	activeConnectionStateActivated   = 2
+	activeConnectionStateDeactivated = 4
+
+	deviceStateReasonNoSecrets              = 7
+	deviceStateReasonSupplicantDisconnect   = 8
+	deviceStateReasonSupplicantConfigFailed = 9
+	deviceStateReasonSupplicantFailed       = 10
+	deviceStateReasonSupplicantTimeout      = 11
+	deviceStateReasonSsidNotFound           = 53
+)
+
+var (
+	// ErrNetworkNotFound is returned if the requested network is not in range
+	ErrNetworkNotFound = errors.New("network not found")
+	// ErrAuthenticationFailed is returned if the network rejected the given credentials
+	ErrAuthenticationFailed = errors.New("authentication failed")
+)
+
+// ConnectOptions describes the WiFi network to connect to and the credentials to use
+type ConnectOptions struct {
+	// SSID of the network, also used as the name of a new connection profile.
+	// May also be the name or UUID of an existing connection profile.
+	SSID string
+	// Password is the pre-shared key, or the user password for enterprise networks
+	Password string
+	// Hidden must be set for networks that do not broadcast their SSID
+	Hidden bool
+	// BSSID restricts the connection to a single access point
+	BSSID string
+	// Band restricts the connection to a frequency band, one of BandAny, Band2G, Band5G or Band6G
+	Band string
+	// Interface is the name of the WiFi device to use, empty to use the first WiFi device
+	Interface string
+	// Enterprise contains the 802.1X settings for WPA2/WPA3-Enterprise networks, nil for other networks
+	Enterprise *EnterpriseOptions
+	// Timeout is the maximum time to wait for the connection to be established
+	Timeout time.Duration
+}
+
+// EnterpriseOptions contains the 802.1X settings for WPA2/WPA3-Enterprise networks
+type EnterpriseOptions struct {
+	// EAP is the EAP method, one of EAPMethodPEAP or EAPMethodTTLS
+	EAP string `yaml:"eap"`
+	// Phase2 is the inner authentication method, f.ex. "mschapv2"
+	Phase2            string `yaml:"phase2,omitempty"`
+	Identity          string `yaml:"identity"`
+	AnonymousIdentity string `yaml:"anonymousIdentity,omitempty"`
+	// CACert is the path to the CA certificate used to verify the authentication server
+	CACert string `yaml:"caCert,omitempty"`
+}
+
+// ConnectWithOptions connects to a WiFi network without any interactive prompts,
+// creating or updating a NetworkManager connection profile if necessary.
+// NetworkManager is controlled via D-Bus, with nmcli as a fallback if it is not reachable that way.
+func ConnectWithOptions(options ConnectOptions) error {
+	nm, err := SystemNetworkManager()
+	if err != nil || !nm.isRunning() {
+		return connectWithNmcli(options)
+	}
+	return nm.ConnectWifi(options)
+}
+
+// ConnectWifi connects to a WiFi network without any interactive prompts.
+// If a WiFi connection profile with the given name (or UUID) exists and no credentials are given,
+// the existing profile is activated. Otherwise, the profile is created or updated, which requires
+// the network to be in range unless it is hidden.
+func (nm *NetworkManager) ConnectWifi(options ConnectOptions) error {
+	if options.Timeout <= 0 {
+		options.Timeout = activationTimeout
+	}
+	if !slices.Contains([]string{BandAny, Band2G, Band5G, Band6G}, options.Band) {
+		return fmt.Errorf("invalid band: %s", options.Band)
+	}
+	if options.Enterprise != nil && options.Password == "" {
+		return errors.New("a password is required for enterprise networks")
+	}
+
+	devicePath, err := nm.findWifiDevice(options.Interface)
+	if err != nil {
+		return err
+	}
+
+	existing, err := nm.findWifiConnection(options.SSID)
+	if err != nil && !errors.Is(err, ErrConnectionNotFound) {
+		return err
+	}
+	exists := err == nil
+	hasCredentials := options.Password != "" || options.Enterprise != nil
+	if exists && !hasCredentials && !options.Hidden && options.BSSID == "" && options.Band == BandAny {
+		// NetworkManager selects the access point, so the network does not have to be part of the last scan
+		err = nm.activate(dbus.ObjectPath(existing.DBUSPath), devicePath, "/", options.Timeout)
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", existing.Name, err)
+		}
+		return nil
+	}
+
+	name := options.SSID
+	connectionUUID := uuid.NewString()
+	if exists {
+		// keep the name of the profile, which may differ from the SSID of its network
+		name = existing.Name
+		connectionUUID = existing.UUID
+		ssid, err := nm.getConnectionSSID(dbus.ObjectPath(existing.DBUSPath))
+		if err != nil {
+			return err
+		}
+		if ssid != "" {
+			options.SSID = ssid
+		}
+	}
+
+	keyMgmt := keyMgmtNone
+	accessPointPath := dbus.ObjectPath("/")
+	if !options.Hidden {
+		accessPoint, properties, err := nm.findAccessPoint(devicePath, options)
+		if err != nil {
+			return err
+		}
+		accessPointPath = accessPoint
+		keyMgmt = accessPointKeyMgmt(
+			variantValue[uint32](properties, "Flags"),
+			variantValue[uint32](properties, "WpaFlags"),
+			variantValue[uint32](properties, "RsnFlags"),
+		)
+		if options.Band == Band6G && options.BSSID == "" {
+			// NetworkManager can not restrict a profile to the 6 GHz band, so stick to the access point instead
+			options.BSSID = variantValue[string](properties, "HwAddress")
+		}
+	} else {
+		switch {
+		case options.Enterprise != nil:
+			keyMgmt = keyMgmtEAP
+		case options.Password != "":
+			keyMgmt = keyMgmtPSK
+		}
+	}
+
+	if keyMgmt != keyMgmtNone && keyMgmt != keyMgmtOWE && !hasCredentials {
+		return fmt.Errorf("network %s is secured, a password is required", options.SSID)
+	}
+
+	settings, err := buildWifiSettings(options, keyMgmt, connectionUUID)
+	if err != nil {
+		return err
+	}
+	settings["connection"]["id"] = dbus.MakeVariant(name)
+
+	if exists {
+		existingPath := dbus.ObjectPath(existing.DBUSPath)
+		err = nm.conn.Object(nmBusName, existingPath).Call(nmSettingsConnectionInterface+".Update", 0, settings).Err
+		if err != nil {
+			return err
+		}
+		err = nm.activate(existingPath, devicePath, accessPointPath, options.Timeout)
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", name, err)
+		}
+		return nil
+	}
+
+	var connectionPath, activePath dbus.ObjectPath
+	err = nm.conn.Object(nmBusName, nmObjectPath).Call(nmInterface+".AddAndActivateConnection", 0, settings, devicePath, accessPointPath).Store(&connectionPath, &activePath)
+	if err != nil {
+		return err
+	}
+	err = nm.waitForActivation(activePath, devicePath, options.Timeout)
+	if err != nil {
+		// do not keep profiles with wrong credentials around, like "nmcli device wifi connect"
+		_ = nm.conn.Object(nmBusName, connectionPath).Call(nmSettingsConnectionInterface+".Delete", 0).Err
+		return fmt.Errorf("failed to connect to %s: %w", options.SSID, err)
+	}
+	return nil
+}
+
+// activate activates an existing connection profile and waits until it is established
+func (nm *NetworkManager) activate(connectionPath dbus.ObjectPath, devicePath dbus.ObjectPath, specificObject dbus.ObjectPath, timeout time.Duration) error {
+	var activePath dbus.ObjectPath
+	err := nm.conn.Object(nmBusName, nmObjectPath).Call(nmInterface+".ActivateConnection", 0, connectionPath, devicePath, specificObject).Store(&activePath)
+	if err != nil {
+		return err
+	}
+	return nm.waitForActivation(activePath, devicePath, timeout)
+}
+
+// waitForActivation waits until the given active connection is established or has failed
+func (nm *NetworkManager) waitForActivation(activePath dbus.ObjectPath, devicePath dbus.ObjectPath, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		properties, err := nm.getProperties(activePath, nmActiveConnectionInterface)
+		state := variantValue[uint32](properties, "State")
+		switch {
+		case err == nil && state == activeConnectionStateActivated:
+			return nil
+		case err != nil || state == activeConnectionStateDeactivated:
+			// the active connection object is removed as soon as the activation failed
+			return nm.activationError(devicePath)
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+	return fmt.Errorf("timeout after %s while waiting for the connection to be established", timeout)
+}
+
+// activationError returns an error describing why the activation on the given device failed
+func (nm *NetworkManager) activationError(devicePath dbus.ObjectPath) error {
+	if !isValidPath(devicePath) {
+		// connections without a device (f.ex. VPNs) have no state reason
+		return errors.New("activation failed")
+	}
+	var deviceStateReason uint32
+	properties, err := nm.getProperties(devicePath, nmDeviceInterface)
+	if err == nil {
+		if stateReason := variantValue[[]any](properties, "StateReason"); len(stateReason) == 2 {
+			deviceStateReason, _ = stateReason[1].(uint32)
+		}
+	}
+	return deviceStateReasonError(deviceStateReason)
+}
+
+// deviceStateReasonError maps the device state reason of a failed activation to an error
+func deviceStateReasonError(reason uint32) error {
+	switch reason {
+	case deviceStateReasonNoSecrets, deviceStateReasonSupplicantDisconnect, deviceStateReasonSupplicantConfigFailed,
+		deviceStateReasonSupplicantFailed, deviceStateReasonSupplicantTimeout:
+		return ErrAuthenticationFailed
+	case deviceStateReasonSsidNotFound:
+		return ErrNetworkNotFound
+	default:
+		return fmt.Errorf("activation failed (device state reason: %d)", reason)
+	}
+}
+
+// findWifiDevice returns the WiFi device with the given interface name, or the first WiFi device if name is empty
+func (nm *NetworkManager) findWifiDevice(name string) (dbus.ObjectPath, error) {
+	devicePaths, err := nm.getDevicePaths()
+	if err != nil {
+		return "", err
+	}
+	for _, devicePath := range devicePaths {
+		properties, err := nm.getProperties(devicePath, nmDeviceInterface)
+		if err != nil {
+			return "", err
+		}
+		if variantValue[uint32](properties, "DeviceType") != nmDeviceTypeWifi {
+			continue
+		}
+		if name == "" || variantValue[string](properties, "Interface") == name {
+			return devicePath, nil
+		}
+	}
+	if name != "" {
+		return "", fmt.Errorf("WiFi device not found: %s", name)
+	}
+	return "", errors.New("no WiFi device found")
+}
+
+// findAccessPoint returns the access point with the strongest signal matching the given options
+func (nm *NetworkManager) findAccessPoint(devicePath dbus.ObjectPath, options ConnectOptions) (dbus.ObjectPath, map[string]dbus.Variant, error) {
+	wirelessProperties, err := nm.getProperties(devicePath, nmWirelessInterface)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var bestPath dbus.ObjectPath
+	var bestProperties map[string]dbus.Variant
+	for _, accessPointPath := range variantValue[[]dbus.ObjectPath](wirelessProperties, "AccessPoints") {
+		properties, err := nm.getProperties(accessPointPath, nmAccessPointInterface)
+		if err != nil {
+			continue
+		}
+		if string(variantValue[[]byte](properties, "Ssid")) != options.SSID {
+			continue
+		}
+		if options.BSSID != "" && !strings.EqualFold(variantValue[string](properties, "HwAddress"), options.BSSID) {
+			continue
+		}
+		if !IsFrequencyInBand(int(variantValue[uint32](properties, "Frequency")), options.Band) {
+			continue
+		}
+		if bestProperties == nil || variantValue[byte](properties, "Strength") > variantValue[byte](bestProperties, "Strength") {
+			bestPath = accessPointPath
+			bestProperties = properties
+		}
+	}
+
+	if bestProperties == nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrNetworkNotFound, options.SSID)
+	}
+	return bestPath, bestProperties, nil
+}
+
+// findWifiConnection returns the WiFi connection profile with the given name or UUID
+func (nm *NetworkManager) findWifiConnection(nameOrUUID string) (Connection, error) {
+	connections, err := nm.GetConnections()
+	if err != nil {
+		return Connection{}, err
+	}
+	return findConnection(FilterWifiConnections(connections), nameOrUUID)
+}
+
+// FilterWifiConnections returns all WiFi connection profiles, including hotspots
+func FilterWifiConnections(connections []Connection) []Connection {
+	return slices.DeleteFunc(slices.Clone(connections), func(connection Connection) bool {
+		return connection.Type != "wifi"
+	})
+}
+
+// getConnectionSSID returns the SSID of the WiFi connection profile with the given path
+func (nm *NetworkManager) getConnectionSSID(path dbus.ObjectPath) (string, error) {
+	settings, err := nm.GetConnectionSettings(path, false)
+	if err != nil {
+		return "", err
+	}
+	return string(variantValue[[]byte](settings["802-11-wireless"], "ssid")), nil
+}
+
+// findConnectionByName returns the path and UUID of the connection profile with the given name, if any
+func (nm *NetworkManager) findConnectionByName(name string) (dbus.ObjectPath, string, error) {
+	connections, err := nm.GetConnections()
+	if err != nil {
+		return "", "", err
+	}
+	for _, connection := range connections {
+		if connection.Name == name {
+			return dbus.ObjectPath(connection.DBUSPath), connection.UUID, nil
+		}
+	}
+	return "", "", nil
+}
+
+// IsFrequencyInBand returns true if the given frequency in MHz is part of the given band
+func IsFrequencyInBand(frequency int, band string) bool {
+	switch band {
+	case Band2G:
+		return frequency >= 2400 && frequency < 2500
+	case Band5G:
+		return frequency >= 5150 && frequency < 5925
+	case Band6G:
+		return frequency >= 5925 && frequency <= 7125
+	default:
+		return true
+	}
+}
+
+// accessPointKeyMgmt returns the NetworkManager key management to use for an access point with the given flags
+func accessPointKeyMgmt(flags uint32, wpaFlags uint32, rsnFlags uint32) string {
+	securityFlags := wpaFlags | rsnFlags
+	switch {
+	case securityFlags&apSecKeyMgmtEAPB192 != 0:
+		return keyMgmtEAPSuiteB
+	case securityFlags&apSecKeyMgmt8021X != 0:
+		return keyMgmtEAP
+	case securityFlags&apSecKeyMgmtPSK != 0:
+		return keyMgmtPSK
+	case securityFlags&apSecKeyMgmtSAE != 0:
+		return keyMgmtSAE
+	case securityFlags&(apSecKeyMgmtOWE|apSecKeyMgmtOWETM) != 0:
+		return keyMgmtOWE
+	case flags&apFlagPrivacy != 0:
+		return keyMgmtWEP
+	default:
+		return keyMgmtNone
+	}
+}
+
+// buildWifiSettings creates the NetworkManager connection settings for the given options
+func buildWifiSettings(options ConnectOptions, keyMgmt string, connectionUUID string) (map[string]map[string]dbus.Variant, error) {
+	wireless := map[string]dbus.Variant{
+		"ssid":   dbus.MakeVariant([]byte(options.SSID)),
+		"mode":   dbus.MakeVariant("infrastructure"),
+		"hidden": dbus.MakeVariant(options.Hidden),
+	}
+	if options.BSSID != "" {
+		bssid, err := net.ParseMAC(options.BSSID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BSSID: %w", err)
+		}
+		wireless["bssid"] = dbus.MakeVariant([]byte(bssid))
+	}
+	switch options.Band {
+	case Band2G:
+		wireless["band"] = dbus.MakeVariant("bg")
+	case Band5G:
+		wireless["band"] = dbus.MakeVariant("a")
+	case BandAny, Band6G:
+	default:
+		return nil, fmt.Errorf("invalid band: %s", options.Band)
+	}
+
+	settings := map[string]map[string]dbus.Variant{
+		"connection": {
+			"id":   dbus.MakeVariant(options.SSID),
+			"uuid": dbus.MakeVariant(connectionUUID),
+			"type": dbus.MakeVariant("802-11-wireless"),
+		},
+		"802-11-wireless": wireless,
+		"ipv4":            {"method": dbus.MakeVariant("auto")},
+		"ipv6":            {"method": dbus.MakeVariant("auto")},
+	}
+	if keyMgmt == keyMgmtNone {
+		return settings, nil
+	}
+
+	security := map[string]dbus.Variant{
+		"key-mgmt": dbus.MakeVariant(keyMgmt),
+	}
+	switch keyMgmt {
+	case keyMgmtPSK, keyMgmtSAE:
+		security["psk"] = dbus.MakeVariant(options.Password)
+	case keyMgmtWEP:
+		security["wep-key0"] = dbus.MakeVariant(options.Password)
+	case keyMgmtEAP, keyMgmtEAPSuiteB:
+		if options.Enterprise == nil {
+			return nil, errors.New("network requires enterprise authentication, an EAP method and identity are required")
+		}
+		ieee8021x, err := buildEnterpriseSettings(*options.Enterprise, options.Password)
+		if err != nil {
+			return nil, err
+		}
+		settings["802-1x"] = ieee8021x
+	}
+	settings["802-11-wireless-security"] = security
+
+	return settings, nil
+}
+
+func buildEnterpriseSettings(options EnterpriseOptions, password string) (map[string]dbus.Variant, error) {
+	if !slices.Contains([]string{EAPMethodPEAP, EAPMethodTTLS}, options.EAP) {
+		return nil, fmt.Errorf("unsupported EAP method: %s", options.EAP)
+	}
+	if options.Identity == "" {
+		return nil, errors.New("an identity is required for enterprise authentication")
+	}
+	phase2 := options.Phase2
+	if phase2 == "" {
+		phase2 = "mschapv2"
+	}
+
+	settings := map[string]dbus.Variant{
+		"eap":         dbus.MakeVariant([]string{options.EAP}),
+		"identity":    dbus.MakeVariant(options.Identity),
+		"password":    dbus.MakeVariant(password),
+		"phase2-auth": dbus.MakeVariant(phase2),
+	}
+	if options.AnonymousIdentity != "" {
+		settings["anonymous-identity"] = dbus.MakeVariant(options.AnonymousIdentity)
+	}
+	if options.CACert != "" {
+		caCert, err := filepath.Abs(options.CACert)
+		if err != nil {
+			return nil, err
+		}
+		// certificates are referenced by a NUL terminated file URI
+		settings["ca-cert"] = dbus.MakeVariant([]byte("file://" + caCert + "\x00"))
+	}
+	return settings, nil
+}
@@ -0,0 +1,212 @@
+package wifi
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/markusressel/system-control/internal/dbustest"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeActivations records the ActivateConnection calls of a fake NetworkManager
+type fakeActivations struct {
+	mu    sync.Mutex
+	calls [][]dbus.ObjectPath
+}
+
+func (a *fakeActivations) ActivateConnection(connection dbus.ObjectPath, device dbus.ObjectPath, specificObject dbus.ObjectPath) (dbus.ObjectPath, *dbus.Error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.calls = append(a.calls, []dbus.ObjectPath{connection, device, specificObject})
+	return testActivePath, nil
+}
+
+func (a *fakeActivations) Calls() [][]dbus.ObjectPath {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.calls
+}
+
+func TestBuildWifiSettings_PSK(t *testing.T) {
+	// GIVEN
+	options := ConnectOptions{
+		SSID:     "My Network",
+		Password: "secret123",
+		BSSID:    "aa:bb:cc:dd:ee:01",
+		Band:     Band5G,
+	}
+
+	// WHEN
+	settings, err := buildWifiSettings(options, keyMgmtPSK, "0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11")
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, "My Network", settings["connection"]["id"].Value())
+	assert.Equal(t, []byte("My Network"), settings["802-11-wireless"]["ssid"].Value())
+	assert.Equal(t, []byte{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0x01}, settings["802-11-wireless"]["bssid"].Value())
+	assert.Equal(t, "a", settings["802-11-wireless"]["band"].Value())
+	assert.Equal(t, "wpa-psk", settings["802-11-wireless-security"]["key-mgmt"].Value())
+	assert.Equal(t, "secret123", settings["802-11-wireless-security"]["psk"].Value())
+	assert.NotContains(t, settings, "802-1x")
+}
+
+func TestBuildWifiSettings_Open(t *testing.T) {
+	// GIVEN
+	options := ConnectOptions{SSID: "Café", Hidden: true}
+
+	// WHEN
+	settings, err := buildWifiSettings(options, keyMgmtNone, "0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11")
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, true, settings["802-11-wireless"]["hidden"].Value())
+	assert.NotContains(t, settings, "802-11-wireless-security")
+}
+
+func TestBuildWifiSettings_Enterprise(t *testing.T) {
+	// GIVEN
+	options := ConnectOptions{
+		SSID:     "eduroam",
+		Password: "secret123",
+		Enterprise: &EnterpriseOptions{
+			EAP:               EAPMethodTTLS,
+			Phase2:            "pap",
+			Identity:          "user@example.org",
+			AnonymousIdentity: "anonymous@example.org",
+			CACert:            "/etc/ssl/certs/ca.pem",
+		},
+	}
+
+	// WHEN
+	settings, err := buildWifiSettings(options, keyMgmtEAP, "0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11")
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, "wpa-eap", settings["802-11-wireless-security"]["key-mgmt"].Value())
+	ieee8021x := settings["802-1x"]
+	assert.Equal(t, []string{"ttls"}, ieee8021x["eap"].Value())
+	assert.Equal(t, "pap", ieee8021x["phase2-auth"].Value())
+	assert.Equal(t, "user@example.org", ieee8021x["identity"].Value())
+	assert.Equal(t, "anonymous@example.org", ieee8021x["anonymous-identity"].Value())
+	assert.Equal(t, "secret123", ieee8021x["password"].Value())
+	assert.Equal(t, []byte("file:///etc/ssl/certs/ca.pem\x00"), ieee8021x["ca-cert"].Value())
+}
+
+func TestBuildWifiSettings_EnterpriseMissingOptions(t *testing.T) {
+	_, err := buildWifiSettings(ConnectOptions{SSID: "eduroam", Password: "secret123"}, keyMgmtEAP, "0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11")
+	assert.Error(t, err)
+
+	_, err = buildWifiSettings(ConnectOptions{SSID: "eduroam", Enterprise: &EnterpriseOptions{EAP: "tls", Identity: "user"}}, keyMgmtEAP, "0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11")
+	assert.Error(t, err)
+}
+
+func TestAccessPointKeyMgmt(t *testing.T) {
+	assert.Equal(t, keyMgmtNone, accessPointKeyMgmt(0, 0, 0))
+	assert.Equal(t, keyMgmtWEP, accessPointKeyMgmt(apFlagPrivacy, 0, 0))
+	assert.Equal(t, keyMgmtPSK, accessPointKeyMgmt(apFlagPrivacy, 0, apSecKeyMgmtPSK|apSecKeyMgmtSAE))
+	assert.Equal(t, keyMgmtSAE, accessPointKeyMgmt(apFlagPrivacy, 0, apSecKeyMgmtSAE))
+	assert.Equal(t, keyMgmtEAP, accessPointKeyMgmt(apFlagPrivacy, 0, apSecKeyMgmt8021X))
+	assert.Equal(t, keyMgmtEAPSuiteB, accessPointKeyMgmt(apFlagPrivacy, 0, apSecKeyMgmtEAPB192))
+}
+
+func TestDeviceStateReasonError(t *testing.T) {
+	assert.ErrorIs(t, deviceStateReasonError(deviceStateReasonNoSecrets), ErrAuthenticationFailed)
+	assert.ErrorIs(t, deviceStateReasonError(deviceStateReasonSupplicantDisconnect), ErrAuthenticationFailed)
+	assert.ErrorIs(t, deviceStateReasonError(deviceStateReasonSsidNotFound), ErrNetworkNotFound)
+	assert.Error(t, deviceStateReasonError(0))
+}
+
+func TestNetworkManager_ConnectWifi_NetworkNotFound(t *testing.T) {
+	// GIVEN
+	nm := startFakeNetworkManager(t)
+
+	// WHEN
+	err := nm.ConnectWifi(ConnectOptions{SSID: "Unknown Network", Password: "secret123"})
+	errWrongBand := nm.ConnectWifi(ConnectOptions{SSID: "My Network: 5 GHz", Password: "secret123", Band: Band2G})
+	errWrongDevice := nm.ConnectWifi(ConnectOptions{SSID: "My Network: 5 GHz", Interface: "wlan1"})
+
+	// THEN
+	assert.ErrorIs(t, err, ErrNetworkNotFound)
+	assert.ErrorIs(t, errWrongBand, ErrNetworkNotFound)
+	assert.EqualError(t, errWrongDevice, "WiFi device not found: wlan1")
+}
+
+func TestNetworkManager_ConnectWifi_EnterpriseWithoutPassword(t *testing.T) {
+	// GIVEN
+	nm := startFakeNetworkManager(t)
+
+	// WHEN
+	err := nm.ConnectWifi(ConnectOptions{SSID: "eduroam", Enterprise: &EnterpriseOptions{EAP: EAPMethodPEAP, Identity: "user"}})
+
+	// THEN
+	assert.EqualError(t, err, "a password is required for enterprise networks")
+}
+
+func TestNetworkManager_isRunning(t *testing.T) {
+	// GIVEN
+	address := dbustest.StartBus(t)
+	nm := NewNetworkManager(dbustest.Connect(t, address))
+
+	// WHEN / THEN
+	assert.False(t, nm.isRunning())
+	assert.True(t, startFakeNetworkManager(t).isRunning())
+}
+
+func TestNetworkManager_findAccessPoint(t *testing.T) {
+	// GIVEN
+	nm := startFakeNetworkManager(t)
+
+	// WHEN
+	path, properties, err := nm.findAccessPoint(testWifiDevicePath, ConnectOptions{SSID: "My Network: 5 GHz", BSSID: "aa:bb:cc:dd:ee:01"})
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, testAccessPointPath, path)
+	assert.Equal(t, uint32(5180), properties["Frequency"].Value())
+}
+
+func TestNetworkManager_ConnectWifi_ExistingProfile(t *testing.T) {
+	// GIVEN
+	nm, service := startFakeNetworkManagerService(t)
+	activations := &fakeActivations{}
+	assert.NoError(t, service.Export(activations, nmObjectPath, nmInterface))
+
+	// a profile which is named differently than its network, which is not in range
+	homePath := dbus.ObjectPath("/org/freedesktop/NetworkManager/Settings/3")
+	assert.NoError(t, service.Export(fakeSettings{connections: []dbus.ObjectPath{testVpnSettingsPath, testWifiSettingsPath, homePath}}, nmSettingsObjectPath, nmSettingsInterface))
+	assert.NoError(t, service.Export(fakeSettingsConnection{settings: map[string]map[string]dbus.Variant{
+		"connection": {
+			"id":   dbus.MakeVariant("Home"),
+			"uuid": dbus.MakeVariant("8e1f3c5a-2b4d-4f6e-9a1c-3d5e7f9b1a2c"),
+			"type": dbus.MakeVariant("802-11-wireless"),
+		},
+		"802-11-wireless": {
+			"ssid": dbus.MakeVariant([]byte("FRITZ!Box 7590")),
+		},
+	}}, homePath, nmSettingsConnectionInterface))
+	dbustest.ExportProperties(t, service, homePath, map[string]map[string]any{
+		nmSettingsConnectionInterface: {
+			"Filename": "/etc/NetworkManager/system-connections/Home.nmconnection",
+		},
+	})
+
+	// WHEN
+	errByName := nm.ConnectWifi(ConnectOptions{SSID: "Home"})
+	errByUUID := nm.ConnectWifi(ConnectOptions{SSID: "8e1f3c5a-2b4d-4f6e-9a1c-3d5e7f9b1a2c"})
+	errWithPassword := nm.ConnectWifi(ConnectOptions{SSID: "Home", Password: "secret123"})
+	errVpn := nm.ConnectWifi(ConnectOptions{SSID: "Office VPN"})
+
+	// THEN
+	assert.NoError(t, errByName)
+	assert.NoError(t, errByUUID)
+	assert.Equal(t, [][]dbus.ObjectPath{
+		{homePath, testWifiDevicePath, "/"},
+		{homePath, testWifiDevicePath, "/"},
+	}, activations.Calls())
+	// updating the profile requires its network, which is looked up by the SSID of the profile
+	assert.ErrorIs(t, errWithPassword, ErrNetworkNotFound)
+	assert.ErrorContains(t, errWithPassword, "FRITZ!Box 7590")
+	// VPN profiles are not WiFi networks
+	assert.ErrorIs(t, errVpn, ErrNetworkNotFound)
+}
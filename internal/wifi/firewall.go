@@ -0,0 +1,424 @@
+package wifi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/markusressel/system-control/internal/util"
+)
+
+const (
+	// FirewallAuto uses nftables if available, iptables otherwise
+	FirewallAuto     = "auto"
+	FirewallNftables = "nftables"
+	FirewallIptables = "iptables"
+
+	nftTable        = "system_control"
+	nftClientsChain = "hotspot_clients"
+	iptablesChain   = "SYSTEM-CONTROL-HOTSPOT"
+
+	firewallTagPrefix = "system-control"
+
+	// ipv6InterfacesPath only exists if IPv6 is enabled
+	ipv6InterfacesPath = "/proc/net/if_inet6"
+)
+
+// Firewall manages the rules for hotspot clients.
+// All rules live in a dedicated table or chain and are tagged with a comment, so they can be
+// found again and are never inserted twice.
+// NOTE: requires root privileges
+type Firewall interface {
+	// Block drops all traffic from the device with the given MAC address
+	Block(mac string) error
+	// Unblock removes the rules added by Block
+	Unblock(mac string) error
+	// Limit limits the download and upload bandwidth (in kbit/s) of the given IPv4 address,
+	// a limit of 0 removes the respective limit
+	Limit(ip string, downKbit int, upKbit int) error
+}
+
+// NewFirewall returns the firewall backend with the given name, one of FirewallAuto, FirewallNftables
+// or FirewallIptables
+func NewFirewall(name string) (Firewall, error) {
+	switch name {
+	case FirewallAuto, "":
+		if _, err := exec.LookPath("nft"); err == nil {
+			return &nftablesFirewall{}, nil
+		}
+		if _, err := exec.LookPath("iptables"); err == nil {
+			return &iptablesFirewall{}, nil
+		}
+		return nil, errors.New("neither nft nor iptables found")
+	case FirewallNftables:
+		return &nftablesFirewall{}, nil
+	case FirewallIptables:
+		return &iptablesFirewall{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported firewall: %s", name)
+	}
+}
+
+// BlockHost blocks all network traffic of the device with the given MAC address.
+// Blocking by MAC address keeps the device blocked even if it is assigned a different IP address.
+// NOTE: requires root privileges
+func BlockHost(firewallName string, mac string) error {
+	firewall, err := NewFirewall(firewallName)
+	if err != nil {
+		return err
+	}
+	return firewall.Block(mac)
+}
+
+// UnblockHost allows network traffic of the device with the given MAC address again.
+// NOTE: requires root privileges
+func UnblockHost(firewallName string, mac string) error {
+	firewall, err := NewFirewall(firewallName)
+	if err != nil {
+		return err
+	}
+	return firewall.Unblock(mac)
+}
+
+// LimitHost limits the download and upload bandwidth (in kbit/s) of the given IPv4 address.
+// A limit of 0 removes the respective limit.
+// NOTE: requires root privileges
+func LimitHost(firewallName string, ip string, downKbit int, upKbit int) error {
+	firewall, err := NewFirewall(firewallName)
+	if err != nil {
+		return err
+	}
+	return firewall.Limit(ip, downKbit, upKbit)
+}
+
+func blockTag(mac string) string {
+	return fmt.Sprintf("%s-block-%s", firewallTagPrefix, strings.ToLower(mac))
+}
+
+func limitTag(ip string) string {
+	return fmt.Sprintf("%s-limit-%s", firewallTagPrefix, ip)
+}
+
+// kbitToKbyte converts a rate in kbit/s to kbyte/s, which is the unit used by the firewall rate limits
+func kbitToKbyte(kbit int) int {
+	return max(1, kbit/8)
+}
+
+func validateMAC(mac string) error {
+	_, err := net.ParseMAC(mac)
+	if err != nil {
+		return fmt.Errorf("invalid MAC address: %s", mac)
+	}
+	return nil
+}
+
+func parseIPv4(ip string) (net.IP, error) {
+	parsed := net.ParseIP(ip).To4()
+	if parsed == nil {
+		return nil, fmt.Errorf("invalid IPv4 address: %s", ip)
+	}
+	return parsed, nil
+}
+
+// commandSucceeds runs the given command and returns true if it exits successfully, without printing any output
+func commandSucceeds(command string, args ...string) bool {
+	return exec.Command(command, args...).Run() == nil
+}
+
+type nftablesFirewall struct{}
+
+func (f *nftablesFirewall) Block(mac string) error {
+	if err := validateMAC(mac); err != nil {
+		return err
+	}
+	err := f.ensureTable()
+	if err != nil {
+		return err
+	}
+
+	tag := blockTag(mac)
+	handles, err := f.ruleHandles(tag)
+	if err != nil || len(handles) > 0 {
+		return err
+	}
+	return f.addRule("ether", "saddr", strings.ToLower(mac), "drop", "comment", quote(tag))
+}
+
+func (f *nftablesFirewall) Unblock(mac string) error {
+	if err := validateMAC(mac); err != nil {
+		return err
+	}
+	return f.deleteRules(blockTag(mac))
+}
+
+func (f *nftablesFirewall) Limit(ip string, downKbit int, upKbit int) error {
+	if _, err := parseIPv4(ip); err != nil {
+		return err
+	}
+	err := f.ensureTable()
+	if err != nil {
+		return err
+	}
+
+	tag := limitTag(ip)
+	err = f.deleteRules(tag)
+	if err != nil {
+		return err
+	}
+	if downKbit > 0 {
+		err = f.addRule("ip", "daddr", ip, "limit", "rate", "over", fmt.Sprintf("%d", kbitToKbyte(downKbit)), "kbytes/second", "drop", "comment", quote(tag))
+		if err != nil {
+			return err
+		}
+	}
+	if upKbit > 0 {
+		err = f.addRule("ip", "saddr", ip, "limit", "rate", "over", fmt.Sprintf("%d", kbitToKbyte(upKbit)), "kbytes/second", "drop", "comment", quote(tag))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureTable creates the table and chains used for the hotspot client rules, if they do not exist yet
+func (f *nftablesFirewall) ensureTable() error {
+	if commandSucceeds("nft", "list", "table", "inet", nftTable) {
+		return nil
+	}
+
+	commands := [][]string{
+		{"add", "table", "inet", nftTable},
+		{"add", "chain", "inet", nftTable, nftClientsChain},
+	}
+	for _, hook := range []string{"input", "forward"} {
+		commands = append(commands,
+			[]string{"add", "chain", "inet", nftTable, hook, "{", "type", "filter", "hook", hook, "priority", "0", ";", "policy", "accept", ";", "}"},
+			[]string{"add", "rule", "inet", nftTable, hook, "jump", nftClientsChain},
+		)
+	}
+	for _, command := range commands {
+		_, err := util.ExecCommand("nft", command...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *nftablesFirewall) addRule(rule ...string) error {
+	_, err := util.ExecCommand("nft", append([]string{"add", "rule", "inet", nftTable, nftClientsChain}, rule...)...)
+	return err
+}
+
+func (f *nftablesFirewall) ruleHandles(tag string) ([]string, error) {
+	if !commandSucceeds("nft", "list", "table", "inet", nftTable) {
+		return nil, nil
+	}
+	output, err := util.ExecCommand("nft", "-a", "list", "chain", "inet", nftTable, nftClientsChain)
+	if err != nil {
+		return nil, err
+	}
+	return parseNftRuleHandles(output, tag), nil
+}
+
+func (f *nftablesFirewall) deleteRules(tag string) error {
+	handles, err := f.ruleHandles(tag)
+	if err != nil {
+		return err
+	}
+	for _, handle := range handles {
+		_, err := util.ExecCommand("nft", "delete", "rule", "inet", nftTable, nftClientsChain, "handle", handle)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseNftRuleHandles returns the handles of all rules with the given comment in the output of "nft -a list chain"
+func parseNftRuleHandles(output string, tag string) []string {
+	var result []string
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.Contains(line, "comment "+quote(tag)+" ") {
+			continue
+		}
+		_, handle, found := strings.Cut(line, "# handle ")
+		if found {
+			result = append(result, strings.TrimSpace(handle))
+		}
+	}
+	return result
+}
+
+func quote(value string) string {
+	return `"` + value + `"`
+}
+
+type iptablesFirewall struct{}
+
+func (f *iptablesFirewall) Block(mac string) error {
+	if err := validateMAC(mac); err != nil {
+		return err
+	}
+	commands, err := blockCommands()
+	if err != nil {
+		return err
+	}
+
+	rule := []string{iptablesChain, "-m", "mac", "--mac-source", strings.ToLower(mac), "-m", "comment", "--comment", blockTag(mac), "-j", "DROP"}
+	for _, command := range commands {
+		err := f.ensureChain(command)
+		if err != nil {
+			return err
+		}
+		if commandSucceeds(command, append([]string{"-C"}, rule...)...) {
+			continue
+		}
+		_, err = util.ExecCommand(command, append([]string{"-A"}, rule...)...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *iptablesFirewall) Unblock(mac string) error {
+	if err := validateMAC(mac); err != nil {
+		return err
+	}
+	commands, err := blockCommands()
+	if err != nil {
+		return err
+	}
+	for _, command := range commands {
+		err := f.deleteRules(command, blockTag(mac))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockCommands returns the commands used to block clients, which includes ip6tables unless IPv6 is disabled,
+// because the rules of iptables only apply to IPv4
+func blockCommands() ([]string, error) {
+	if _, err := os.Stat(ipv6InterfacesPath); err != nil {
+		return []string{"iptables"}, nil
+	}
+	if _, err := exec.LookPath("ip6tables"); err != nil {
+		return nil, errors.New("ip6tables not found, blocked clients would still be able to use IPv6")
+	}
+	return []string{"iptables", "ip6tables"}, nil
+}
+
+func (f *iptablesFirewall) Limit(ip string, downKbit int, upKbit int) error {
+	parsed, err := parseIPv4(ip)
+	if err != nil {
+		return err
+	}
+	err = f.ensureChain("iptables")
+	if err != nil {
+		return err
+	}
+
+	tag := limitTag(ip)
+	err = f.deleteRules("iptables", tag)
+	if err != nil {
+		return err
+	}
+
+	// hashlimit names are limited to 15 characters
+	name := fmt.Sprintf("sc%08x", binary.BigEndian.Uint32(parsed))
+	limits := []struct {
+		kbit      int
+		direction string
+		suffix    string
+		mode      string
+	}{
+		{downKbit, "-d", "d", "dstip"},
+		{upKbit, "-s", "u", "srcip"},
+	}
+	for _, limit := range limits {
+		if limit.kbit <= 0 {
+			continue
+		}
+		_, err = util.ExecCommand("iptables",
+			"-A", iptablesChain,
+			limit.direction, ip,
+			"-m", "hashlimit",
+			"--hashlimit-name", name+limit.suffix,
+			"--hashlimit-mode", limit.mode,
+			"--hashlimit-above", fmt.Sprintf("%dkb/s", kbitToKbyte(limit.kbit)),
+			"-m", "comment", "--comment", tag,
+			"-j", "DROP",
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureChain creates the chain used for the hotspot client rules and jumps to it from INPUT and FORWARD,
+// if this has not been done yet, using the given command (iptables or ip6tables)
+func (f *iptablesFirewall) ensureChain(command string) error {
+	if !commandSucceeds(command, "-n", "-L", iptablesChain) {
+		_, err := util.ExecCommand(command, "-N", iptablesChain)
+		if err != nil {
+			return err
+		}
+	}
+	for _, parent := range []string{"INPUT", "FORWARD"} {
+		if commandSucceeds(command, "-C", parent, "-j", iptablesChain) {
+			continue
+		}
+		_, err := util.ExecCommand(command, "-I", parent, "-j", iptablesChain)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *iptablesFirewall) deleteRules(command string, tag string) error {
+	if !commandSucceeds(command, "-n", "-L", iptablesChain) {
+		return nil
+	}
+	output, err := util.ExecCommand(command, "-S", iptablesChain)
+	if err != nil {
+		return err
+	}
+	for _, rule := range parseIptablesRules(output, tag) {
+		_, err := util.ExecCommand(command, append([]string{"-D"}, rule...)...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseIptablesRules returns the rule specifications (without the leading "-A") of all rules with
+// the given comment in the output of "iptables -S"
+func parseIptablesRules(output string, tag string) [][]string {
+	var result [][]string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "-A" {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.Trim(fields[i], `"`)
+		}
+		for i := 1; i < len(fields); i++ {
+			if fields[i-1] == "--comment" && fields[i] == tag {
+				result = append(result, fields[1:])
+				break
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package wifi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNftRuleHandles(t *testing.T) {
+	// GIVEN
+	output := `table inet system_control {
+	chain hotspot_clients { # handle 1
+		ether saddr 04:f0:21:32:3f:d2 drop comment "system-control-block-04:f0:21:32:3f:d2" # handle 4
+		ip daddr 10.42.0.23 limit rate over 125 kbytes/second drop comment "system-control-limit-10.42.0.23" # handle 5
+		ip saddr 10.42.0.23 limit rate over 64 kbytes/second drop comment "system-control-limit-10.42.0.23" # handle 6
+		ip daddr 10.42.0.2 limit rate over 125 kbytes/second drop comment "system-control-limit-10.42.0.2" # handle 7
+	}
+}`
+
+	// WHEN
+	block := parseNftRuleHandles(output, blockTag("04:F0:21:32:3F:D2"))
+	limit := parseNftRuleHandles(output, limitTag("10.42.0.2"))
+	other := parseNftRuleHandles(output, limitTag("10.42.0.5"))
+
+	// THEN
+	assert.Equal(t, []string{"4"}, block)
+	assert.Equal(t, []string{"7"}, limit)
+	assert.Empty(t, other)
+}
+
+func TestParseIptablesRules(t *testing.T) {
+	// GIVEN
+	output := `-N SYSTEM-CONTROL-HOTSPOT
+-A SYSTEM-CONTROL-HOTSPOT -m mac --mac-source 04:f0:21:32:3f:d2 -m comment --comment system-control-block-04:f0:21:32:3f:d2 -j DROP
+-A SYSTEM-CONTROL-HOTSPOT -d 10.42.0.23/32 -m hashlimit --hashlimit-above 125kb/s --hashlimit-mode dstip --hashlimit-name sc0a2a0017d -m comment --comment "system-control-limit-10.42.0.23" -j DROP
+-A SYSTEM-CONTROL-HOTSPOT -s 10.42.0.23/32 -m hashlimit --hashlimit-above 8kb/s --hashlimit-mode srcip --hashlimit-name sc0a2a0017u -m comment --comment "system-control-limit-10.42.0.23" -j DROP`
+
+	// WHEN
+	block := parseIptablesRules(output, blockTag("04:f0:21:32:3f:d2"))
+	limit := parseIptablesRules(output, limitTag("10.42.0.23"))
+
+	// THEN
+	assert.Equal(t, [][]string{
+		{"SYSTEM-CONTROL-HOTSPOT", "-m", "mac", "--mac-source", "04:f0:21:32:3f:d2", "-m", "comment", "--comment", "system-control-block-04:f0:21:32:3f:d2", "-j", "DROP"},
+	}, block)
+	assert.Len(t, limit, 2)
+	assert.Equal(t, []string{"SYSTEM-CONTROL-HOTSPOT", "-s", "10.42.0.23/32"}, limit[1][:3])
+}
+
+func TestNewFirewall(t *testing.T) {
+	firewall, err := NewFirewall(FirewallNftables)
+	assert.NoError(t, err)
+	assert.IsType(t, &nftablesFirewall{}, firewall)
+
+	firewall, err = NewFirewall(FirewallIptables)
+	assert.NoError(t, err)
+	assert.IsType(t, &iptablesFirewall{}, firewall)
+
+	_, err = NewFirewall("pf")
+	assert.Error(t, err)
+}
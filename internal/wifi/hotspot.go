@@ -1,9 +1,14 @@
 package wifi
 
 import (
+	"crypto/rand"
+	"errors"
 	"fmt"
+	"math/big"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/markusressel/system-control/internal/util"
 )
@@ -120,33 +125,125 @@ end
 
 */
 
-func TurnOnHotspot(
-	name string,
-	wifiInterface string,
-	ssid string,
-	password string,
-) error {
+const (
+	// HotspotSecurityWPA2 uses WPA2-Personal
+	HotspotSecurityWPA2 = "wpa2"
+	// HotspotSecurityWPA3 uses WPA3-Personal (SAE)
+	HotspotSecurityWPA3 = "wpa3"
+	// HotspotSecurityOpen does not use any encryption
+	HotspotSecurityOpen = "open"
+
+	// hotspotPasswordAlphabet omits characters that are easily confused when typed from another screen
+	hotspotPasswordAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	hotspotPasswordLength   = 16
+)
+
+// HotspotOptions contains all settings of a hotspot
+type HotspotOptions struct {
+	// Name is the name of the connection profile
+	Name      string
+	Interface string
+	SSID      string
+	// Password is ignored for HotspotSecurityOpen
+	Password string
+	// Band is one of BandAny, Band2G or Band5G
+	Band string
+	// Channel is ignored if it is 0 or no band is given
+	Channel int
+	// Security is one of HotspotSecurityWPA2, HotspotSecurityWPA3 or HotspotSecurityOpen
+	Security string
+}
+
+// TurnOnHotspot creates and activates a hotspot with the given options
+func TurnOnHotspot(options HotspotOptions) error {
 
 	// TODO: check if hotspot config already exists (by hotspot name)
 	//  if so, we dont need the full command, although settings might change...
 
-	_, err := util.ExecCommand(
-		"nmcli",
+	args := []string{
 		"d",
 		"wifi",
 		"hotspot",
+		"con-name",
+		options.Name,
 		"ifname",
-		wifiInterface,
+		options.Interface,
 		"ssid",
-		ssid,
-		"password",
-		password,
-	)
+		options.SSID,
+	}
+	// open hotspots do not use a password, nmcli generates one which is removed with the security settings below
+	if options.Password != "" {
+		args = append(args, "password", options.Password)
+	}
+	switch options.Band {
+	case Band2G:
+		args = append(args, "band", "bg")
+	case Band5G:
+		args = append(args, "band", "a")
+	case BandAny:
+	default:
+		return fmt.Errorf("unsupported hotspot band: %s", options.Band)
+	}
+	if options.Band != BandAny && options.Channel > 0 {
+		args = append(args, "channel", strconv.Itoa(options.Channel))
+	}
+
+	// nmcli always creates a WPA2 hotspot, other security modes are applied to the profile afterwards
+	var modifyArgs []string
+	switch options.Security {
+	case HotspotSecurityWPA2:
+	case HotspotSecurityWPA3:
+		modifyArgs = []string{"802-11-wireless-security.key-mgmt", "sae"}
+	case HotspotSecurityOpen:
+		modifyArgs = []string{"remove", "802-11-wireless-security"}
+	default:
+		return fmt.Errorf("unsupported hotspot security: %s", options.Security)
+	}
+
+	_, err := util.ExecCommand("nmcli", args...)
 	if err != nil {
 		return err
 	}
 
-	return Connect(name)
+	if len(modifyArgs) > 0 {
+		_, err = util.ExecCommand("nmcli", append([]string{"connection", "modify", options.Name}, modifyArgs...)...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ConnectWithOptions(ConnectOptions{SSID: options.Name, Interface: options.Interface})
+}
+
+// GenerateHotspotPassword returns a new random password suitable for a WPA2/WPA3 hotspot
+func GenerateHotspotPassword() (string, error) {
+	result := make([]byte, hotspotPasswordLength)
+	for i := range result {
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(len(hotspotPasswordAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		result[i] = hotspotPasswordAlphabet[index.Int64()]
+	}
+	return string(result), nil
+}
+
+// FindWifiInterface returns the name of the first WiFi device
+func FindWifiInterface() (string, error) {
+	devices, err := GetNetworkDevices()
+	if err != nil {
+		return "", err
+	}
+	return findWifiInterface(devices)
+}
+
+func findWifiInterface(devices []NetworkDevice) (string, error) {
+	for _, device := range devices {
+		if device.Type == "wifi" {
+			return device.Name, nil
+		}
+	}
+	return "", errors.New("no WiFi device found")
 }
 
 func TurnOffHotspot(name string) error {
@@ -164,6 +261,23 @@ type HotspotLease struct {
 	IP   string
 	Name string
 	MAC  string
+	// Station contains the statistics of the connected client
+	Station StationInfo
+}
+
+// FindHotspotClient returns the client with the given name, MAC or IP address
+func FindHotspotClient(clients []HotspotLease, query string) (HotspotLease, bool) {
+	for _, client := range clients {
+		if strings.EqualFold(client.MAC, query) || client.IP == query {
+			return client, true
+		}
+	}
+	for _, client := range clients {
+		if strings.EqualFold(client.Name, query) {
+			return client, true
+		}
+	}
+	return HotspotLease{}, false
 }
 
 // GetConnectedHotspotDevices returns a list of devices currently connected to the hotspot with the given SSID.
@@ -208,9 +322,10 @@ func GetConnectedHotspotDevices(wifiInterface string, ssid string) ([]HotspotLea
 
 	for _, hotspotLease := range hotspotLeases {
 		matchingStationInfo := util.FilterFunc(stationInfoList, func(e StationInfo) bool {
-			return e.MAC == hotspotLease.MAC
+			return strings.EqualFold(e.MAC, hotspotLease.MAC)
 		})
 		if len(matchingStationInfo) > 0 {
+			hotspotLease.Station = matchingStationInfo[0]
 			result = append(result, hotspotLease)
 		} else {
 			// Did not find matching StationInfo for mac %s, assuming its not connected.
@@ -241,10 +356,30 @@ func GetConnectedHotspotDevices(wifiInterface string, ssid string) ([]HotspotLea
 	return result, nil
 }
 
+// StationInfo contains the statistics of a single station (client) as reported by "iw dev <interface> station dump".
+// Rx and Tx are from the perspective of the hotspot, so RxBytes is the upload of the client.
 type StationInfo struct {
-	MAC        string
-	Interface  string
-	Properties map[string]string
+	MAC       string
+	Interface string
+
+	InactiveTime time.Duration
+	RxBytes      uint64
+	RxPackets    uint64
+	TxBytes      uint64
+	TxPackets    uint64
+	TxRetries    uint64
+	TxFailed     uint64
+	// Signal is the signal strength of the last received packet in dBm
+	Signal int
+	// SignalAvg is the average signal strength in dBm
+	SignalAvg int
+	// TxBitrate and RxBitrate are the bitrates of the last packets in MBit/s
+	TxBitrate     float64
+	RxBitrate     float64
+	Authorized    bool
+	Authenticated bool
+	Associated    bool
+	ConnectedTime time.Duration
 }
 
 // ParseStationDump
@@ -285,27 +420,71 @@ type StationInfo struct {
 func ParseStationDump(output string) []StationInfo {
 	lines := strings.Split(output, "\n")
 
-	var lastStation *StationInfo = nil
 	result := []StationInfo{}
 	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
 		if !strings.HasPrefix(line, "\t") {
-			parsed := parseStationDumpEntryHeader(line)
-			lastStation = &parsed
-			result = append(result, parsed)
-		} else {
-			result[len(result)-1] = parseStationDumpEntryProperty(*lastStation, line)
+			result = append(result, parseStationDumpEntryHeader(line))
+		} else if len(result) > 0 {
+			parseStationDumpEntryProperty(&result[len(result)-1], line)
 		}
 	}
 
 	return result
 }
 
-func parseStationDumpEntryProperty(info StationInfo, line string) StationInfo {
-	parts := strings.Split(line, ":")
-	key := strings.TrimSpace(parts[0])
-	value := strings.TrimSpace(parts[1])
-	info.Properties[key] = value
-	return info
+func parseStationDumpEntryProperty(info *StationInfo, line string) {
+	key, value, found := strings.Cut(line, ":")
+	if !found {
+		return
+	}
+	key = strings.TrimSpace(key)
+	value = strings.TrimSpace(value)
+
+	switch key {
+	case "inactive time":
+		info.InactiveTime = time.Duration(parseLeadingNumber(value)) * time.Millisecond
+	case "rx bytes":
+		info.RxBytes = uint64(parseLeadingNumber(value))
+	case "rx packets":
+		info.RxPackets = uint64(parseLeadingNumber(value))
+	case "tx bytes":
+		info.TxBytes = uint64(parseLeadingNumber(value))
+	case "tx packets":
+		info.TxPackets = uint64(parseLeadingNumber(value))
+	case "tx retries":
+		info.TxRetries = uint64(parseLeadingNumber(value))
+	case "tx failed":
+		info.TxFailed = uint64(parseLeadingNumber(value))
+	case "signal":
+		info.Signal = int(parseLeadingNumber(value))
+	case "signal avg":
+		info.SignalAvg = int(parseLeadingNumber(value))
+	case "tx bitrate":
+		info.TxBitrate = parseLeadingNumber(value)
+	case "rx bitrate":
+		info.RxBitrate = parseLeadingNumber(value)
+	case "authorized":
+		info.Authorized = value == "yes"
+	case "authenticated":
+		info.Authenticated = value == "yes"
+	case "associated":
+		info.Associated = value == "yes"
+	case "connected time":
+		info.ConnectedTime = time.Duration(parseLeadingNumber(value)) * time.Second
+	}
+}
+
+// parseLeadingNumber parses the first field of values like "-60 [-60, -60] dBm", returning 0 if it is not a number
+func parseLeadingNumber(value string) float64 {
+	fields := strings.Fields(value)
+	if len(fields) <= 0 {
+		return 0
+	}
+	result, _ := strconv.ParseFloat(fields[0], 64)
+	return result
 }
 
 func parseStationDumpEntryHeader(line string) StationInfo {
@@ -319,16 +498,32 @@ func parseStationDumpEntryHeader(line string) StationInfo {
 	interfaceNameRegexPattern := "\\(on (.+)\\)"
 	interfaceNameRegex := regexp.MustCompile(interfaceNameRegexPattern)
 
-	interfaceName := interfaceNameRegex.FindString(line)
-	interfaceName = strings.TrimSpace(interfaceName)
+	interfaceName := ""
+	if match := interfaceNameRegex.FindStringSubmatch(line); match != nil {
+		interfaceName = match[1]
+	}
 
 	return StationInfo{
-		MAC:        mac,
-		Interface:  interfaceName,
-		Properties: map[string]string{},
+		MAC:       mac,
+		Interface: interfaceName,
 	}
 }
 
+// Throughput returns the download and upload rate (in bytes per second) of the client between the
+// previous and the current sample. Counter resets (f.ex. on reconnect) result in a rate of 0.
+func Throughput(previous StationInfo, current StationInfo, elapsed time.Duration) (down float64, up float64) {
+	if elapsed <= 0 {
+		return 0, 0
+	}
+	if current.TxBytes >= previous.TxBytes {
+		down = float64(current.TxBytes-previous.TxBytes) / elapsed.Seconds()
+	}
+	if current.RxBytes >= previous.RxBytes {
+		up = float64(current.RxBytes-previous.RxBytes) / elapsed.Seconds()
+	}
+	return down, up
+}
+
 // IsHotspotUp checks if the given hotspot is currently running
 func IsHotspotUp(ssid string) (bool, error) {
 	networkInfo, err := GetNetworks()
@@ -344,71 +539,3 @@ func IsHotspotUp(ssid string) (bool, error) {
 		return networkInfo[0].Connected, nil
 	}
 }
-
-// Block all network traffic for the given host.
-// NOTE: requires root privileges
-func BlockHost(ip string) error {
-	// iptables -I INPUT --source $ip --jump DROP
-	_, err := util.ExecCommand(
-		"iptables",
-		"-I",
-		"INPUT",
-		"--source",
-		ip,
-		"--jump",
-		"DROP",
-	)
-	if err != nil {
-		return err
-	}
-
-	// iptables -I FORWARD --source $ip --jump DROP
-	_, err = util.ExecCommand(
-		"iptables",
-		"-I",
-		"FORWARD",
-		"--source",
-		ip,
-		"--jump",
-		"DROP",
-	)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// Unblock network traffic for the given host.
-// NOTE: requires root privileges
-func UnblockHost(ip string) error {
-	// iptables -D INPUT --source $ip --jump DROP
-	_, err := util.ExecCommand(
-		"iptables",
-		"-D",
-		"INPUT",
-		"--source",
-		ip,
-		"--jump",
-		"DROP",
-	)
-	if err != nil {
-		return err
-	}
-
-	// iptables -D FORWARD --source $ip --jump DROP
-	_, err = util.ExecCommand(
-		"iptables",
-		"-D",
-		"FORWARD",
-		"--source",
-		ip,
-		"--jump",
-		"DROP",
-	)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
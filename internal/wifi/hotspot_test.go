@@ -0,0 +1,136 @@
+package wifi
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateHotspotPassword(t *testing.T) {
+	// WHEN
+	first, err := GenerateHotspotPassword()
+	assert.NoError(t, err)
+	second, err := GenerateHotspotPassword()
+	assert.NoError(t, err)
+
+	// THEN
+	assert.Len(t, first, hotspotPasswordLength)
+	assert.NotEqual(t, first, second)
+	for _, c := range first {
+		assert.True(t, strings.ContainsRune(hotspotPasswordAlphabet, c))
+	}
+}
+
+func TestFindWifiInterface(t *testing.T) {
+	// GIVEN
+	devices := []NetworkDevice{
+		{Name: "eth0", Type: "ethernet"},
+		{Name: "wlan0", Type: "wifi"},
+		{Name: "wlan1", Type: "wifi"},
+	}
+
+	// WHEN
+	result, err := findWifiInterface(devices)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, "wlan0", result)
+
+	_, err = findWifiInterface(devices[:1])
+	assert.Error(t, err)
+}
+
+func TestFindHotspotClient(t *testing.T) {
+	// GIVEN
+	clients := []HotspotLease{
+		{IP: "10.42.0.23", Name: "phone", MAC: "04:f0:21:32:3f:d2"},
+		{IP: "10.42.0.42", Name: "laptop", MAC: "a8:5e:45:01:02:03"},
+	}
+
+	// WHEN
+	byName, foundByName := FindHotspotClient(clients, "Laptop")
+	byMAC, foundByMAC := FindHotspotClient(clients, "04:F0:21:32:3F:D2")
+	byIP, foundByIP := FindHotspotClient(clients, "10.42.0.42")
+	_, foundUnknown := FindHotspotClient(clients, "tablet")
+
+	// THEN
+	assert.True(t, foundByName)
+	assert.Equal(t, "a8:5e:45:01:02:03", byName.MAC)
+	assert.True(t, foundByMAC)
+	assert.Equal(t, "phone", byMAC.Name)
+	assert.True(t, foundByIP)
+	assert.Equal(t, "laptop", byIP.Name)
+	assert.False(t, foundUnknown)
+}
+
+func TestParseStationDump(t *testing.T) {
+	// GIVEN
+	output := "Station 04:f0:21:32:3f:d2 (on wlo1)\n" +
+		"\tinactive time:\t70 ms\n" +
+		"\trx bytes:\t12257886\n" +
+		"\trx packets:\t12577\n" +
+		"\ttx bytes:\t664158\n" +
+		"\ttx packets:\t3441\n" +
+		"\ttx retries:\t976\n" +
+		"\ttx failed:\t0\n" +
+		"\tsignal:  \t-60 [-60, -60] dBm\n" +
+		"\tsignal avg:\t-59 dBm\n" +
+		"\ttx bitrate:\t650.0 MBit/s VHT-MCS 7 80MHz short GI VHT-NSS 2\n" +
+		"\trx bitrate:\t6.0 MBit/s\n" +
+		"\tauthorized:\tyes\n" +
+		"\tauthenticated:\tyes\n" +
+		"\tassociated:\tyes\n" +
+		"\tbeacon interval:100\n" +
+		"\tconnected time:\t233 seconds\n" +
+		"\tassociated at [boottime]:\t20613.215s\n" +
+		"Station a8:5e:45:01:02:03 (on wlo1)\n" +
+		"\trx bytes:\t42\n"
+
+	// WHEN
+	result := ParseStationDump(output)
+
+	// THEN
+	assert.Len(t, result, 2)
+	assert.Equal(t, StationInfo{
+		MAC:           "04:f0:21:32:3f:d2",
+		Interface:     "wlo1",
+		InactiveTime:  70 * time.Millisecond,
+		RxBytes:       12257886,
+		RxPackets:     12577,
+		TxBytes:       664158,
+		TxPackets:     3441,
+		TxRetries:     976,
+		Signal:        -60,
+		SignalAvg:     -59,
+		TxBitrate:     650,
+		RxBitrate:     6,
+		Authorized:    true,
+		Authenticated: true,
+		Associated:    true,
+		ConnectedTime: 233 * time.Second,
+	}, result[0])
+	assert.Equal(t, "a8:5e:45:01:02:03", result[1].MAC)
+	assert.Equal(t, uint64(42), result[1].RxBytes)
+
+	assert.Empty(t, ParseStationDump(""))
+}
+
+func TestThroughput(t *testing.T) {
+	// GIVEN
+	previous := StationInfo{RxBytes: 1000, TxBytes: 10000}
+	current := StationInfo{RxBytes: 3000, TxBytes: 50000}
+
+	// WHEN
+	down, up := Throughput(previous, current, 2*time.Second)
+
+	// THEN
+	assert.Equal(t, 20000.0, down)
+	assert.Equal(t, 1000.0, up)
+
+	// counters are reset when the client reconnects
+	down, up = Throughput(current, previous, 2*time.Second)
+	assert.Zero(t, down)
+	assert.Zero(t, up)
+}
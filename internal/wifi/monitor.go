@@ -0,0 +1,151 @@
+package wifi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	MonitorEventConnected      = "connected"
+	MonitorEventDisconnected   = "disconnected"
+	MonitorEventRoamed         = "roamed"
+	MonitorEventNetworkChanged = "network changed"
+	MonitorEventSignalLow      = "signal low"
+	MonitorEventSignalRestored = "signal restored"
+
+	// signalRecoveryMargin is the margin above the threshold the signal has to reach to count as restored,
+	// to avoid repeated warnings for a signal fluctuating around the threshold
+	signalRecoveryMargin = 5
+)
+
+// MonitorEvent is a change of the WiFi connection detected by a SignalMonitor
+type MonitorEvent struct {
+	Type string
+	Time time.Time
+	// Previous is the network before the event, nil if there was none
+	Previous *WiFiNetwork
+	// Current is the network after the event, nil if there is none
+	Current *WiFiNetwork
+}
+
+func (e MonitorEvent) String() string {
+	switch e.Type {
+	case MonitorEventConnected:
+		return fmt.Sprintf("connected to %s (%s, channel %d)", e.Current.SSID, e.Current.BSSID, e.Current.Channel)
+	case MonitorEventDisconnected:
+		return fmt.Sprintf("disconnected from %s (%s)", e.Previous.SSID, e.Previous.BSSID)
+	case MonitorEventRoamed:
+		return fmt.Sprintf(
+			"roamed within %s from %s (channel %d, signal %d%%) to %s (channel %d, signal %d%%)",
+			e.Current.SSID,
+			e.Previous.BSSID, e.Previous.Channel, e.Previous.Signal,
+			e.Current.BSSID, e.Current.Channel, e.Current.Signal,
+		)
+	case MonitorEventNetworkChanged:
+		return fmt.Sprintf("switched from %s (%s) to %s (%s)", e.Previous.SSID, e.Previous.BSSID, e.Current.SSID, e.Current.BSSID)
+	case MonitorEventSignalLow:
+		return fmt.Sprintf("signal of %s (%s) dropped to %d%%", e.Current.SSID, e.Current.BSSID, e.Current.Signal)
+	case MonitorEventSignalRestored:
+		return fmt.Sprintf("signal of %s (%s) restored to %d%%", e.Current.SSID, e.Current.BSSID, e.Current.Signal)
+	default:
+		return e.Type
+	}
+}
+
+// SignalMonitor detects roaming events and weak signals from successive samples of the connected network
+type SignalMonitor struct {
+	// Threshold is the signal strength (in percent) below which a MonitorEventSignalLow is emitted
+	Threshold int
+
+	last      *WiFiNetwork
+	signalLow bool
+}
+
+// Update processes a new sample of the connected network, nil if disconnected, and returns the detected events
+func (m *SignalMonitor) Update(current *WiFiNetwork, now time.Time) []MonitorEvent {
+	var events []MonitorEvent
+	previous := m.last
+	m.last = current
+
+	switch {
+	case previous == nil && current == nil:
+		return nil
+	case previous == nil:
+		events = append(events, MonitorEvent{Type: MonitorEventConnected, Time: now, Current: current})
+	case current == nil:
+		m.signalLow = false
+		return []MonitorEvent{{Type: MonitorEventDisconnected, Time: now, Previous: previous}}
+	case previous.SSID != current.SSID:
+		events = append(events, MonitorEvent{Type: MonitorEventNetworkChanged, Time: now, Previous: previous, Current: current})
+	case previous.BSSID != current.BSSID:
+		events = append(events, MonitorEvent{Type: MonitorEventRoamed, Time: now, Previous: previous, Current: current})
+	}
+
+	switch {
+	case !m.signalLow && current.Signal < m.Threshold:
+		m.signalLow = true
+		events = append(events, MonitorEvent{Type: MonitorEventSignalLow, Time: now, Previous: previous, Current: current})
+	case m.signalLow && current.Signal >= m.Threshold+signalRecoveryMargin:
+		m.signalLow = false
+		events = append(events, MonitorEvent{Type: MonitorEventSignalRestored, Time: now, Previous: previous, Current: current})
+	}
+
+	return events
+}
+
+// GetActiveNetwork returns the network the given WiFi device is currently connected to, nil if it is not connected.
+// If interfaceName is empty, the first connected WiFi device is used.
+func GetActiveNetwork(interfaceName string) (*WiFiNetwork, error) {
+	nm, err := SystemNetworkManager()
+	if err != nil {
+		return nil, err
+	}
+	return nm.GetActiveNetwork(interfaceName)
+}
+
+// GetActiveNetwork returns the network the given WiFi device is currently connected to, nil if it is not connected.
+// If interfaceName is empty, the first connected WiFi device is used.
+func (nm *NetworkManager) GetActiveNetwork(interfaceName string) (*WiFiNetwork, error) {
+	devicePaths, err := nm.getDevicePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for _, devicePath := range devicePaths {
+		properties, err := nm.getProperties(devicePath, nmDeviceInterface)
+		if err != nil {
+			return nil, err
+		}
+		if variantValue[uint32](properties, "DeviceType") != nmDeviceTypeWifi {
+			continue
+		}
+		if interfaceName != "" && variantValue[string](properties, "Interface") != interfaceName {
+			continue
+		}
+		found = true
+
+		wirelessProperties, err := nm.getProperties(devicePath, nmWirelessInterface)
+		if err != nil {
+			return nil, err
+		}
+		accessPointPath := variantValue[dbus.ObjectPath](wirelessProperties, "ActiveAccessPoint")
+		if !isValidPath(accessPointPath) {
+			continue
+		}
+		accessPointProperties, err := nm.getProperties(accessPointPath, nmAccessPointInterface)
+		if err != nil {
+			// the access point vanished in the meantime
+			continue
+		}
+		network := newWiFiNetwork(accessPointProperties, true)
+		return &network, nil
+	}
+
+	if interfaceName != "" && !found {
+		return nil, fmt.Errorf("WiFi device not found: %s", interfaceName)
+	}
+	return nil, nil
+}
@@ -0,0 +1,60 @@
+package wifi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func eventTypes(events []MonitorEvent) []string {
+	var result []string
+	for _, event := range events {
+		result = append(result, event.Type)
+	}
+	return result
+}
+
+func TestSignalMonitor_Update(t *testing.T) {
+	// GIVEN
+	monitor := SignalMonitor{Threshold: 30}
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	officeA := &WiFiNetwork{SSID: "Office", BSSID: "AA:BB:CC:DD:EE:01", Channel: 36, Signal: 70}
+	officeAWeak := &WiFiNetwork{SSID: "Office", BSSID: "AA:BB:CC:DD:EE:01", Channel: 36, Signal: 20}
+	officeAThreshold := &WiFiNetwork{SSID: "Office", BSSID: "AA:BB:CC:DD:EE:01", Channel: 36, Signal: 32}
+	officeB := &WiFiNetwork{SSID: "Office", BSSID: "AA:BB:CC:DD:EE:02", Channel: 1, Signal: 60}
+	guest := &WiFiNetwork{SSID: "Guest", BSSID: "AA:BB:CC:DD:EE:03", Channel: 6, Signal: 50}
+
+	// WHEN / THEN
+	assert.Empty(t, monitor.Update(nil, now))
+	assert.Equal(t, []string{MonitorEventConnected}, eventTypes(monitor.Update(officeA, now)))
+	assert.Empty(t, monitor.Update(officeA, now))
+	assert.Equal(t, []string{MonitorEventSignalLow}, eventTypes(monitor.Update(officeAWeak, now)))
+	// still below threshold + margin
+	assert.Empty(t, monitor.Update(officeAThreshold, now))
+
+	events := monitor.Update(officeB, now)
+	assert.Equal(t, []string{MonitorEventRoamed, MonitorEventSignalRestored}, eventTypes(events))
+	assert.Equal(t, "roamed within Office from AA:BB:CC:DD:EE:01 (channel 36, signal 32%) to AA:BB:CC:DD:EE:02 (channel 1, signal 60%)", events[0].String())
+
+	assert.Equal(t, []string{MonitorEventNetworkChanged}, eventTypes(monitor.Update(guest, now)))
+	assert.Equal(t, []string{MonitorEventDisconnected}, eventTypes(monitor.Update(nil, now)))
+	assert.Equal(t, []string{MonitorEventConnected, MonitorEventSignalLow}, eventTypes(monitor.Update(officeAWeak, now)))
+}
+
+func TestNetworkManager_GetActiveNetwork(t *testing.T) {
+	// GIVEN
+	nm := startFakeNetworkManager(t)
+
+	// WHEN
+	network, err := nm.GetActiveNetwork("")
+	_, errUnknownDevice := nm.GetActiveNetwork("wlan1")
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, "My Network: 5 GHz", network.SSID)
+	assert.Equal(t, "AA:BB:CC:DD:EE:01", network.BSSID)
+	assert.Equal(t, 36, network.Channel)
+	assert.Equal(t, 87, network.Signal)
+	assert.Error(t, errUnknownDevice)
+}
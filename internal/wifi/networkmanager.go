@@ -0,0 +1,509 @@
+package wifi
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	nmBusName                     = "org.freedesktop.NetworkManager"
+	nmObjectPath                  = "/org/freedesktop/NetworkManager"
+	nmSettingsObjectPath          = "/org/freedesktop/NetworkManager/Settings"
+	nmInterface                   = "org.freedesktop.NetworkManager"
+	nmSettingsInterface           = "org.freedesktop.NetworkManager.Settings"
+	nmSettingsConnectionInterface = "org.freedesktop.NetworkManager.Settings.Connection"
+	nmActiveConnectionInterface   = "org.freedesktop.NetworkManager.Connection.Active"
+	nmDeviceInterface             = "org.freedesktop.NetworkManager.Device"
+	nmWirelessInterface           = "org.freedesktop.NetworkManager.Device.Wireless"
+	nmAccessPointInterface        = "org.freedesktop.NetworkManager.AccessPoint"
+
+	nmDeviceTypeWifi = 2
+
+	// empty value placeholder, as printed by nmcli
+	nmEmptyValue = "--"
+)
+
+// NetworkManager reads network information from NetworkManager via its D-Bus API
+type NetworkManager struct {
+	conn *dbus.Conn
+}
+
+// NewNetworkManager creates a NetworkManager client using the given bus connection
+func NewNetworkManager(conn *dbus.Conn) *NetworkManager {
+	return &NetworkManager{conn: conn}
+}
+
+// SystemNetworkManager creates a NetworkManager client using the system bus
+func SystemNetworkManager() (*NetworkManager, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+	return NewNetworkManager(conn), nil
+}
+
+// isRunning returns true if NetworkManager is connected to the bus
+func (nm *NetworkManager) isRunning() bool {
+	var hasOwner bool
+	err := nm.conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, nmBusName).Store(&hasOwner)
+	return err == nil && hasOwner
+}
+
+// activeConnection is an active connection, referencing the connection profile it was activated from
+type activeConnection struct {
+	Path    dbus.ObjectPath
+	State   uint32
+	Devices []string
+}
+
+// GetConnections returns all connection profiles known to NetworkManager
+func (nm *NetworkManager) GetConnections() ([]Connection, error) {
+	var connectionPaths []dbus.ObjectPath
+	err := nm.conn.Object(nmBusName, nmSettingsObjectPath).Call(nmSettingsInterface+".ListConnections", 0).Store(&connectionPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	activeConnections, err := nm.getActiveConnections()
+	if err != nil {
+		return nil, err
+	}
+
+	connections := make([]Connection, 0, len(connectionPaths))
+	for _, connectionPath := range connectionPaths {
+		var settings map[string]map[string]dbus.Variant
+		err := nm.conn.Object(nmBusName, connectionPath).Call(nmSettingsConnectionInterface+".GetSettings", 0).Store(&settings)
+		if err != nil {
+			return nil, err
+		}
+		properties, err := nm.getProperties(connectionPath, nmSettingsConnectionInterface)
+		if err != nil {
+			return nil, err
+		}
+
+		connectionSettings := settings["connection"]
+		timestamp := variantValue[uint64](connectionSettings, "timestamp")
+		autoconnect := true
+		if _, ok := connectionSettings["autoconnect"]; ok {
+			autoconnect = variantValue[bool](connectionSettings, "autoconnect")
+		}
+
+		connection := Connection{
+			Name:           variantValue[string](connectionSettings, "id"),
+			UUID:           variantValue[string](connectionSettings, "uuid"),
+			Type:           connectionTypeName(variantValue[string](connectionSettings, "type")),
+			Timestamp:      strconv.FormatUint(timestamp, 10),
+			TimestampReal:  formatConnectionTimestamp(timestamp),
+			Autoconnect:    formatYesNo(autoconnect),
+			AutoconnectPri: strconv.Itoa(int(variantValue[int32](connectionSettings, "autoconnect-priority"))),
+			Readonly:       formatYesNo(false),
+			DBUSPath:       string(connectionPath),
+			Active:         formatYesNo(false),
+			Device:         nmEmptyValue,
+			State:          nmEmptyValue,
+			ActivePath:     nmEmptyValue,
+			Slave:          valueOrEmpty(variantValue[string](connectionSettings, "slave-type")),
+			Filename:       valueOrEmpty(variantValue[string](properties, "Filename")),
+		}
+		if active, ok := activeConnections[connectionPath]; ok {
+			connection.Active = formatYesNo(true)
+			connection.Device = valueOrEmpty(strings.Join(active.Devices, ","))
+			connection.State = activeConnectionStateName(active.State)
+			connection.ActivePath = string(active.Path)
+		}
+		connections = append(connections, connection)
+	}
+
+	// same order as nmcli: active connections first, then the most recently used ones
+	slices.SortStableFunc(connections, func(a, b Connection) int {
+		return cmp.Or(
+			-1*cmp.Compare(a.Active, b.Active),
+			-1*cmp.Compare(parseUint(a.Timestamp), parseUint(b.Timestamp)),
+		)
+	})
+
+	return connections, nil
+}
+
+// GetNetworkDevices returns all network devices known to NetworkManager
+func (nm *NetworkManager) GetNetworkDevices() ([]NetworkDevice, error) {
+	devicePaths, err := nm.getDevicePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]NetworkDevice, 0, len(devicePaths))
+	states := map[string]uint32{}
+	for _, devicePath := range devicePaths {
+		properties, err := nm.getProperties(devicePath, nmDeviceInterface)
+		if err != nil {
+			return nil, err
+		}
+
+		state := variantValue[uint32](properties, "State")
+		device := NetworkDevice{
+			Name:            variantValue[string](properties, "Interface"),
+			Type:            deviceTypeName(variantValue[uint32](properties, "DeviceType")),
+			State:           deviceStateName(state),
+			IP4Connectivity: connectivityStateName(variantValue[uint32](properties, "Ip4Connectivity")),
+			IP6Connectivity: connectivityStateName(variantValue[uint32](properties, "Ip6Connectivity")),
+			DBUSPath:        string(devicePath),
+			Connection:      nmEmptyValue,
+			CONUUID:         nmEmptyValue,
+			CONPath:         nmEmptyValue,
+		}
+
+		activeConnectionPath := variantValue[dbus.ObjectPath](properties, "ActiveConnection")
+		if isValidPath(activeConnectionPath) {
+			activeProperties, err := nm.getProperties(activeConnectionPath, nmActiveConnectionInterface)
+			if err != nil {
+				return nil, err
+			}
+			device.Connection = valueOrEmpty(variantValue[string](activeProperties, "Id"))
+			device.CONUUID = valueOrEmpty(variantValue[string](activeProperties, "Uuid"))
+			device.CONPath = string(activeConnectionPath)
+		}
+
+		states[device.DBUSPath] = state
+		devices = append(devices, device)
+	}
+
+	// same order as nmcli: connected devices first
+	slices.SortStableFunc(devices, func(a, b NetworkDevice) int {
+		return cmp.Or(
+			-1*cmp.Compare(states[a.DBUSPath], states[b.DBUSPath]),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+
+	return devices, nil
+}
+
+// GetNetworks returns all WiFi networks visible to any of the WiFi devices
+func (nm *NetworkManager) GetNetworks() ([]WiFiNetwork, error) {
+	devicePaths, err := nm.getDevicePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	networks := make([]WiFiNetwork, 0)
+	for _, devicePath := range devicePaths {
+		deviceProperties, err := nm.getProperties(devicePath, nmDeviceInterface)
+		if err != nil {
+			return nil, err
+		}
+		if variantValue[uint32](deviceProperties, "DeviceType") != nmDeviceTypeWifi {
+			continue
+		}
+
+		wirelessProperties, err := nm.getProperties(devicePath, nmWirelessInterface)
+		if err != nil {
+			return nil, err
+		}
+		activeAccessPoint := variantValue[dbus.ObjectPath](wirelessProperties, "ActiveAccessPoint")
+
+		for _, accessPointPath := range variantValue[[]dbus.ObjectPath](wirelessProperties, "AccessPoints") {
+			properties, err := nm.getProperties(accessPointPath, nmAccessPointInterface)
+			if err != nil {
+				// access points disappear frequently while scanning
+				continue
+			}
+			networks = append(networks, newWiFiNetwork(properties, accessPointPath == activeAccessPoint))
+		}
+	}
+
+	slices.SortStableFunc(networks, func(a, b WiFiNetwork) int {
+		return -1 * cmp.Compare(a.Signal, b.Signal)
+	})
+
+	return networks, nil
+}
+
+func newWiFiNetwork(properties map[string]dbus.Variant, connected bool) WiFiNetwork {
+	frequency := variantValue[uint32](properties, "Frequency")
+	strength := int(variantValue[byte](properties, "Strength"))
+
+	bandwidth := nmEmptyValue
+	if value := variantValue[uint32](properties, "Bandwidth"); value > 0 {
+		bandwidth = fmt.Sprintf("%d MHz", value)
+	}
+
+	return WiFiNetwork{
+		Connected: connected,
+		BSSID:     variantValue[string](properties, "HwAddress"),
+		SSID:      string(variantValue[[]byte](properties, "Ssid")),
+		Mode:      accessPointModeName(variantValue[uint32](properties, "Mode")),
+		Channel:   FrequencyToChannel(int(frequency)),
+		Bandwidth: bandwidth,
+		Frequency: fmt.Sprintf("%d MHz", frequency),
+		Rate:      fmt.Sprintf("%d Mbit/s", variantValue[uint32](properties, "MaxBitrate")/1000),
+		Signal:    strength,
+		Bars:      signalBars(strength),
+		Security: accessPointSecurity(
+			variantValue[uint32](properties, "Flags"),
+			variantValue[uint32](properties, "WpaFlags"),
+			variantValue[uint32](properties, "RsnFlags"),
+		),
+	}
+}
+
+// getActiveConnections returns all active connections, keyed by the path of their connection profile
+func (nm *NetworkManager) getActiveConnections() (map[dbus.ObjectPath]activeConnection, error) {
+	properties, err := nm.getProperties(nmObjectPath, nmInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[dbus.ObjectPath]activeConnection{}
+	for _, activePath := range variantValue[[]dbus.ObjectPath](properties, "ActiveConnections") {
+		activeProperties, err := nm.getProperties(activePath, nmActiveConnectionInterface)
+		if err != nil {
+			return nil, err
+		}
+
+		active := activeConnection{
+			Path:  activePath,
+			State: variantValue[uint32](activeProperties, "State"),
+		}
+		for _, devicePath := range variantValue[[]dbus.ObjectPath](activeProperties, "Devices") {
+			deviceProperties, err := nm.getProperties(devicePath, nmDeviceInterface)
+			if err != nil {
+				return nil, err
+			}
+			active.Devices = append(active.Devices, variantValue[string](deviceProperties, "Interface"))
+		}
+
+		result[variantValue[dbus.ObjectPath](activeProperties, "Connection")] = active
+	}
+	return result, nil
+}
+
+func (nm *NetworkManager) getDevicePaths() ([]dbus.ObjectPath, error) {
+	properties, err := nm.getProperties(nmObjectPath, nmInterface)
+	if err != nil {
+		return nil, err
+	}
+	return variantValue[[]dbus.ObjectPath](properties, "Devices"), nil
+}
+
+func (nm *NetworkManager) getProperties(path dbus.ObjectPath, iface string) (map[string]dbus.Variant, error) {
+	var properties map[string]dbus.Variant
+	err := nm.conn.Object(nmBusName, path).Call("org.freedesktop.DBus.Properties.GetAll", 0, iface).Store(&properties)
+	return properties, err
+}
+
+// variantValue returns the value of the given key, or the zero value if it is missing or has a different type
+func variantValue[T any](values map[string]dbus.Variant, key string) T {
+	var result T
+	variant, ok := values[key]
+	if !ok {
+		return result
+	}
+	if value, ok := variant.Value().(T); ok {
+		return value
+	}
+	return result
+}
+
+// isValidPath returns false for the "/" path NetworkManager uses instead of null references
+func isValidPath(path dbus.ObjectPath) bool {
+	return path.IsValid() && path != "/"
+}
+
+func valueOrEmpty(value string) string {
+	if len(value) <= 0 {
+		return nmEmptyValue
+	}
+	return value
+}
+
+func formatYesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+func parseUint(value string) uint64 {
+	result, _ := strconv.ParseUint(value, 10, 64)
+	return result
+}
+
+func formatConnectionTimestamp(timestamp uint64) string {
+	if timestamp == 0 {
+		return "never"
+	}
+	return time.Unix(int64(timestamp), 0).Format("Mon 02 Jan 2006 03:04:05 PM MST")
+}
+
+// FrequencyToChannel returns the WiFi channel of the given center frequency in MHz, or 0 if unknown
+func FrequencyToChannel(frequency int) int {
+	switch {
+	case frequency == 2484:
+		return 14
+	case frequency >= 2412 && frequency < 2484:
+		return (frequency - 2407) / 5
+	case frequency == 5935:
+		return 2
+	case frequency >= 5955 && frequency <= 7115:
+		return (frequency - 5950) / 5
+	case frequency >= 5000 && frequency < 5935:
+		return (frequency - 5000) / 5
+	default:
+		return 0
+	}
+}
+
+// connectionTypeName returns the short connection type name used by nmcli
+func connectionTypeName(connectionType string) string {
+	switch connectionType {
+	case "802-11-wireless":
+		return "wifi"
+	case "802-3-ethernet":
+		return "ethernet"
+	case "802-11-olpc-mesh":
+		return "olpc-mesh"
+	default:
+		return valueOrEmpty(connectionType)
+	}
+}
+
+var deviceTypeNames = []string{
+	"unknown", "ethernet", "wifi", "unused1", "unused2", "bt", "olpc-mesh", "wimax", "gsm", "infiniband",
+	"bond", "vlan", "adsl", "bridge", "generic", "team", "tun", "ip-tunnel", "macvlan", "vxlan",
+	"veth", "macsec", "dummy", "ppp", "ovs-interface", "ovs-port", "ovs-bridge", "wpan", "6lowpan", "wireguard",
+	"wifi-p2p", "vrf", "loopback", "hsr",
+}
+
+func deviceTypeName(deviceType uint32) string {
+	if int(deviceType) < len(deviceTypeNames) {
+		return deviceTypeNames[deviceType]
+	}
+	return "unknown"
+}
+
+var deviceStateNames = map[uint32]string{
+	10:  "unmanaged",
+	20:  "unavailable",
+	30:  "disconnected",
+	40:  "connecting (prepare)",
+	50:  "connecting (configuring)",
+	60:  "connecting (need authentication)",
+	70:  "connecting (getting IP configuration)",
+	80:  "connecting (checking IP connectivity)",
+	90:  "connecting (starting secondary connections)",
+	100: "connected",
+	110: "deactivating",
+	120: "connection failed",
+}
+
+func deviceStateName(state uint32) string {
+	if name, ok := deviceStateNames[state]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+func activeConnectionStateName(state uint32) string {
+	switch state {
+	case 1:
+		return "activating"
+	case 2:
+		return "activated"
+	case 3:
+		return "deactivating"
+	case 4:
+		return "deactivated"
+	default:
+		return "unknown"
+	}
+}
+
+func connectivityStateName(state uint32) string {
+	switch state {
+	case 1:
+		return "none"
+	case 2:
+		return "portal"
+	case 3:
+		return "limited"
+	case 4:
+		return "full"
+	default:
+		return "unknown"
+	}
+}
+
+func accessPointModeName(mode uint32) string {
+	switch mode {
+	case 1:
+		return "Ad-Hoc"
+	case 2:
+		return "Infra"
+	case 3:
+		return "AP"
+	case 4:
+		return "Mesh"
+	default:
+		return "N/A"
+	}
+}
+
+// signalBars returns the signal strength visualization used by nmcli
+func signalBars(strength int) string {
+	switch {
+	case strength > 80:
+		return "▂▄▆█"
+	case strength > 55:
+		return "▂▄▆_"
+	case strength > 30:
+		return "▂▄__"
+	case strength > 5:
+		return "▂___"
+	default:
+		return "____"
+	}
+}
+
+const (
+	apFlagPrivacy       = 0x1
+	apSecKeyMgmtPSK     = 0x100
+	apSecKeyMgmt8021X   = 0x200
+	apSecKeyMgmtSAE     = 0x400
+	apSecKeyMgmtOWE     = 0x800
+	apSecKeyMgmtOWETM   = 0x1000
+	apSecKeyMgmtEAPB192 = 0x2000
+)
+
+// accessPointSecurity returns the security description used by nmcli, f.ex. "WPA2 WPA3"
+func accessPointSecurity(flags uint32, wpaFlags uint32, rsnFlags uint32) string {
+	var security []string
+	if flags&apFlagPrivacy != 0 && wpaFlags == 0 && rsnFlags == 0 {
+		security = append(security, "WEP")
+	}
+	if wpaFlags != 0 {
+		security = append(security, "WPA1")
+	}
+	if rsnFlags&(apSecKeyMgmtPSK|apSecKeyMgmt8021X) != 0 {
+		security = append(security, "WPA2")
+	}
+	if rsnFlags&(apSecKeyMgmtSAE|apSecKeyMgmtEAPB192) != 0 {
+		security = append(security, "WPA3")
+	}
+	if rsnFlags&(apSecKeyMgmtOWE|apSecKeyMgmtOWETM) != 0 {
+		security = append(security, "OWE")
+	}
+	if (wpaFlags|rsnFlags)&apSecKeyMgmt8021X != 0 {
+		security = append(security, "802.1X")
+	}
+	if len(security) <= 0 {
+		return nmEmptyValue
+	}
+	return strings.Join(security, " ")
+}
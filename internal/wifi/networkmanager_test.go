@@ -0,0 +1,273 @@
+package wifi
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/markusressel/system-control/internal/dbustest"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testWifiDevicePath     = dbus.ObjectPath("/org/freedesktop/NetworkManager/Devices/3")
+	testEthernetDevicePath = dbus.ObjectPath("/org/freedesktop/NetworkManager/Devices/2")
+	testWifiSettingsPath   = dbus.ObjectPath("/org/freedesktop/NetworkManager/Settings/1")
+	testVpnSettingsPath    = dbus.ObjectPath("/org/freedesktop/NetworkManager/Settings/2")
+	testActivePath         = dbus.ObjectPath("/org/freedesktop/NetworkManager/ActiveConnection/7")
+	testAccessPointPath    = dbus.ObjectPath("/org/freedesktop/NetworkManager/AccessPoint/10")
+	testOpenAccessPoint    = dbus.ObjectPath("/org/freedesktop/NetworkManager/AccessPoint/11")
+)
+
+type fakeSettings struct {
+	connections []dbus.ObjectPath
+}
+
+func (s fakeSettings) ListConnections() ([]dbus.ObjectPath, *dbus.Error) {
+	return s.connections, nil
+}
+
+type fakeSettingsConnection struct {
+	settings map[string]map[string]dbus.Variant
+}
+
+func (c fakeSettingsConnection) GetSettings() (map[string]map[string]dbus.Variant, *dbus.Error) {
+	return c.settings, nil
+}
+
+// startFakeNetworkManager exports a NetworkManager service with a connected WiFi device,
+// an unavailable ethernet device and two connection profiles on a private bus
+func startFakeNetworkManager(t *testing.T) *NetworkManager {
+	nm, _ := startFakeNetworkManagerService(t)
+	return nm
+}
+
+// startFakeNetworkManagerService is like startFakeNetworkManager, but also returns the connection
+// of the service, which can be used to export additional objects
+func startFakeNetworkManagerService(t *testing.T) (*NetworkManager, *dbus.Conn) {
+	address := dbustest.StartBus(t)
+	service := dbustest.Connect(t, address)
+
+	dbustest.ExportProperties(t, service, nmObjectPath, map[string]map[string]any{
+		nmInterface: {
+			"Devices":           []dbus.ObjectPath{testEthernetDevicePath, testWifiDevicePath},
+			"ActiveConnections": []dbus.ObjectPath{testActivePath},
+		},
+	})
+
+	assert.NoError(t, service.Export(fakeSettings{connections: []dbus.ObjectPath{testVpnSettingsPath, testWifiSettingsPath}}, nmSettingsObjectPath, nmSettingsInterface))
+	assert.NoError(t, service.Export(fakeSettingsConnection{settings: map[string]map[string]dbus.Variant{
+		"connection": {
+			"id":        dbus.MakeVariant("My Network: 5 GHz"),
+			"uuid":      dbus.MakeVariant("0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11"),
+			"type":      dbus.MakeVariant("802-11-wireless"),
+			"timestamp": dbus.MakeVariant(uint64(1760000000)),
+		},
+	}}, testWifiSettingsPath, nmSettingsConnectionInterface))
+	dbustest.ExportProperties(t, service, testWifiSettingsPath, map[string]map[string]any{
+		nmSettingsConnectionInterface: {
+			"Filename": "/etc/NetworkManager/system-connections/My Network.nmconnection",
+		},
+	})
+	assert.NoError(t, service.Export(fakeSettingsConnection{settings: map[string]map[string]dbus.Variant{
+		"connection": {
+			"id":                   dbus.MakeVariant("Office VPN"),
+			"uuid":                 dbus.MakeVariant("5d1c2a8e-3b7f-4e2d-8c61-7a9e0f4b2c33"),
+			"type":                 dbus.MakeVariant("vpn"),
+			"autoconnect":          dbus.MakeVariant(false),
+			"autoconnect-priority": dbus.MakeVariant(int32(-5)),
+		},
+	}}, testVpnSettingsPath, nmSettingsConnectionInterface))
+	dbustest.ExportProperties(t, service, testVpnSettingsPath, map[string]map[string]any{
+		nmSettingsConnectionInterface: {
+			"Filename": "",
+		},
+	})
+
+	dbustest.ExportProperties(t, service, testActivePath, map[string]map[string]any{
+		nmActiveConnectionInterface: {
+			"Connection": testWifiSettingsPath,
+			"Id":         "My Network: 5 GHz",
+			"Uuid":       "0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11",
+			"State":      uint32(2),
+			"Devices":    []dbus.ObjectPath{testWifiDevicePath},
+		},
+	})
+
+	dbustest.ExportProperties(t, service, testWifiDevicePath, map[string]map[string]any{
+		nmDeviceInterface: {
+			"Interface":        "wlan0",
+			"DeviceType":       uint32(nmDeviceTypeWifi),
+			"State":            uint32(100),
+			"Ip4Connectivity":  uint32(4),
+			"Ip6Connectivity":  uint32(1),
+			"ActiveConnection": testActivePath,
+		},
+		nmWirelessInterface: {
+			"AccessPoints":      []dbus.ObjectPath{testOpenAccessPoint, testAccessPointPath},
+			"ActiveAccessPoint": testAccessPointPath,
+		},
+	})
+	dbustest.ExportProperties(t, service, testEthernetDevicePath, map[string]map[string]any{
+		nmDeviceInterface: {
+			"Interface":        "eth0",
+			"DeviceType":       uint32(1),
+			"State":            uint32(20),
+			"Ip4Connectivity":  uint32(0),
+			"Ip6Connectivity":  uint32(0),
+			"ActiveConnection": dbus.ObjectPath("/"),
+		},
+	})
+
+	dbustest.ExportProperties(t, service, testAccessPointPath, map[string]map[string]any{
+		nmAccessPointInterface: {
+			"Ssid":       []byte("My Network: 5 GHz"),
+			"HwAddress":  "AA:BB:CC:DD:EE:01",
+			"Mode":       uint32(2),
+			"Frequency":  uint32(5180),
+			"MaxBitrate": uint32(540000),
+			"Bandwidth":  uint32(80),
+			"Strength":   byte(87),
+			"Flags":      uint32(apFlagPrivacy),
+			"WpaFlags":   uint32(0),
+			"RsnFlags":   uint32(apSecKeyMgmtPSK | apSecKeyMgmtSAE),
+		},
+	})
+	dbustest.ExportProperties(t, service, testOpenAccessPoint, map[string]map[string]any{
+		nmAccessPointInterface: {
+			"Ssid":       []byte("Café"),
+			"HwAddress":  "AA:BB:CC:DD:EE:02",
+			"Mode":       uint32(2),
+			"Frequency":  uint32(2437),
+			"MaxBitrate": uint32(54000),
+			"Strength":   byte(40),
+			"Flags":      uint32(0),
+			"WpaFlags":   uint32(0),
+			"RsnFlags":   uint32(0),
+		},
+	})
+
+	_, err := service.RequestName(nmBusName, dbus.NameFlagDoNotQueue)
+	assert.NoError(t, err)
+
+	return NewNetworkManager(dbustest.Connect(t, address)), service
+}
+
+func TestNetworkManager_GetConnections(t *testing.T) {
+	// GIVEN
+	nm := startFakeNetworkManager(t)
+
+	// WHEN
+	connections, err := nm.GetConnections()
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Len(t, connections, 2)
+
+	wifiConnection := connections[0]
+	assert.Equal(t, "My Network: 5 GHz", wifiConnection.Name)
+	assert.Equal(t, "wifi", wifiConnection.Type)
+	assert.Equal(t, "1760000000", wifiConnection.Timestamp)
+	assert.Equal(t, "yes", wifiConnection.Autoconnect)
+	assert.Equal(t, "0", wifiConnection.AutoconnectPri)
+	assert.Equal(t, "yes", wifiConnection.Active)
+	assert.Equal(t, "wlan0", wifiConnection.Device)
+	assert.Equal(t, "activated", wifiConnection.State)
+	assert.Equal(t, string(testActivePath), wifiConnection.ActivePath)
+	assert.Equal(t, "/etc/NetworkManager/system-connections/My Network.nmconnection", wifiConnection.Filename)
+
+	vpnConnection := connections[1]
+	assert.Equal(t, "Office VPN", vpnConnection.Name)
+	assert.Equal(t, "vpn", vpnConnection.Type)
+	assert.Equal(t, "never", vpnConnection.TimestampReal)
+	assert.Equal(t, "no", vpnConnection.Autoconnect)
+	assert.Equal(t, "-5", vpnConnection.AutoconnectPri)
+	assert.Equal(t, "no", vpnConnection.Active)
+	assert.Equal(t, "--", vpnConnection.Device)
+	assert.Equal(t, "--", vpnConnection.Filename)
+}
+
+func TestNetworkManager_GetNetworkDevices(t *testing.T) {
+	// GIVEN
+	nm := startFakeNetworkManager(t)
+
+	// WHEN
+	devices, err := nm.GetNetworkDevices()
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, []NetworkDevice{
+		{
+			Name:            "wlan0",
+			Type:            "wifi",
+			State:           "connected",
+			IP4Connectivity: "full",
+			IP6Connectivity: "none",
+			DBUSPath:        string(testWifiDevicePath),
+			Connection:      "My Network: 5 GHz",
+			CONUUID:         "0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11",
+			CONPath:         string(testActivePath),
+		},
+		{
+			Name:            "eth0",
+			Type:            "ethernet",
+			State:           "unavailable",
+			IP4Connectivity: "unknown",
+			IP6Connectivity: "unknown",
+			DBUSPath:        string(testEthernetDevicePath),
+			Connection:      "--",
+			CONUUID:         "--",
+			CONPath:         "--",
+		},
+	}, devices)
+}
+
+func TestNetworkManager_GetNetworks(t *testing.T) {
+	// GIVEN
+	nm := startFakeNetworkManager(t)
+
+	// WHEN
+	networks, err := nm.GetNetworks()
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, []WiFiNetwork{
+		{
+			Connected: true,
+			BSSID:     "AA:BB:CC:DD:EE:01",
+			SSID:      "My Network: 5 GHz",
+			Mode:      "Infra",
+			Channel:   36,
+			Bandwidth: "80 MHz",
+			Frequency: "5180 MHz",
+			Rate:      "540 Mbit/s",
+			Signal:    87,
+			Bars:      "▂▄▆█",
+			Security:  "WPA2 WPA3",
+		},
+		{
+			Connected: false,
+			BSSID:     "AA:BB:CC:DD:EE:02",
+			SSID:      "Café",
+			Mode:      "Infra",
+			Channel:   6,
+			Bandwidth: "--",
+			Frequency: "2437 MHz",
+			Rate:      "54 Mbit/s",
+			Signal:    40,
+			Bars:      "▂▄__",
+			Security:  "--",
+		},
+	}, networks)
+}
+
+func TestFrequencyToChannel(t *testing.T) {
+	assert.Equal(t, 1, FrequencyToChannel(2412))
+	assert.Equal(t, 13, FrequencyToChannel(2472))
+	assert.Equal(t, 14, FrequencyToChannel(2484))
+	assert.Equal(t, 36, FrequencyToChannel(5180))
+	assert.Equal(t, 165, FrequencyToChannel(5825))
+	assert.Equal(t, 2, FrequencyToChannel(5935))
+	assert.Equal(t, 1, FrequencyToChannel(5955))
+	assert.Equal(t, 233, FrequencyToChannel(7115))
+	assert.Equal(t, 0, FrequencyToChannel(900))
+}
@@ -1,6 +1,7 @@
 package wifi
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 
@@ -53,56 +54,45 @@ type Connection struct {
 	Filename       string // FILENAME
 }
 
-// Connect to a known WiFi network
-func Connect(name string) error {
-	//networks, err := GetNetworks()
-	//if err != nil {
-	//	return err
-	//}
-
-	//knownNetwork := false
-	//for _, network := range networks {
-	//	if network.SSID == name {
-	//		knownNetwork = true
-	//		if network.Connected {
-	//			return errors.New("already connected")
-	//		}
-	//		break
-	//	}
-	//}
+// connectWithNmcli connects to a WiFi network using nmcli, used if NetworkManager is not reachable via D-Bus.
+// Like ConnectWifi, an existing WiFi connection profile with the given name (or UUID) is activated if no credentials are given.
+func connectWithNmcli(options ConnectOptions) error {
+	if options.Timeout <= 0 {
+		options.Timeout = activationTimeout
+	}
+	if options.Enterprise != nil || options.Band != BandAny {
+		return errors.New("enterprise authentication and bands require access to NetworkManager via D-Bus")
+	}
+	wait := strconv.Itoa(int(options.Timeout.Seconds()))
 
-	connections, err := GetConnections()
+	connections, err := getConnectionsFromNmcli()
 	if err != nil {
 		return err
 	}
-	knownConnection := false
-	for _, connection := range connections {
-		if connection.Name == name {
-			knownConnection = true
-			break
+	connection, err := findConnection(FilterWifiConnections(connections), options.SSID)
+	if err == nil && options.Password == "" && !options.Hidden && options.BSSID == "" {
+		args := []string{"--wait", wait, "connection", "up", connection.UUID}
+		if options.Interface != "" {
+			args = append(args, "ifname", options.Interface)
 		}
+		_, err = util.ExecCommand("nmcli", args...)
+		return err
 	}
 
-	switch {
-	case knownConnection:
-		_, err = util.ExecCommand(
-			"nmcli",
-			"connection",
-			"up",
-			name,
-			"--ask",
-		)
-	default:
-		_, err = util.ExecCommand(
-			"nmcli",
-			"device",
-			"wifi",
-			"connect",
-			name,
-			"--ask",
-		)
+	args := []string{"--wait", wait, "device", "wifi", "connect", options.SSID}
+	if options.Password != "" {
+		args = append(args, "password", options.Password)
 	}
-
+	if options.Interface != "" {
+		args = append(args, "ifname", options.Interface)
+	}
+	if options.BSSID != "" {
+		args = append(args, "bssid", options.BSSID)
+	}
+	if options.Hidden {
+		args = append(args, "hidden", "yes")
+	}
+	_, err = util.ExecCommand("nmcli", args...)
 	return err
 }
 
@@ -126,7 +116,20 @@ func Disconnect() error {
 	return err
 }
 
+// GetConnections returns all known connection profiles.
+// NetworkManager is queried via D-Bus, with nmcli as a fallback.
 func GetConnections() ([]Connection, error) {
+	nm, err := SystemNetworkManager()
+	if err == nil {
+		connections, err := nm.GetConnections()
+		if err == nil {
+			return connections, nil
+		}
+	}
+	return getConnectionsFromNmcli()
+}
+
+func getConnectionsFromNmcli() ([]Connection, error) {
 	output, err := util.ExecCommand(
 		"nmcli",
 		"-f",
@@ -164,7 +167,20 @@ func GetConnections() ([]Connection, error) {
 	return connections, err
 }
 
+// GetNetworkDevices returns all known network devices.
+// NetworkManager is queried via D-Bus, with nmcli as a fallback.
 func GetNetworkDevices() ([]NetworkDevice, error) {
+	nm, err := SystemNetworkManager()
+	if err == nil {
+		devices, err := nm.GetNetworkDevices()
+		if err == nil {
+			return devices, nil
+		}
+	}
+	return getNetworkDevicesFromNmcli()
+}
+
+func getNetworkDevicesFromNmcli() ([]NetworkDevice, error) {
 	output, err := util.ExecCommand(
 		"nmcli",
 		"-f",
@@ -195,8 +211,20 @@ func GetNetworkDevices() ([]NetworkDevice, error) {
 	return devices, err
 }
 
-// GetNetworks returns a list of all known WiFi networks
+// GetNetworks returns a list of all known WiFi networks.
+// NetworkManager is queried via D-Bus, with nmcli as a fallback.
 func GetNetworks() ([]WiFiNetwork, error) {
+	nm, err := SystemNetworkManager()
+	if err == nil {
+		networks, err := nm.GetNetworks()
+		if err == nil {
+			return networks, nil
+		}
+	}
+	return getNetworksFromNmcli()
+}
+
+func getNetworksFromNmcli() ([]WiFiNetwork, error) {
 	output, err := util.ExecCommand(
 		"nmcli",
 		"-f",
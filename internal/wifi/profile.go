@@ -0,0 +1,326 @@
+package wifi
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrConnectionNotFound is returned if there is no connection profile with the given name or UUID
+	ErrConnectionNotFound = errors.New("connection not found")
+	// ErrConnectionExists is returned when importing a connection profile with the name of an existing one
+	ErrConnectionExists = errors.New("connection already exists")
+)
+
+const (
+	// WifiModeInfrastructure is the mode of profiles which connect to an access point
+	WifiModeInfrastructure = "infrastructure"
+	// WifiModeAP is the mode of hotspot profiles
+	WifiModeAP = "ap"
+)
+
+// wifiModes are all supported values of ConnectionProfile.Mode
+var wifiModes = []string{"", WifiModeInfrastructure, WifiModeAP, "adhoc", "mesh"}
+
+// keyMgmts are all supported values of ConnectionProfile.Security
+var keyMgmts = []string{keyMgmtNone, keyMgmtWEP, keyMgmtPSK, keyMgmtSAE, keyMgmtOWE, keyMgmtEAP, keyMgmtEAPSuiteB}
+
+// secretSettings are the settings that may contain secrets, which are not returned by GetSettings
+var secretSettings = []string{"802-11-wireless-security", "802-1x"}
+
+// ConnectionProfile is the portable representation of a WiFi connection profile, used for export and import.
+// It does not contain any machine specific values like UUIDs or device bindings.
+type ConnectionProfile struct {
+	Name   string `yaml:"name"`
+	SSID   string `yaml:"ssid"`
+	Hidden bool   `yaml:"hidden,omitempty"`
+	BSSID  string `yaml:"bssid,omitempty"`
+	Band   string `yaml:"band,omitempty"`
+	// Mode is the WiFi mode, f.ex. "ap" for hotspots, empty for profiles which connect to an access point
+	Mode                string `yaml:"mode,omitempty"`
+	Autoconnect         bool   `yaml:"autoconnect"`
+	AutoconnectPriority int32  `yaml:"autoconnectPriority"`
+	// Security is the key management of the network, f.ex. "wpa-psk", "sae" or "wpa-eap", empty for open networks
+	Security   string             `yaml:"security,omitempty"`
+	Password   string             `yaml:"password,omitempty"`
+	Enterprise *EnterpriseOptions `yaml:"enterprise,omitempty"`
+}
+
+// ConnectionProfiles is the root element of the export file format
+type ConnectionProfiles struct {
+	Connections []ConnectionProfile `yaml:"connections"`
+}
+
+// FindConnection returns the connection profile with the given name or UUID
+func FindConnection(nameOrUUID string) (Connection, error) {
+	connections, err := GetConnections()
+	if err != nil {
+		return Connection{}, err
+	}
+	return findConnection(connections, nameOrUUID)
+}
+
+func findConnection(connections []Connection, nameOrUUID string) (Connection, error) {
+	for _, connection := range connections {
+		if connection.UUID == nameOrUUID {
+			return connection, nil
+		}
+	}
+	for _, connection := range connections {
+		if connection.Name == nameOrUUID {
+			return connection, nil
+		}
+	}
+	return Connection{}, fmt.Errorf("%w: %s", ErrConnectionNotFound, nameOrUUID)
+}
+
+// DeleteConnection deletes the given connection profile
+func DeleteConnection(connection Connection) error {
+	nm, err := SystemNetworkManager()
+	if err != nil {
+		return err
+	}
+	return nm.DeleteConnection(dbus.ObjectPath(connection.DBUSPath))
+}
+
+// RenameConnection changes the name of the given connection profile
+func RenameConnection(connection Connection, name string) error {
+	return updateConnectionSettings(connection, func(settings map[string]map[string]dbus.Variant) {
+		settings["connection"]["id"] = dbus.MakeVariant(name)
+	})
+}
+
+// SetConnectionAutoconnect enables or disables automatically connecting the given connection profile
+func SetConnectionAutoconnect(connection Connection, autoconnect bool) error {
+	return updateConnectionSettings(connection, func(settings map[string]map[string]dbus.Variant) {
+		settings["connection"]["autoconnect"] = dbus.MakeVariant(autoconnect)
+	})
+}
+
+// SetConnectionPriority sets the autoconnect priority of the given connection profile.
+// Profiles with a higher priority are preferred if multiple profiles are available.
+func SetConnectionPriority(connection Connection, priority int32) error {
+	return updateConnectionSettings(connection, func(settings map[string]map[string]dbus.Variant) {
+		settings["connection"]["autoconnect-priority"] = dbus.MakeVariant(priority)
+	})
+}
+
+// GetConnectionSettings returns all settings of the given connection profile, grouped by setting name
+func GetConnectionSettings(connection Connection, withSecrets bool) (map[string]map[string]dbus.Variant, error) {
+	nm, err := SystemNetworkManager()
+	if err != nil {
+		return nil, err
+	}
+	return nm.GetConnectionSettings(dbus.ObjectPath(connection.DBUSPath), withSecrets)
+}
+
+// ExportConnections converts the given WiFi connection profiles to their portable representation.
+// An error is returned if any of the given connections is not a WiFi connection, see FilterWifiConnections.
+func ExportConnections(connections []Connection, withSecrets bool) (ConnectionProfiles, error) {
+	result := ConnectionProfiles{Connections: []ConnectionProfile{}}
+	nm, err := SystemNetworkManager()
+	if err != nil {
+		return result, err
+	}
+
+	for _, connection := range connections {
+		if connection.Type != "wifi" {
+			return result, fmt.Errorf("%s is not a WiFi connection", connection.Name)
+		}
+		settings, err := nm.GetConnectionSettings(dbus.ObjectPath(connection.DBUSPath), withSecrets)
+		if err != nil {
+			return result, err
+		}
+		result.Connections = append(result.Connections, profileFromSettings(settings))
+	}
+	return result, nil
+}
+
+// ImportConnectionProfile creates a connection profile from its portable representation.
+// If a profile with the same name exists, it is updated if replace is true, otherwise an error is returned.
+func ImportConnectionProfile(profile ConnectionProfile, replace bool) error {
+	nm, err := SystemNetworkManager()
+	if err != nil {
+		return err
+	}
+
+	existingPath, existingUUID, err := nm.findConnectionByName(profile.Name)
+	if err != nil {
+		return err
+	}
+	if existingPath != "" && !replace {
+		return fmt.Errorf("%w: %s", ErrConnectionExists, profile.Name)
+	}
+
+	connectionUUID := existingUUID
+	if connectionUUID == "" {
+		connectionUUID = uuid.NewString()
+	}
+	settings, err := settingsFromProfile(profile, connectionUUID)
+	if err != nil {
+		return fmt.Errorf("invalid connection %s: %w", profile.Name, err)
+	}
+
+	if existingPath != "" {
+		return nm.conn.Object(nmBusName, existingPath).Call(nmSettingsConnectionInterface+".Update", 0, settings).Err
+	}
+	return nm.conn.Object(nmBusName, nmSettingsObjectPath).Call(nmSettingsInterface+".AddConnection", 0, settings).Err
+}
+
+// updateConnectionSettings applies the given modification to the settings of the given connection profile
+func updateConnectionSettings(connection Connection, modify func(settings map[string]map[string]dbus.Variant)) error {
+	nm, err := SystemNetworkManager()
+	if err != nil {
+		return err
+	}
+
+	path := dbus.ObjectPath(connection.DBUSPath)
+	// secrets have to be included, otherwise they might be removed from the profile
+	settings, err := nm.GetConnectionSettings(path, true)
+	if err != nil {
+		return err
+	}
+	modify(settings)
+	return nm.conn.Object(nmBusName, path).Call(nmSettingsConnectionInterface+".Update", 0, settings).Err
+}
+
+// DeleteConnection deletes the connection profile with the given path
+func (nm *NetworkManager) DeleteConnection(path dbus.ObjectPath) error {
+	return nm.conn.Object(nmBusName, path).Call(nmSettingsConnectionInterface+".Delete", 0).Err
+}
+
+// GetConnectionSettings returns all settings of the connection profile with the given path.
+// Secrets are only included if withSecrets is true and the caller is allowed to read them.
+func (nm *NetworkManager) GetConnectionSettings(path dbus.ObjectPath, withSecrets bool) (map[string]map[string]dbus.Variant, error) {
+	object := nm.conn.Object(nmBusName, path)
+
+	var settings map[string]map[string]dbus.Variant
+	err := object.Call(nmSettingsConnectionInterface+".GetSettings", 0).Store(&settings)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := settings["connection"]; !ok {
+		settings["connection"] = map[string]dbus.Variant{}
+	}
+	if !withSecrets {
+		return settings, nil
+	}
+
+	for _, settingName := range secretSettings {
+		if _, ok := settings[settingName]; !ok {
+			continue
+		}
+		var secrets map[string]map[string]dbus.Variant
+		err := object.Call(nmSettingsConnectionInterface+".GetSecrets", 0, settingName).Store(&secrets)
+		if err != nil {
+			// profiles without (system owned) secrets or missing permissions
+			continue
+		}
+		for key, value := range secrets[settingName] {
+			settings[settingName][key] = value
+		}
+	}
+	return settings, nil
+}
+
+// profileFromSettings converts NetworkManager connection settings to a portable connection profile
+func profileFromSettings(settings map[string]map[string]dbus.Variant) ConnectionProfile {
+	connectionSettings := settings["connection"]
+	wireless := settings["802-11-wireless"]
+	security := settings["802-11-wireless-security"]
+
+	profile := ConnectionProfile{
+		Name:                variantValue[string](connectionSettings, "id"),
+		SSID:                string(variantValue[[]byte](wireless, "ssid")),
+		Hidden:              variantValue[bool](wireless, "hidden"),
+		Autoconnect:         true,
+		AutoconnectPriority: variantValue[int32](connectionSettings, "autoconnect-priority"),
+		Security:            variantValue[string](security, "key-mgmt"),
+	}
+	if _, ok := connectionSettings["autoconnect"]; ok {
+		profile.Autoconnect = variantValue[bool](connectionSettings, "autoconnect")
+	}
+	if bssid := variantValue[[]byte](wireless, "bssid"); len(bssid) > 0 {
+		profile.BSSID = strings.ToUpper(net.HardwareAddr(bssid).String())
+	}
+	if mode := variantValue[string](wireless, "mode"); mode != WifiModeInfrastructure {
+		profile.Mode = mode
+	}
+	switch variantValue[string](wireless, "band") {
+	case "bg":
+		profile.Band = Band2G
+	case "a":
+		profile.Band = Band5G
+	}
+
+	switch profile.Security {
+	case keyMgmtPSK, keyMgmtSAE:
+		profile.Password = variantValue[string](security, "psk")
+	case keyMgmtWEP:
+		profile.Password = variantValue[string](security, "wep-key0")
+	case keyMgmtEAP, keyMgmtEAPSuiteB:
+		ieee8021x := settings["802-1x"]
+		profile.Password = variantValue[string](ieee8021x, "password")
+		enterprise := &EnterpriseOptions{
+			Phase2:            variantValue[string](ieee8021x, "phase2-auth"),
+			Identity:          variantValue[string](ieee8021x, "identity"),
+			AnonymousIdentity: variantValue[string](ieee8021x, "anonymous-identity"),
+		}
+		if eap := variantValue[[]string](ieee8021x, "eap"); len(eap) > 0 {
+			enterprise.EAP = eap[0]
+		}
+		if caCert := string(variantValue[[]byte](ieee8021x, "ca-cert")); strings.HasPrefix(caCert, "file://") {
+			enterprise.CACert = strings.TrimSuffix(strings.TrimPrefix(caCert, "file://"), "\x00")
+		}
+		profile.Enterprise = enterprise
+	}
+
+	return profile
+}
+
+// settingsFromProfile converts a portable connection profile to NetworkManager connection settings
+func settingsFromProfile(profile ConnectionProfile, connectionUUID string) (map[string]map[string]dbus.Variant, error) {
+	if profile.SSID == "" {
+		return nil, errors.New("missing SSID")
+	}
+	if !slices.Contains(keyMgmts, profile.Security) {
+		return nil, fmt.Errorf("unsupported security: %s", profile.Security)
+	}
+	if !slices.Contains(wifiModes, profile.Mode) {
+		return nil, fmt.Errorf("unsupported mode: %s", profile.Mode)
+	}
+
+	settings, err := buildWifiSettings(ConnectOptions{
+		SSID:       profile.SSID,
+		Password:   profile.Password,
+		Hidden:     profile.Hidden,
+		BSSID:      profile.BSSID,
+		Band:       profile.Band,
+		Enterprise: profile.Enterprise,
+	}, profile.Security, connectionUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := profile.Name
+	if name == "" {
+		name = profile.SSID
+	}
+	settings["connection"]["id"] = dbus.MakeVariant(name)
+	settings["connection"]["autoconnect"] = dbus.MakeVariant(profile.Autoconnect)
+	settings["connection"]["autoconnect-priority"] = dbus.MakeVariant(profile.AutoconnectPriority)
+	if profile.Mode != "" {
+		settings["802-11-wireless"]["mode"] = dbus.MakeVariant(profile.Mode)
+	}
+	if profile.Mode == WifiModeAP {
+		// like the hotspots created by NetworkManager, clients are served by its DHCP server and NAT
+		settings["ipv4"]["method"] = dbus.MakeVariant("shared")
+	}
+	return settings, nil
+}
@@ -0,0 +1,141 @@
+package wifi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFindConnection(t *testing.T) {
+	// GIVEN
+	connections := []Connection{
+		{Name: "Home", UUID: "0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11"},
+		{Name: "0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11", UUID: "5d1c2a8e-3b7f-4e2d-8c61-7a9e0f4b2c33"},
+		{Name: "Office", UUID: "7c9e2b1a-4d3f-4a6b-9e8d-1f2a3b4c5d66"},
+	}
+
+	// WHEN
+	byName, errByName := findConnection(connections, "Office")
+	byUUID, errByUUID := findConnection(connections, "0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11")
+	_, errNotFound := findConnection(connections, "Cafe")
+
+	// THEN
+	assert.NoError(t, errByName)
+	assert.Equal(t, "Office", byName.Name)
+	assert.NoError(t, errByUUID)
+	assert.Equal(t, "Home", byUUID.Name)
+	assert.ErrorIs(t, errNotFound, ErrConnectionNotFound)
+}
+
+func TestConnectionProfile_RoundTrip(t *testing.T) {
+	profiles := []ConnectionProfile{
+		{
+			Name:                "Home",
+			SSID:                "My Network: 5 GHz",
+			BSSID:               "AA:BB:CC:DD:EE:01",
+			Band:                Band5G,
+			Autoconnect:         true,
+			AutoconnectPriority: 10,
+			Security:            keyMgmtSAE,
+			Password:            "secret123",
+		},
+		{
+			Name:     "Cafe",
+			SSID:     "Café",
+			Hidden:   true,
+			Security: keyMgmtNone,
+		},
+		{
+			Name:                "eduroam",
+			SSID:                "eduroam",
+			Autoconnect:         true,
+			AutoconnectPriority: -5,
+			Security:            keyMgmtEAP,
+			Password:            "secret123",
+			Enterprise: &EnterpriseOptions{
+				EAP:               EAPMethodPEAP,
+				Phase2:            "mschapv2",
+				Identity:          "user@example.org",
+				AnonymousIdentity: "anonymous@example.org",
+				CACert:            "/etc/ssl/certs/ca.pem",
+			},
+		},
+		{
+			Name:     "Hotspot",
+			SSID:     "system-control",
+			Mode:     WifiModeAP,
+			Security: keyMgmtPSK,
+			Password: "secret123",
+		},
+	}
+
+	for _, profile := range profiles {
+		t.Run(profile.Name, func(t *testing.T) {
+			// WHEN
+			settings, err := settingsFromProfile(profile, "0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11")
+			assert.NoError(t, err)
+			result := profileFromSettings(settings)
+
+			// THEN
+			assert.Equal(t, profile, result)
+		})
+	}
+}
+
+func TestConnectionProfile_Hotspot(t *testing.T) {
+	// WHEN
+	settings, err := settingsFromProfile(ConnectionProfile{Name: "Hotspot", SSID: "system-control", Mode: WifiModeAP}, "0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11")
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, "ap", settings["802-11-wireless"]["mode"].Value())
+	assert.Equal(t, "shared", settings["ipv4"]["method"].Value())
+}
+
+func TestConnectionProfile_Invalid(t *testing.T) {
+	_, err := settingsFromProfile(ConnectionProfile{Name: "Home"}, "0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11")
+	assert.Error(t, err)
+
+	_, err = settingsFromProfile(ConnectionProfile{Name: "Home", SSID: "Home", Security: "wpa-none"}, "0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11")
+	assert.Error(t, err)
+
+	_, err = settingsFromProfile(ConnectionProfile{Name: "Home", SSID: "Home", Mode: "monitor"}, "0b3a7c2e-6f4c-4d0a-9a57-2c4f7e7f1b11")
+	assert.EqualError(t, err, "unsupported mode: monitor")
+}
+
+func TestConnectionProfiles_Yaml(t *testing.T) {
+	// GIVEN
+	input := `
+connections:
+  - name: Home
+    ssid: My Network
+    autoconnect: true
+    autoconnectPriority: 10
+    security: wpa-psk
+    password: secret123
+  - name: eduroam
+    ssid: eduroam
+    autoconnect: false
+    autoconnectPriority: 0
+    security: wpa-eap
+    password: secret123
+    enterprise:
+      eap: ttls
+      phase2: pap
+      identity: user@example.org
+      caCert: /etc/ssl/certs/ca.pem
+`
+
+	// WHEN
+	var profiles ConnectionProfiles
+	err := yaml.Unmarshal([]byte(input), &profiles)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Len(t, profiles.Connections, 2)
+	assert.Equal(t, "My Network", profiles.Connections[0].SSID)
+	assert.Equal(t, int32(10), profiles.Connections[0].AutoconnectPriority)
+	assert.Equal(t, "ttls", profiles.Connections[1].Enterprise.EAP)
+	assert.Equal(t, "/etc/ssl/certs/ca.pem", profiles.Connections[1].Enterprise.CACert)
+}
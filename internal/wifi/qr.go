@@ -0,0 +1,76 @@
+package wifi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// qrEscaper escapes the characters with a special meaning in the WiFi QR code format
+var qrEscaper = strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, `:`, `\:`, `"`, `\"`)
+
+// QRCodePayload returns the content of a QR code which allows phones to join the given network, using the
+// "WIFI:S:<ssid>;T:<type>;P:<password>;;" format. security is the key management of the network,
+// f.ex. "wpa-psk", "sae" or "none" (WEP), empty for open networks.
+func QRCodePayload(ssid string, security string, password string, hidden bool) (string, error) {
+	var authType string
+	switch security {
+	case keyMgmtNone, keyMgmtOWE:
+		authType = "nopass"
+	case keyMgmtWEP:
+		authType = "WEP"
+	case keyMgmtPSK, keyMgmtSAE:
+		// WPA is understood by all readers, including WPA3 (SAE) networks
+		authType = "WPA"
+	case keyMgmtEAP, keyMgmtEAPSuiteB:
+		return "", errors.New("enterprise networks are not supported by WiFi QR codes")
+	default:
+		return "", fmt.Errorf("unsupported security: %s", security)
+	}
+	if authType != "nopass" && password == "" {
+		return "", errors.New("the password of the network is not available")
+	}
+
+	var builder strings.Builder
+	builder.WriteString("WIFI:S:" + qrEscaper.Replace(ssid) + ";T:" + authType + ";")
+	if authType != "nopass" {
+		builder.WriteString("P:" + qrEscaper.Replace(password) + ";")
+	}
+	if hidden {
+		builder.WriteString("H:true;")
+	}
+	builder.WriteString(";")
+	return builder.String(), nil
+}
+
+// HotspotQRCodePayload returns the QR code content for a hotspot with the given options
+func HotspotQRCodePayload(ssid string, security string, password string) (string, error) {
+	keyMgmt := keyMgmtPSK
+	switch security {
+	case HotspotSecurityWPA3:
+		keyMgmt = keyMgmtSAE
+	case HotspotSecurityOpen:
+		keyMgmt = keyMgmtNone
+	}
+	return QRCodePayload(ssid, keyMgmt, password, false)
+}
+
+// ConnectionQRCodePayload returns the QR code content for the given saved WiFi connection profile.
+// Reading the password of system owned connection profiles may require root privileges.
+func ConnectionQRCodePayload(connection Connection) (string, error) {
+	if connection.Type != "wifi" {
+		return "", fmt.Errorf("%s is not a WiFi connection", connection.Name)
+	}
+	nm, err := SystemNetworkManager()
+	if err != nil {
+		return "", err
+	}
+	settings, err := nm.GetConnectionSettings(dbus.ObjectPath(connection.DBUSPath), true)
+	if err != nil {
+		return "", err
+	}
+	profile := profileFromSettings(settings)
+	return QRCodePayload(profile.SSID, profile.Security, profile.Password, profile.Hidden)
+}
@@ -0,0 +1,44 @@
+package wifi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQRCodePayload(t *testing.T) {
+	// WPA2
+	payload, err := QRCodePayload("My Network", keyMgmtPSK, "secret123", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "WIFI:S:My Network;T:WPA;P:secret123;;", payload)
+
+	// special characters and hidden networks
+	payload, err = QRCodePayload(`Caf\é;1`, keyMgmtSAE, `p:a,s"s`, true)
+	assert.NoError(t, err)
+	assert.Equal(t, `WIFI:S:Caf\\é\;1;T:WPA;P:p\:a\,s\"s;H:true;;`, payload)
+
+	// open networks
+	payload, err = QRCodePayload("Guest", keyMgmtNone, "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "WIFI:S:Guest;T:nopass;;", payload)
+
+	// WEP
+	payload, err = QRCodePayload("Legacy", keyMgmtWEP, "abcde", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "WIFI:S:Legacy;T:WEP;P:abcde;;", payload)
+
+	_, err = QRCodePayload("Office", keyMgmtEAP, "secret", false)
+	assert.Error(t, err)
+	_, err = QRCodePayload("Home", keyMgmtPSK, "", false)
+	assert.Error(t, err)
+}
+
+func TestHotspotQRCodePayload(t *testing.T) {
+	payload, err := HotspotQRCodePayload("laptop", HotspotSecurityWPA3, "abcdefgh")
+	assert.NoError(t, err)
+	assert.Equal(t, "WIFI:S:laptop;T:WPA;P:abcdefgh;;", payload)
+
+	payload, err = HotspotQRCodePayload("laptop", HotspotSecurityOpen, "abcdefgh")
+	assert.NoError(t, err)
+	assert.Equal(t, "WIFI:S:laptop;T:nopass;;", payload)
+}
@@ -0,0 +1,238 @@
+package wifi
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	// channelWidth is the width of a single channel in MHz
+	channelWidth = 20
+	// coChannelWeight and adjacentChannelWeight weigh the interference of other networks for the congestion score
+	coChannelWeight       = 1.0
+	adjacentChannelWeight = 0.5
+)
+
+// ChannelReport contains the congestion of a single channel
+type ChannelReport struct {
+	Band      string
+	Channel   int
+	Frequency int
+	// DFS is true for channels that require radar detection, which are usually not usable for hotspots
+	DFS bool
+	// Candidate is true for channels that are considered for the recommendation
+	Candidate bool
+	// Networks is the number of networks using this channel as their primary channel
+	Networks int
+	// OverlappingBandwidth is the sum of the bandwidth (in MHz) of all networks overlapping with this channel,
+	// f.ex. 30 for a network using it as its primary channel and one with a secondary channel half overlapping it
+	OverlappingBandwidth int
+	// StrongestSignal is the strongest signal (in percent) of all overlapping networks
+	StrongestSignal int
+	// Score is the congestion score of this channel, lower is better
+	Score float64
+}
+
+// BandReport contains the congestion of all channels of a frequency band
+type BandReport struct {
+	Band     string
+	Channels []ChannelReport
+	// Recommended is the least congested candidate channel
+	Recommended ChannelReport
+}
+
+// Survey aggregates the given networks into a per-channel congestion report for the 2.4, 5 and 6 GHz bands
+func Survey(networks []WiFiNetwork) []BandReport {
+	return []BandReport{
+		surveyBand(Band2G, channels2G(), networks),
+		surveyBand(Band5G, channels5G(), networks),
+		surveyBand(Band6G, channels6G(), networks),
+	}
+}
+
+func surveyBand(band string, channels []ChannelReport, networks []WiFiNetwork) BandReport {
+	report := BandReport{Band: band}
+	for _, channel := range channels {
+		channel.Band = band
+		low, high := channelRange(channel.Frequency)
+		for _, network := range networks {
+			frequency := parseMHz(network.Frequency)
+			if !IsFrequencyInBand(frequency, band) {
+				continue
+			}
+			networkLow, networkHigh := networkRange(frequency, parseMHz(network.Bandwidth))
+			if networkHigh <= low || networkLow >= high {
+				continue
+			}
+
+			overlap := min(networkHigh, high) - max(networkLow, low)
+			channel.OverlappingBandwidth += overlap
+			channel.StrongestSignal = max(channel.StrongestSignal, network.Signal)
+			weight := adjacentChannelWeight
+			if frequency == channel.Frequency {
+				channel.Networks++
+				weight = coChannelWeight
+			}
+			// networks only partially overlapping with the channel interfere less
+			weight *= float64(overlap) / channelWidth
+			channel.Score += weight * float64(network.Signal) / 100
+		}
+
+		report.Channels = append(report.Channels, channel)
+		if channel.Candidate && (!report.Recommended.Candidate || channel.Score < report.Recommended.Score) {
+			report.Recommended = channel
+		}
+	}
+	return report
+}
+
+// networkRange returns the frequency range occupied by a network with the given primary channel and bandwidth
+func networkRange(frequency int, bandwidth int) (low int, high int) {
+	if bandwidth < channelWidth {
+		bandwidth = channelWidth
+	}
+	if IsFrequencyInBand(frequency, Band2G) {
+		// the secondary channel may be above or below the primary channel
+		extension := bandwidth - channelWidth
+		return frequency - channelWidth/2 - extension, frequency + channelWidth/2 + extension
+	}
+
+	// bonded channels are aligned to fixed blocks in the 5 and 6 GHz bands
+	base := 5945
+	switch {
+	case IsFrequencyInBand(frequency, Band5G) && frequency >= 5735:
+		base = 5735
+	case IsFrequencyInBand(frequency, Band5G):
+		base = 5170
+	}
+	low = base + (frequency-channelWidth/2-base)/bandwidth*bandwidth
+	return low, low + bandwidth
+}
+
+func channelRange(frequency int) (low int, high int) {
+	return frequency - channelWidth/2, frequency + channelWidth/2
+}
+
+// parseMHz parses values like "5180 MHz", returning 0 if the value is unknown
+func parseMHz(value string) int {
+	result, _ := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(value, "MHz")))
+	return result
+}
+
+func channels2G() []ChannelReport {
+	var result []ChannelReport
+	for channel := 1; channel <= 13; channel++ {
+		result = append(result, ChannelReport{
+			Channel:   channel,
+			Frequency: 2407 + channel*5,
+			// the only non-overlapping channels
+			Candidate: channel == 1 || channel == 6 || channel == 11,
+		})
+	}
+	return result
+}
+
+func channels5G() []ChannelReport {
+	var channels []int
+	for channel := 36; channel <= 144; channel += 4 {
+		channels = append(channels, channel)
+	}
+	for channel := 149; channel <= 165; channel += 4 {
+		channels = append(channels, channel)
+	}
+
+	var result []ChannelReport
+	for _, channel := range channels {
+		dfs := channel >= 52 && channel <= 144
+		result = append(result, ChannelReport{
+			Channel:   channel,
+			Frequency: 5000 + channel*5,
+			DFS:       dfs,
+			Candidate: !dfs,
+		})
+	}
+	return result
+}
+
+func channels6G() []ChannelReport {
+	var result []ChannelReport
+	for channel := 1; channel <= 233; channel += 4 {
+		result = append(result, ChannelReport{
+			Channel:   channel,
+			Frequency: 5950 + channel*5,
+			// preferred scanning channels, which clients scan first
+			Candidate: channel%16 == 5,
+		})
+	}
+	return result
+}
+
+// Rescan requests a new scan on all WiFi devices and waits until it is finished or the timeout is reached
+func Rescan(timeout time.Duration) error {
+	nm, err := SystemNetworkManager()
+	if err != nil {
+		return err
+	}
+	return nm.Rescan(timeout)
+}
+
+// Rescan requests a new scan on all WiFi devices and waits until it is finished or the timeout is reached
+func (nm *NetworkManager) Rescan(timeout time.Duration) error {
+	devicePaths, err := nm.getDevicePaths()
+	if err != nil {
+		return err
+	}
+
+	lastScans := map[dbus.ObjectPath]int64{}
+	for _, devicePath := range devicePaths {
+		properties, err := nm.getProperties(devicePath, nmDeviceInterface)
+		if err != nil {
+			return err
+		}
+		if variantValue[uint32](properties, "DeviceType") != nmDeviceTypeWifi {
+			continue
+		}
+		wirelessProperties, err := nm.getProperties(devicePath, nmWirelessInterface)
+		if err != nil {
+			return err
+		}
+		err = nm.conn.Object(nmBusName, devicePath).Call(nmWirelessInterface+".RequestScan", 0, map[string]dbus.Variant{}).Err
+		if err != nil {
+			return fmt.Errorf("failed to request scan: %w", err)
+		}
+		lastScans[devicePath] = variantValue[int64](wirelessProperties, "LastScan")
+	}
+
+	deadline := time.Now().Add(timeout)
+	for len(lastScans) > 0 && time.Now().Before(deadline) {
+		time.Sleep(500 * time.Millisecond)
+		for devicePath, lastScan := range lastScans {
+			wirelessProperties, err := nm.getProperties(devicePath, nmWirelessInterface)
+			if err != nil || variantValue[int64](wirelessProperties, "LastScan") != lastScan {
+				delete(lastScans, devicePath)
+			}
+		}
+	}
+	return nil
+}
+
+// RecommendChannel returns the least congested channel of the given band, based on the currently visible networks
+func RecommendChannel(band string) (int, error) {
+	networks, err := GetNetworks()
+	if err != nil {
+		return 0, err
+	}
+	reports := Survey(networks)
+	index := slices.IndexFunc(reports, func(report BandReport) bool {
+		return report.Band == band
+	})
+	if index < 0 {
+		return 0, fmt.Errorf("unsupported band: %s", band)
+	}
+	return reports[index].Recommended.Channel, nil
+}
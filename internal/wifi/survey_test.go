@@ -0,0 +1,68 @@
+package wifi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func findChannelReport(t *testing.T, reports []BandReport, band string, channel int) ChannelReport {
+	for _, report := range reports {
+		if report.Band != band {
+			continue
+		}
+		for _, channelReport := range report.Channels {
+			if channelReport.Channel == channel {
+				return channelReport
+			}
+		}
+	}
+	t.Fatalf("channel %d not found in band %s", channel, band)
+	return ChannelReport{}
+}
+
+func TestSurvey(t *testing.T) {
+	// GIVEN
+	networks := []WiFiNetwork{
+		{SSID: "A", Channel: 1, Frequency: "2412 MHz", Bandwidth: "20 MHz", Signal: 80},
+		{SSID: "B", Channel: 1, Frequency: "2412 MHz", Bandwidth: "20 MHz", Signal: 40},
+		{SSID: "C", Channel: 6, Frequency: "2437 MHz", Bandwidth: "20 MHz", Signal: 30},
+		{SSID: "D", Channel: 11, Frequency: "2462 MHz", Bandwidth: "--", Signal: 20},
+		{SSID: "E", Channel: 36, Frequency: "5180 MHz", Bandwidth: "80 MHz", Signal: 70},
+		{SSID: "F", Channel: 149, Frequency: "5745 MHz", Bandwidth: "40 MHz", Signal: 50},
+		{SSID: "G", Channel: 5, Frequency: "5975 MHz", Bandwidth: "160 MHz", Signal: 60},
+	}
+
+	// WHEN
+	reports := Survey(networks)
+
+	// THEN
+	assert.Len(t, reports, 3)
+
+	channel1 := findChannelReport(t, reports, Band2G, 1)
+	assert.Equal(t, 2, channel1.Networks)
+	assert.Equal(t, 40, channel1.OverlappingBandwidth)
+	assert.Equal(t, 80, channel1.StrongestSignal)
+	assert.InDelta(t, 1.2, channel1.Score, 0.001)
+
+	channel3 := findChannelReport(t, reports, Band2G, 3)
+	assert.Equal(t, 0, channel3.Networks)
+	// A and B overlap half of channel 3, C a quarter
+	assert.Equal(t, 25, channel3.OverlappingBandwidth)
+	assert.InDelta(t, 0.5*(0.5*0.8+0.5*0.4+0.25*0.3), channel3.Score, 0.001)
+
+	// 80 MHz on 36 covers 36-48
+	assert.Equal(t, 20, findChannelReport(t, reports, Band5G, 48).OverlappingBandwidth)
+	assert.Equal(t, 0, findChannelReport(t, reports, Band5G, 52).OverlappingBandwidth)
+	// 40 MHz on 149 covers 149-153
+	assert.Equal(t, 20, findChannelReport(t, reports, Band5G, 153).OverlappingBandwidth)
+	assert.Equal(t, 0, findChannelReport(t, reports, Band5G, 157).OverlappingBandwidth)
+	assert.True(t, findChannelReport(t, reports, Band5G, 100).DFS)
+	// 160 MHz on 5 covers 1-29
+	assert.Equal(t, 20, findChannelReport(t, reports, Band6G, 29).OverlappingBandwidth)
+	assert.Equal(t, 0, findChannelReport(t, reports, Band6G, 33).OverlappingBandwidth)
+
+	assert.Equal(t, 11, reports[0].Recommended.Channel)
+	assert.Equal(t, 157, reports[1].Recommended.Channel)
+	assert.Equal(t, 37, reports[2].Recommended.Channel)
+}
@@ -0,0 +1,92 @@
+package wifi
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// vpnConnectionTypes are the connection types considered to be VPNs
+var vpnConnectionTypes = []string{"vpn", "wireguard"}
+
+// IsVPNConnection returns true if the given connection profile is a NetworkManager VPN or WireGuard connection
+func IsVPNConnection(connection Connection) bool {
+	return slices.Contains(vpnConnectionTypes, connection.Type)
+}
+
+// GetVPNConnections returns all VPN and WireGuard connection profiles
+func GetVPNConnections() ([]Connection, error) {
+	connections, err := GetConnections()
+	if err != nil {
+		return nil, err
+	}
+	return filterVPNConnections(connections), nil
+}
+
+// FindVPNConnection returns the VPN or WireGuard connection profile with the given name or UUID
+func FindVPNConnection(nameOrUUID string) (Connection, error) {
+	connections, err := GetVPNConnections()
+	if err != nil {
+		return Connection{}, err
+	}
+	return findConnection(connections, nameOrUUID)
+}
+
+func filterVPNConnections(connections []Connection) []Connection {
+	return slices.DeleteFunc(slices.Clone(connections), func(connection Connection) bool {
+		return !IsVPNConnection(connection)
+	})
+}
+
+// ActivateVPN activates the given VPN connection profile and waits until it is established.
+// If exclusive is true, all other active VPN connections are deactivated first.
+func ActivateVPN(connection Connection, exclusive bool, timeout time.Duration) error {
+	nm, err := SystemNetworkManager()
+	if err != nil {
+		return err
+	}
+
+	if exclusive {
+		connections, err := GetVPNConnections()
+		if err != nil {
+			return err
+		}
+		for _, other := range connections {
+			if other.UUID == connection.UUID || other.Active != "yes" {
+				continue
+			}
+			err = nm.DeactivateConnection(dbus.ObjectPath(other.ActivePath))
+			if err != nil {
+				return fmt.Errorf("failed to deactivate %s: %w", other.Name, err)
+			}
+		}
+	}
+
+	if connection.Active == "yes" {
+		return nil
+	}
+	err = nm.activate(dbus.ObjectPath(connection.DBUSPath), "/", "/", timeout)
+	if err != nil {
+		return fmt.Errorf("failed to activate %s: %w", connection.Name, err)
+	}
+	return nil
+}
+
+// DeactivateVPN deactivates the given VPN connection profile, if it is active
+func DeactivateVPN(connection Connection) error {
+	if connection.Active != "yes" {
+		return nil
+	}
+	nm, err := SystemNetworkManager()
+	if err != nil {
+		return err
+	}
+	return nm.DeactivateConnection(dbus.ObjectPath(connection.ActivePath))
+}
+
+// DeactivateConnection deactivates the active connection with the given path
+func (nm *NetworkManager) DeactivateConnection(activePath dbus.ObjectPath) error {
+	return nm.conn.Object(nmBusName, nmObjectPath).Call(nmInterface+".DeactivateConnection", 0, activePath).Err
+}
@@ -0,0 +1,42 @@
+package wifi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterVPNConnections(t *testing.T) {
+	// GIVEN
+	connections := []Connection{
+		{Name: "Home", Type: "wifi"},
+		{Name: "Office VPN", Type: "vpn"},
+		{Name: "Wired", Type: "ethernet"},
+		{Name: "wg0", Type: "wireguard"},
+	}
+
+	// WHEN
+	result := filterVPNConnections(connections)
+
+	// THEN
+	assert.Equal(t, []Connection{
+		{Name: "Office VPN", Type: "vpn"},
+		{Name: "wg0", Type: "wireguard"},
+	}, result)
+	assert.Len(t, connections, 4)
+}
+
+func TestFilterVPNConnections_NetworkManager(t *testing.T) {
+	// GIVEN
+	nm := startFakeNetworkManager(t)
+	connections, err := nm.GetConnections()
+	assert.NoError(t, err)
+
+	// WHEN
+	result := filterVPNConnections(connections)
+
+	// THEN
+	assert.Len(t, result, 1)
+	assert.Equal(t, "Office VPN", result[0].Name)
+	assert.False(t, IsVPNConnection(connections[0]))
+}